@@ -19,14 +19,31 @@ import {
   installNpmDependencies,
 } from "../src/core/plugin-generator.js";
 import { PluginContext } from "../src/types/index.js";
-import { PLUGIN_TYPES } from "../src/config/constants.js";
+import { PLUGIN_TYPES, TESTKIT } from "../src/config/constants.js";
 import {
   discoverPlugins,
   checkInstallationStatus,
   installPlugins,
   uninstallPlugins,
   PluginInfo,
+  InstallOptions,
 } from "../src/core/plugin-manager.js";
+import {
+  isModuleSpec,
+  modulePluginInfo,
+  resolvePluginModule,
+  ResolveOptions,
+} from "../src/core/plugin-module.js";
+import { resolveModulePath } from "../src/core/module-path.js";
+import { resolveAnswerVersion } from "../src/core/answer-version.js";
+import {
+  recordPluginTemplate,
+  upgradePlugin,
+  upgradeTestkit,
+  UpgradeOptions,
+  UpgradeResult,
+} from "../src/core/plugin-upgrade.js";
+import { readTemplateLock } from "../src/core/template-lock.js";
 import { executeCommand } from "../src/utils/exec.js";
 import { validateAnswerProjectPath } from "../src/utils/validators.js";
 import { handleError } from "../src/core/error-handler.js";
@@ -46,7 +63,10 @@ const logger = getLogger();
 /**
  * Create plugin command
  */
-const createPlugin = async (pluginName?: string): Promise<void> => {
+const createPlugin = async (
+  pluginName?: string,
+  modulePath?: string
+): Promise<void> => {
   try {
     // Collect user input
     const answers = await collectPluginInfo(pluginName);
@@ -74,8 +94,16 @@ const createPlugin = async (pluginName?: string): Promise<void> => {
       ...nameInfo,
       targetPath,
       answerProjectPath: answers.answerProjectPath,
+      modulePath: await resolveModulePath(
+        answers.answerProjectPath,
+        modulePath
+      ),
+      answerVersion: resolveAnswerVersion(answers.answerProjectPath),
       pluginType: answers.pluginType,
       backendPluginType: answers.backendPluginType,
+      backendPluginTypes: answers.backendPluginTypes,
+      templateVariant: answers.templateVariant,
+      mixins: answers.mixins,
       standardPluginType: answers.standardPluginType,
       routePath: answers.routePath,
     };
@@ -100,7 +128,16 @@ const createPlugin = async (pluginName?: string): Promise<void> => {
       generateStandardUIPlugin(context);
       generateInfoYaml(context);
       spinner.succeed("Standard UI Plugin files generated");
+    }
+
+    // Generate README
+    generateReadme(context);
 
+    // Record the templates and inputs the files were rendered from, the
+    // base of upgrades
+    recordPluginTemplate(context);
+
+    if (context.pluginType === PLUGIN_TYPES.STANDARD_UI) {
       spinner.start("Installing npm dependencies...");
       await installNpmDependencies(context);
       spinner.succeed("Dependencies installed");
@@ -111,9 +148,6 @@ const createPlugin = async (pluginName?: string): Promise<void> => {
     await initGoModule(context);
     spinner.succeed("Go module initialized");
 
-    // Generate README
-    generateReadme(context);
-
     spinner.succeed(
       `Plugin "${nameInfo.packageName}" created successfully at ${targetPath}`
     );
@@ -180,7 +214,8 @@ const listPlugins = async (answerProjectPath?: string): Promise<void> => {
  */
 const installPluginsCommand = async (
   pluginNames: string[],
-  answerProjectPath?: string
+  answerProjectPath?: string,
+  options: InstallOptions & ResolveOptions = {}
 ): Promise<void> => {
   try {
     const projectPath = answerProjectPath || process.cwd();
@@ -209,6 +244,13 @@ const installPluginsCommand = async (
       );
     }
 
+    // Resolve published plugins, given as module@version, from the proxy
+    for (const spec of pluginNames.filter(isModuleSpec)) {
+      spinner.start(`Resolving ${spec}...`);
+      pluginsToInstall.push(await resolvePluginModule(spec, options));
+      spinner.stop();
+    }
+
     if (pluginsToInstall.length === 0) {
       ora().warn("No plugins found to install");
       return;
@@ -217,18 +259,20 @@ const installPluginsCommand = async (
     spinner.start(`Installing ${pluginsToInstall.length} plugin(s)...`);
 
     try {
-      installPlugins(pluginsToInstall, projectPath);
+      installPlugins(pluginsToInstall, projectPath, options);
 
       // Load configuration
       const config = loadConfig(projectPath);
 
-      // Run go mod tidy
-      spinner.text = "Running go mod tidy...";
-      await executeCommand(config.commands.goModTidy, {
-        cwd: projectPath,
-        timeout: config.timeouts.goModTidy,
-        retries: config.retries.goModTidy,
-      });
+      // Run go mod tidy, go.mod is left alone in workspace mode
+      if (!options.workspace) {
+        spinner.text = "Running go mod tidy...";
+        await executeCommand(config.commands.goModTidy, {
+          cwd: projectPath,
+          timeout: config.timeouts.goModTidy,
+          retries: config.retries.goModTidy,
+        });
+      }
 
       // Merge plugin i18n resources into Answer data
       spinner.text = "Merging plugin i18n resources...";
@@ -251,7 +295,11 @@ const installPluginsCommand = async (
       );
 
       pluginsToInstall.forEach((plugin) => {
-        console.log(`  ✅ ${plugin.name}`);
+        console.log(
+          plugin.modulePath
+            ? `  ✅ ${plugin.modulePath}@${plugin.version}`
+            : `  ✅ ${plugin.name}`
+        );
       });
     } catch (error: any) {
       spinner.fail(`Failed to install plugins: ${error.message}`);
@@ -267,7 +315,8 @@ const installPluginsCommand = async (
  */
 const uninstallPluginsCommand = async (
   pluginNames: string[],
-  answerProjectPath?: string
+  answerProjectPath?: string,
+  options: InstallOptions = {}
 ): Promise<void> => {
   try {
     const projectPath = answerProjectPath || process.cwd();
@@ -290,8 +339,12 @@ const uninstallPluginsCommand = async (
       // Uninstall all installed plugins
       pluginsToUninstall = pluginsWithStatus.filter((p) => p.installed);
     } else {
-      // Uninstall specified plugins
-      pluginsToUninstall = pluginsWithStatus.filter(
+      // Uninstall specified plugins, published ones by module path
+      const publishedPlugins = checkInstallationStatus(
+        pluginNames.filter(isModuleSpec).map(modulePluginInfo),
+        projectPath
+      );
+      pluginsToUninstall = [...pluginsWithStatus, ...publishedPlugins].filter(
         (p) => pluginNames.includes(p.name) && p.installed
       );
     }
@@ -304,18 +357,20 @@ const uninstallPluginsCommand = async (
     spinner.start(`Uninstalling ${pluginsToUninstall.length} plugin(s)...`);
 
     try {
-      uninstallPlugins(pluginsToUninstall, projectPath);
+      uninstallPlugins(pluginsToUninstall, projectPath, options);
 
       // Load configuration
       const config = loadConfig(projectPath);
 
-      // Run go mod tidy
-      spinner.text = "Running go mod tidy...";
-      await executeCommand(config.commands.goModTidy, {
-        cwd: projectPath,
-        timeout: config.timeouts.goModTidy,
-        retries: config.retries.goModTidy,
-      });
+      // Run go mod tidy, go.mod is left alone in workspace mode
+      if (!options.workspace) {
+        spinner.text = "Running go mod tidy...";
+        await executeCommand(config.commands.goModTidy, {
+          cwd: projectPath,
+          timeout: config.timeouts.goModTidy,
+          retries: config.retries.goModTidy,
+        });
+      }
 
       logger.debug(`Uninstalled ${pluginsToUninstall.length} plugin(s)`);
 
@@ -335,6 +390,121 @@ const uninstallPluginsCommand = async (
   }
 };
 
+/**
+ * Print what an upgrade did to the files of a plugin or of the testkit
+ */
+const printUpgradeResult = (name: string, result: UpgradeResult): void => {
+  const sections: [
+    Exclude<keyof UpgradeResult, "path" | "fromVersion" | "toVersion">,
+    string
+  ][] = [
+    ["updated", "Updated"],
+    ["merged", "Merged"],
+    ["conflicts", "Conflicts"],
+    ["added", "Added"],
+    ["removed", "Removed"],
+    ["skipped", "Skipped, deleted or changed by you"],
+  ];
+
+  console.log(
+    `\n${name} (templates ${result.fromVersion} → ${result.toVersion})`
+  );
+  let changed = false;
+  for (const [key, label] of sections) {
+    const files = result[key];
+    if (files.length > 0) {
+      changed = true;
+      console.log(
+        `  ${key === "conflicts" ? "⚠️ " : "✅"} ${label}: ${files.join(", ")}`
+      );
+    }
+  }
+  if (!changed) {
+    console.log("  ✅ Up to date");
+  }
+};
+
+/**
+ * Upgrade plugins command
+ */
+const upgradePluginsCommand = async (
+  pluginNames: string[],
+  answerProjectPath?: string,
+  options: UpgradeOptions = {}
+): Promise<void> => {
+  try {
+    const projectPath = answerProjectPath || process.cwd();
+    const validation = validateAnswerProjectPath(projectPath);
+
+    if (validation !== true) {
+      ora().fail(validation);
+      process.exit(1);
+    }
+
+    const spinner = ora("Discovering plugins...").start();
+    const allPlugins = discoverPlugins(projectPath);
+    spinner.stop();
+
+    // Filter plugins to upgrade, all of them defaults to those with a lock
+    const pluginsToUpgrade =
+      pluginNames.length === 0
+        ? allPlugins.filter((p) => readTemplateLock(p.path))
+        : allPlugins.filter((p) => pluginNames.includes(p.name));
+
+    const unknown = pluginNames.filter(
+      (name) => !allPlugins.some((p) => p.name === name)
+    );
+    if (unknown.length > 0) {
+      ora().warn(`Plugins not found: ${unknown.join(", ")}`);
+    }
+    if (pluginsToUpgrade.length === 0) {
+      ora().warn("No plugins found to upgrade");
+      return;
+    }
+
+    spinner.start(`Upgrading ${pluginsToUpgrade.length} plugin(s)...`);
+    const results: [string, UpgradeResult][] = [];
+
+    try {
+      // The testkit first, the plugins build against it
+      spinner.text = "Upgrading the testkit...";
+      const testkit = await upgradeTestkit(projectPath, options);
+      if (testkit) {
+        results.push([TESTKIT.PACKAGE, testkit]);
+      }
+
+      for (const plugin of pluginsToUpgrade) {
+        spinner.text = `Upgrading ${plugin.name}...`;
+        results.push([
+          plugin.name,
+          await upgradePlugin(plugin.path, projectPath, options),
+        ]);
+      }
+
+      logger.debug(`Upgraded ${pluginsToUpgrade.length} plugin(s)`);
+
+      spinner.succeed(
+        `Successfully upgraded ${pluginsToUpgrade.length} plugin(s)`
+      );
+    } catch (error: any) {
+      spinner.fail(`Failed to upgrade plugins: ${error.message}`);
+      results.forEach(([name, result]) => printUpgradeResult(name, result));
+      throw error;
+    }
+
+    results.forEach(([name, result]) => printUpgradeResult(name, result));
+
+    if (results.some(([, result]) => result.conflicts.length > 0)) {
+      console.log("");
+      ora().warn(
+        "Resolve the conflict markers, then run go build ./... in the plugins with Go conflicts"
+      );
+    }
+  } catch (error) {
+    handleError(error);
+  }
+};
+
 // Setup yargs commands
 // Detect command name from process.argv[1] to support both aliases
 const commandName = process.argv[1]?.includes('create-answer-plugin') 
@@ -360,9 +530,17 @@ yargs(hideBin(process.argv))
         type: "string",
         describe: "Path to Answer project",
       });
+      yargs.option("module-path", {
+        type: "string",
+        describe:
+          "Module path the plugin lives under, e.g. git.example.com/acme/answer-plugins",
+      });
     },
     async (argv) => {
-      await createPlugin(argv.pluginName as string | undefined);
+      await createPlugin(
+        argv.pluginName as string | undefined,
+        argv.modulePath as string | undefined
+      );
     }
   )
 
@@ -389,7 +567,8 @@ yargs(hideBin(process.argv))
     (yargs) => {
       yargs.positional("plugins", {
         type: "string",
-        describe: "Plugin names to install",
+        describe:
+          "Plugin names to install, or published plugins as module@version",
         array: true,
         default: [],
       });
@@ -398,11 +577,26 @@ yargs(hideBin(process.argv))
         type: "string",
         describe: "Path to Answer project",
       });
+      yargs.option("workspace", {
+        alias: "w",
+        type: "boolean",
+        describe: "Add the plugins to go.work, leaving go.mod untouched",
+        default: false,
+      });
+      yargs.option("proxy", {
+        type: "string",
+        describe:
+          "GOPROXY to resolve published plugins from, e.g. file:///path/to/proxy",
+      });
     },
     async (argv) => {
       await installPluginsCommand(
         (argv.plugins as string[]) || [],
-        argv.path as string | undefined
+        argv.path as string | undefined,
+        {
+          workspace: argv.workspace as boolean,
+          proxy: argv.proxy as string | undefined,
+        }
       );
     }
   )
@@ -414,7 +608,8 @@ yargs(hideBin(process.argv))
     (yargs) => {
       yargs.positional("plugins", {
         type: "string",
-        describe: "Plugin names to uninstall",
+        describe:
+          "Plugin names to uninstall, or module paths of published ones",
         array: true,
         default: [],
       });
@@ -423,11 +618,50 @@ yargs(hideBin(process.argv))
         type: "string",
         describe: "Path to Answer project",
       });
+      yargs.option("workspace", {
+        alias: "w",
+        type: "boolean",
+        describe: "Remove the plugins from go.work, leaving go.mod untouched",
+        default: false,
+      });
     },
     async (argv) => {
       await uninstallPluginsCommand(
         (argv.plugins as string[]) || [],
-        argv.path as string | undefined
+        argv.path as string | undefined,
+        { workspace: argv.workspace as boolean }
+      );
+    }
+  )
+
+  // Upgrade command
+  .command(
+    "upgrade [plugins...]",
+    "Upgrade plugins to the current templates (defaults to all plugins with a lock)",
+    (yargs) => {
+      yargs.positional("plugins", {
+        type: "string",
+        describe: "Plugin names to upgrade",
+        array: true,
+        default: [],
+      });
+      yargs.option("path", {
+        alias: "p",
+        type: "string",
+        describe: "Path to Answer project",
+      });
+      yargs.option("build-check", {
+        type: "boolean",
+        describe:
+          "Build the Go packages after merging and roll back if they don't build, --no-build-check keeps the merge",
+        default: true,
+      });
+    },
+    async (argv) => {
+      await upgradePluginsCommand(
+        (argv.plugins as string[]) || [],
+        argv.path as string | undefined,
+        { buildCheck: argv.buildCheck as boolean }
       );
     }
   )
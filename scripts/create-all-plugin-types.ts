@@ -25,10 +25,18 @@ import {
 } from "../src/core/plugin-generator.js";
 import { PluginContext } from "../src/types/index.js";
 import { PLUGIN_TYPES, ANSWER_PATHS } from "../src/config/constants.js";
+import { loadConfig } from "../src/config/config.js";
+import { resolveModulePath } from "../src/core/module-path.js";
+import { resolveAnswerVersion } from "../src/core/answer-version.js";
+import { recordPluginTemplate } from "../src/core/plugin-upgrade.js";
 
 const ANSWER_PROJECT_PATH = process.argv[2] || "/Users/robin/Projects/answer";
 const PLUGINS_PATH = path.resolve(ANSWER_PROJECT_PATH, ANSWER_PATHS.PLUGINS);
 
+// Module path the plugins live under, resolved once the project is checked
+let MODULE_PATH = "";
+let ANSWER_VERSION = "";
+
 // Backend Plugin types
 const BACKEND_PLUGINS = [
   { type: "connector", name: "demo-connector" },
@@ -38,6 +46,24 @@ const BACKEND_PLUGINS = [
   { type: "user-center", name: "demo-user-center" },
   { type: "notification", name: "demo-notification" },
   { type: "reviewer", name: "demo-reviewer" },
+  { type: "reviewer", name: "demo-reviewer-rules", variant: "rules" },
+  { type: "reviewer", name: "demo-reviewer-api", variant: "api" },
+  { type: "reviewer", name: "demo-reviewer-spam", variant: "spam" },
+  { type: "importer", name: "demo-importer" },
+  { type: "kv-storage", name: "demo-kv-storage" },
+  { type: "mcp-tool", name: "demo-mcp-tool" },
+  { type: "filter", name: "demo-filter" },
+  { type: "notification", name: "demo-notification-kv", mixins: ["kv"] },
+  {
+    type: "notification",
+    name: "demo-notification-user-config",
+    mixins: ["user-config", "kv"],
+  },
+  {
+    type: "connector",
+    name: "demo-connector-user-center",
+    types: ["user-center"],
+  },
 ];
 
 // Standard UI Plugin types
@@ -45,7 +71,21 @@ const STANDARD_UI_PLUGINS = [
   { type: "editor", name: "demo-editor", routePath: undefined },
   { type: "route", name: "demo-route", routePath: "/demo-route" },
   { type: "captcha", name: "demo-captcha", routePath: undefined },
+  {
+    type: "captcha",
+    name: "demo-captcha-siteverify",
+    routePath: undefined,
+    variant: "siteverify",
+  },
   { type: "render", name: "demo-render", routePath: undefined },
+  {
+    type: "render",
+    name: "demo-render-ssr",
+    routePath: undefined,
+    variant: "ssr",
+  },
+  { type: "embed", name: "demo-embed", routePath: undefined },
+  { type: "sidebar", name: "demo-sidebar", routePath: undefined },
 ];
 
 interface PluginResult {
@@ -62,7 +102,10 @@ const results: PluginResult[] = [];
  */
 async function createBackendPlugin(
   type: string,
-  name: string
+  name: string,
+  variant?: string,
+  mixins?: string[],
+  types?: string[]
 ): Promise<boolean> {
   try {
     const spinner = ora(`Creating Backend Plugin: ${type} (${name})`).start();
@@ -86,16 +129,22 @@ async function createBackendPlugin(
       ...nameInfo,
       targetPath: pluginPath,
       answerProjectPath: ANSWER_PROJECT_PATH,
+      modulePath: MODULE_PATH,
+      answerVersion: ANSWER_VERSION,
       pluginType: PLUGIN_TYPES.BACKEND,
       backendPluginType: type as any,
+      backendPluginTypes: types as any,
+      templateVariant: variant as any,
+      mixins: mixins as any,
     };
 
     // Create plugin
     createPluginDirectory(context);
     generateI18n(context);
     generateBackendPlugin(context);
-    await initGoModule(context);
     generateReadme(context);
+    recordPluginTemplate(context);
+    await initGoModule(context);
 
     spinner.succeed(`Created: ${nameInfo.packageName}`);
     results.push({
@@ -123,7 +172,8 @@ async function createBackendPlugin(
 async function createStandardUIPlugin(
   type: string,
   name: string,
-  routePath?: string
+  routePath?: string,
+  variant?: string
 ): Promise<boolean> {
   try {
     const spinner = ora(
@@ -149,8 +199,11 @@ async function createStandardUIPlugin(
       ...nameInfo,
       targetPath: pluginPath,
       answerProjectPath: ANSWER_PROJECT_PATH,
+      modulePath: MODULE_PATH,
+      answerVersion: ANSWER_VERSION,
       pluginType: PLUGIN_TYPES.STANDARD_UI,
       standardPluginType: type as any,
+      templateVariant: variant as any,
       routePath,
     };
 
@@ -159,9 +212,10 @@ async function createStandardUIPlugin(
     generateI18n(context);
     generateStandardUIPlugin(context);
     generateInfoYaml(context);
+    generateReadme(context);
+    recordPluginTemplate(context);
     await installNpmDependencies(context);
     await initGoModule(context);
-    generateReadme(context);
 
     spinner.succeed(`Created: ${nameInfo.packageName}`);
     results.push({
@@ -198,10 +252,20 @@ async function createAllPlugins(): Promise<void> {
     process.exit(1);
   }
 
+  loadConfig(ANSWER_PROJECT_PATH);
+  MODULE_PATH = await resolveModulePath(ANSWER_PROJECT_PATH);
+  ANSWER_VERSION = resolveAnswerVersion(ANSWER_PROJECT_PATH);
+
   // Create Backend Plugins
   console.log("📦 Creating Backend Plugins...\n");
   for (const plugin of BACKEND_PLUGINS) {
-    await createBackendPlugin(plugin.type, plugin.name);
+    await createBackendPlugin(
+      plugin.type,
+      plugin.name,
+      plugin.variant,
+      plugin.mixins,
+      plugin.types
+    );
     // Small delay to avoid overwhelming the system
     await new Promise((resolve) => setTimeout(resolve, 300));
   }
@@ -209,7 +273,12 @@ async function createAllPlugins(): Promise<void> {
   // Create Standard UI Plugins
   console.log("\n📦 Creating Standard UI Plugins...\n");
   for (const plugin of STANDARD_UI_PLUGINS) {
-    await createStandardUIPlugin(plugin.type, plugin.name, plugin.routePath);
+    await createStandardUIPlugin(
+      plugin.type,
+      plugin.name,
+      plugin.routePath,
+      plugin.variant
+    );
     // Small delay to avoid overwhelming the system
     await new Promise((resolve) => setTimeout(resolve, 300));
   }
@@ -0,0 +1,164 @@
+#!/usr/bin/env tsx
+
+/*
+ * Generate Test Script
+ *
+ * This script generates a Backend Plugin of every type, and of every type
+ * combined with every mix-in the type allows, into a temporary Answer
+ * project. Each one is built with go build, the plain types are checked with
+ * tools/pluginvet too. Go modules come from GOPROXY as usual, point it at a
+ * local cache to run offline.
+ *
+ * Usage:
+ *   tsx scripts/test-generate.ts
+ */
+
+import fs from 'fs'
+import os from 'os'
+import path from 'path'
+import { execFileSync } from 'child_process'
+import { fileURLToPath } from 'url'
+import { transformPluginName } from '../src/utils/name-transformer.js'
+import {
+  createPluginDirectory,
+  generateBackendPlugin,
+  generateI18n,
+  generateReadme,
+  initGoModule,
+} from '../src/core/plugin-generator.js'
+import { PluginContext } from '../src/types/index.js'
+import {
+  ANSWER_PATHS,
+  BACKEND_MIXIN_TYPES,
+  BACKEND_PLUGIN_TYPES,
+  BackendMixin,
+  BackendPluginType,
+  DEFAULT_MODULE_PATH,
+  PLUGIN_TYPES,
+  TOOL_PATHS,
+} from '../src/config/constants.js'
+import { DEFAULT_ANSWER_VERSION } from '../src/config/compatibility.js'
+import { loadConfig } from '../src/config/config.js'
+
+const rootDir = path.resolve(path.dirname(fileURLToPath(import.meta.url)), '..')
+
+interface TestResult {
+  name: string
+  success: boolean
+  error?: string
+}
+
+const results: TestResult[] = []
+
+/**
+ * Generate a Backend Plugin of the type with the mix-ins, go.mod included
+ */
+async function generatePlugin(
+  projectPath: string,
+  name: string,
+  type: BackendPluginType,
+  mixins: BackendMixin[]
+): Promise<string> {
+  const nameInfo = transformPluginName(name, PLUGIN_TYPES.BACKEND, type)
+  const targetPath = path.resolve(projectPath, ANSWER_PATHS.PLUGINS, nameInfo.packageName)
+  const context: PluginContext = {
+    ...nameInfo,
+    targetPath,
+    answerProjectPath: projectPath,
+    modulePath: DEFAULT_MODULE_PATH,
+    answerVersion: DEFAULT_ANSWER_VERSION,
+    pluginType: PLUGIN_TYPES.BACKEND,
+    backendPluginType: type,
+    mixins,
+  }
+
+  createPluginDirectory(context)
+  generateI18n(context)
+  generateBackendPlugin(context)
+  generateReadme(context)
+  await initGoModule(context)
+  return targetPath
+}
+
+/**
+ * Run a Go command in a directory, failing with its output
+ */
+function runGo(args: string[], cwd: string): void {
+  run('go', args, cwd)
+}
+
+/**
+ * Run a command in a directory, failing with its output
+ */
+function run(command: string, args: string[], cwd: string): void {
+  try {
+    execFileSync(command, args, {
+      cwd,
+      env: { ...process.env, GOWORK: 'off' },
+      stdio: ['ignore', 'pipe', 'pipe'],
+    })
+  } catch (error: any) {
+    const output = String(error.stdout || '') + String(error.stderr || error.message)
+    throw new Error(`${path.basename(command)} ${args.join(' ')} failed:\n${output.trim()}`)
+  }
+}
+
+async function test(name: string, fn: () => Promise<void>): Promise<void> {
+  try {
+    await fn()
+    results.push({ name, success: true })
+  } catch (error: any) {
+    results.push({ name, success: false, error: error.message })
+  }
+}
+
+async function testGenerate() {
+  const projectPath = fs.mkdtempSync(path.join(os.tmpdir(), 'answer-generate-'))
+  fs.mkdirSync(path.resolve(projectPath, ANSWER_PATHS.PLUGINS), { recursive: true })
+  loadConfig(projectPath)
+
+  console.log('\n🧪 Building generated Backend Plugins...\n')
+
+  try {
+    // pluginvet checks init() registers the plugin and that it implements the
+    // interface of every type, build it once for all of them
+    const pluginvet = path.join(projectPath, 'pluginvet')
+    runGo(['build', '-o', pluginvet, '.'], path.resolve(rootDir, TOOL_PATHS.PLUGINVET))
+    for (const type of Object.values(BACKEND_PLUGIN_TYPES)) {
+      await test(`${type} builds and passes pluginvet`, async () => {
+        const pluginPath = await generatePlugin(projectPath, `vet-${type}`, type, [])
+        runGo(['build', './...'], pluginPath)
+        run(pluginvet, [pluginPath], pluginPath)
+      })
+    }
+
+    // Fragments are composed with the type's, a name one declares can clash
+    // with what another one expands to, e.g. the receiver
+    for (const [mixin, types] of Object.entries(BACKEND_MIXIN_TYPES) as [BackendMixin, BackendPluginType[]][]) {
+      for (const type of types) {
+        const name = `gen-${type}-${mixin}`
+        await test(`${type} + ${mixin} builds`, async () => {
+          runGo(['build', './...'], await generatePlugin(projectPath, name, type, [mixin]))
+        })
+      }
+    }
+  } finally {
+    fs.rmSync(projectPath, { recursive: true, force: true })
+  }
+
+  const failed = results.filter(r => !r.success)
+  results.forEach(r => {
+    console.log(`${r.success ? '✅' : '❌'} ${r.name}`)
+    if (r.error) {
+      console.log(`   ${r.error.split('\n').join('\n   ')}`)
+    }
+  })
+  console.log(`\n${results.length - failed.length}/${results.length} passed\n`)
+
+  process.exit(failed.length > 0 ? 1 : 0)
+}
+
+testGenerate().catch((error) => {
+  console.error('Test failed:', error)
+  process.exit(1)
+})
@@ -0,0 +1,250 @@
+#!/usr/bin/env tsx
+
+/*
+ * Install Test Script
+ *
+ * This script installs published plugins into a fixture Answer project, from
+ * a GOPROXY built out of the modules in scripts/testdata/goproxy. It runs
+ * offline: the Go modules the tools need are taken from the module cache.
+ *
+ * Usage:
+ *   tsx scripts/test-install.ts
+ */
+
+import assert from 'assert'
+import fs from 'fs'
+import os from 'os'
+import path from 'path'
+import { execFileSync } from 'child_process'
+import { fileURLToPath } from 'url'
+import { installPlugins } from '../src/core/plugin-manager.js'
+import { resolvePluginModule } from '../src/core/plugin-module.js'
+
+const __dirname = path.dirname(fileURLToPath(import.meta.url))
+const TESTDATA_PATH = path.resolve(__dirname, 'testdata')
+
+interface TestResult {
+  name: string
+  success: boolean
+  error?: string
+}
+
+const results: TestResult[] = []
+
+/**
+ * CRC-32 of the zip format
+ */
+const CRC_TABLE = Array.from({ length: 256 }, (_, n) => {
+  let c = n
+  for (let k = 0; k < 8; k++) {
+    c = c & 1 ? 0xedb88320 ^ (c >>> 1) : c >>> 1
+  }
+  return c >>> 0
+})
+
+function crc32(data: Buffer): number {
+  let crc = 0xffffffff
+  for (const byte of data) {
+    crc = CRC_TABLE[(crc ^ byte) & 0xff] ^ (crc >>> 8)
+  }
+  return (crc ^ 0xffffffff) >>> 0
+}
+
+/**
+ * Write a zip of the files, stored without compression, as a module proxy
+ * serves them: every name starts with module@version/
+ */
+function writeZip(zipPath: string, files: Map<string, Buffer>): void {
+  const entries: Buffer[] = []
+  const central: Buffer[] = []
+  let offset = 0
+  for (const [name, data] of files) {
+    const nameBytes = Buffer.from(name)
+    const crc = crc32(data)
+
+    const local = Buffer.alloc(30)
+    local.writeUInt32LE(0x04034b50, 0)
+    local.writeUInt16LE(20, 4)
+    local.writeUInt32LE(crc, 14)
+    local.writeUInt32LE(data.length, 18)
+    local.writeUInt32LE(data.length, 22)
+    local.writeUInt16LE(nameBytes.length, 26)
+    entries.push(local, nameBytes, data)
+
+    const header = Buffer.alloc(46)
+    header.writeUInt32LE(0x02014b50, 0)
+    header.writeUInt16LE(20, 4)
+    header.writeUInt16LE(20, 6)
+    header.writeUInt32LE(crc, 16)
+    header.writeUInt32LE(data.length, 20)
+    header.writeUInt32LE(data.length, 24)
+    header.writeUInt16LE(nameBytes.length, 28)
+    header.writeUInt32LE(offset, 42)
+    central.push(header, nameBytes)
+
+    offset += local.length + nameBytes.length + data.length
+  }
+
+  const centralSize = central.reduce((size, b) => size + b.length, 0)
+  const end = Buffer.alloc(22)
+  end.writeUInt32LE(0x06054b50, 0)
+  end.writeUInt16LE(files.size, 8)
+  end.writeUInt16LE(files.size, 10)
+  end.writeUInt32LE(centralSize, 12)
+  end.writeUInt32LE(offset, 16)
+  fs.writeFileSync(zipPath, Buffer.concat([...entries, ...central, end]))
+}
+
+/**
+ * Files of a directory by their slash separated path
+ */
+function readFiles(dir: string, prefix = ''): Map<string, Buffer> {
+  const files = new Map<string, Buffer>()
+  for (const entry of fs.readdirSync(dir, { withFileTypes: true })) {
+    const filePath = path.resolve(dir, entry.name)
+    if (entry.isDirectory()) {
+      readFiles(filePath, `${prefix}${entry.name}/`).forEach((data, name) => files.set(name, data))
+    } else {
+      files.set(prefix + entry.name, fs.readFileSync(filePath))
+    }
+  }
+  return files
+}
+
+/**
+ * Build a file:// GOPROXY out of testdata/goproxy, where each module@version
+ * directory holds the files of that version
+ */
+function buildProxy(proxyPath: string): string {
+  const sourcePath = path.resolve(TESTDATA_PATH, 'goproxy')
+  const walk = (dir: string) => {
+    for (const entry of fs.readdirSync(dir, { withFileTypes: true })) {
+      if (!entry.isDirectory()) continue
+      const entryPath = path.resolve(dir, entry.name)
+      if (!entry.name.includes('@')) {
+        walk(entryPath)
+        continue
+      }
+
+      const [name, version] = entry.name.split('@')
+      const modulePath = path.relative(sourcePath, path.resolve(dir, name)).split(path.sep).join('/')
+      const versionsPath = path.resolve(proxyPath, modulePath, '@v')
+      fs.mkdirSync(versionsPath, { recursive: true })
+      fs.appendFileSync(path.resolve(versionsPath, 'list'), `${version}\n`)
+      fs.writeFileSync(
+        path.resolve(versionsPath, `${version}.info`),
+        JSON.stringify({ Version: version, Time: '2024-01-01T00:00:00Z' })
+      )
+      fs.copyFileSync(path.resolve(entryPath, 'go.mod'), path.resolve(versionsPath, `${version}.mod`))
+
+      const files = new Map<string, Buffer>()
+      readFiles(entryPath).forEach((data, file) => files.set(`${modulePath}@${version}/${file}`, data))
+      writeZip(path.resolve(versionsPath, `${version}.zip`), files)
+    }
+  }
+  walk(sourcePath)
+  return 'file://' + proxyPath.split(path.sep).join('/')
+}
+
+/**
+ * Copy the fixture Answer project
+ */
+function createAnswerProject(projectPath: string): void {
+  fs.cpSync(path.resolve(TESTDATA_PATH, 'answer'), projectPath, { recursive: true })
+}
+
+function snapshot(projectPath: string): Record<string, string | null> {
+  const files: Record<string, string | null> = {}
+  for (const file of ['go.mod', 'go.sum', 'cmd/answer/main.go']) {
+    const filePath = path.resolve(projectPath, file)
+    files[file] = fs.existsSync(filePath) ? fs.readFileSync(filePath, 'utf-8') : null
+  }
+  return files
+}
+
+async function test(name: string, fn: () => Promise<void>): Promise<void> {
+  try {
+    await fn()
+    results.push({ name, success: true })
+  } catch (error: any) {
+    results.push({ name, success: false, error: error.message })
+  }
+}
+
+async function testInstall() {
+  const tmpPath = fs.mkdtempSync(path.join(os.tmpdir(), 'answer-install-'))
+  const cachePath = execFileSync('go', ['env', 'GOMODCACHE'], { encoding: 'utf-8' }).trim()
+
+  // A module cache of its own, the modules of the tools come from the
+  // download cache of the usual one, the plugins from the fixture proxy
+  process.env.GOMODCACHE = path.resolve(tmpPath, 'modcache')
+  process.env.GOPROXY = 'file://' + path.resolve(cachePath, 'cache/download').split(path.sep).join('/')
+  process.env.GOFLAGS = '-modcacherw'
+  process.env.GONOSUMDB = 'example.com'
+  process.env.GOWORK = 'off'
+  const proxy = buildProxy(path.resolve(tmpPath, 'proxy'))
+
+  console.log('\n🧪 Testing the install of published plugins...\n')
+
+  try {
+    await test('Resolve a published plugin', async () => {
+      const plugin = await resolvePluginModule('example.com/answer-plugins/hello@latest', { proxy })
+      assert.strictEqual(plugin.version, 'v1.0.0')
+      assert.strictEqual(plugin.slugName, 'hello')
+    })
+
+    await test('Install records the checksums of the plugin and its dependencies', async () => {
+      const projectPath = path.resolve(tmpPath, 'answer')
+      createAnswerProject(projectPath)
+      const plugin = await resolvePluginModule('example.com/answer-plugins/hello@v1.0.0', { proxy })
+      installPlugins([plugin], projectPath, { proxy })
+
+      const { 'go.mod': goMod, 'go.sum': goSum, 'cmd/answer/main.go': mainGo } = snapshot(projectPath)
+      assert.match(goMod ?? '', /example\.com\/answer-plugins\/hello v1\.0\.0/)
+      assert.match(mainGo ?? '', /_ "example\.com\/answer-plugins\/hello"/)
+      for (const line of [
+        'example.com/answer-plugins/hello v1.0.0 h1:',
+        'example.com/answer-plugins/hello v1.0.0/go.mod h1:',
+        'example.com/greeting v1.0.0 h1:',
+        'example.com/greeting v1.0.0/go.mod h1:',
+      ]) {
+        assert.ok(goSum?.includes(line), `go.sum has no ${line}`)
+      }
+
+      // go.mod and go.sum are complete, the build needs neither the proxy
+      // nor changes to them
+      execFileSync('go', ['build', './...'], {
+        cwd: projectPath,
+        env: { ...process.env, GOPROXY: 'off', GOFLAGS: '-mod=readonly -modcacherw' },
+        stdio: ['ignore', 'pipe', 'pipe'],
+      })
+    })
+
+    await test('A plugin whose dependencies are missing is rolled back', async () => {
+      const projectPath = path.resolve(tmpPath, 'answer-broken')
+      createAnswerProject(projectPath)
+      const before = snapshot(projectPath)
+      const plugin = await resolvePluginModule('example.com/answer-plugins/broken@v1.0.0', { proxy })
+      assert.throws(() => installPlugins([plugin], projectPath, { proxy }), /go get failed/)
+      assert.deepStrictEqual(snapshot(projectPath), before)
+    })
+  } finally {
+    fs.rmSync(tmpPath, { recursive: true, force: true })
+  }
+
+  const failed = results.filter(r => !r.success)
+  results.forEach(r => {
+    console.log(`${r.success ? '✅' : '❌'} ${r.name}`)
+    if (r.error) {
+      console.log(`   ${r.error.split('\n').join('\n   ')}`)
+    }
+  })
+  console.log(`\n${results.length - failed.length}/${results.length} passed\n`)
+
+  process.exit(failed.length > 0 ? 1 : 0)
+}
+
+testInstall().catch((error) => {
+  console.error('Test failed:', error)
+  process.exit(1)
+})
@@ -0,0 +1,5 @@
+package broken
+
+import "example.com/missing"
+
+var Greeting = missing.Hello()
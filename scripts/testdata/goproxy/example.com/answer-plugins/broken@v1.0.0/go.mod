@@ -0,0 +1,5 @@
+module example.com/answer-plugins/broken
+
+go 1.21
+
+require example.com/missing v1.0.0
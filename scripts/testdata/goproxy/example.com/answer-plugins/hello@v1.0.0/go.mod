@@ -0,0 +1,5 @@
+module example.com/answer-plugins/hello
+
+go 1.21
+
+require example.com/greeting v1.0.0
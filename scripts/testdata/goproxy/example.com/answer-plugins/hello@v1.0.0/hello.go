@@ -0,0 +1,5 @@
+package hello
+
+import "example.com/greeting"
+
+var Greeting = greeting.Hello()
@@ -0,0 +1,5 @@
+package greeting
+
+func Hello() string {
+	return "hello"
+}
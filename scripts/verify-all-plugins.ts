@@ -14,6 +14,7 @@ import path from 'path'
 import { exec } from 'child_process'
 import { promisify } from 'util'
 import ora from 'ora'
+import { TESTKIT } from '../src/config/constants.js'
 
 const execAsync = promisify(exec)
 
@@ -80,6 +81,30 @@ async function verifyPlugin(pluginName: string): Promise<PluginResult> {
   }
 }
 
+/**
+ * Vet the testkit the plugins share, with the answer-dev harness every
+ * backend plugin builds. Undefined when it isn't in the plugins directory.
+ */
+async function verifyTestkit(): Promise<PluginResult | undefined> {
+  const testkitPath = path.resolve(PLUGINS_PATH, TESTKIT.PACKAGE)
+  if (!fs.existsSync(path.resolve(testkitPath, 'go.mod'))) {
+    return undefined
+  }
+
+  const errors: string[] = []
+  try {
+    await execAsync('go vet ./...', { cwd: testkitPath, timeout: 120000 })
+  } catch (error: any) {
+    errors.push(`go vet failed: ${error.stderr || error.message}`)
+  }
+
+  return {
+    name: TESTKIT.PACKAGE,
+    success: errors.length === 0,
+    errors,
+  }
+}
+
 /**
  * Main function
  */
@@ -91,7 +116,11 @@ async function verifyAllPlugins(): Promise<void> {
     process.exit(1)
   }
 
+  // The testkit is shared by the plugins' tests, it isn't a plugin
   const plugins = fs.readdirSync(PLUGINS_PATH).filter(item => {
+    if (item === TESTKIT.PACKAGE) {
+      return false
+    }
     const itemPath = path.resolve(PLUGINS_PATH, item)
     return fs.statSync(itemPath).isDirectory()
   })
@@ -105,6 +134,19 @@ async function verifyAllPlugins(): Promise<void> {
 
   const results: PluginResult[] = []
 
+  const testkitSpinner = ora(`Vetting ${TESTKIT.PACKAGE}...`).start()
+  const testkit = await verifyTestkit()
+  if (!testkit) {
+    testkitSpinner.info(`No ${TESTKIT.PACKAGE} to vet`)
+  } else {
+    results.push(testkit)
+    if (testkit.success) {
+      testkitSpinner.succeed(`${TESTKIT.PACKAGE} - ✅ Vetted`)
+    } else {
+      testkitSpinner.fail(`${TESTKIT.PACKAGE} - ❌ go vet failed`)
+    }
+  }
+
   for (const plugin of plugins) {
     const spinner = ora(`Verifying ${plugin}...`).start()
     const result = await verifyPlugin(plugin)
@@ -11,11 +11,16 @@
 
 import fs from 'fs'
 import path from 'path'
-import { exec } from 'child_process'
+import { exec, execFile } from 'child_process'
+import { fileURLToPath } from 'url'
 import { promisify } from 'util'
 import ora from 'ora'
+import { DEFAULT_MODULE_PATH, TOOL_PATHS } from '../src/config/constants.js'
+import { readModulePath } from '../src/core/module-path.js'
 
 const execAsync = promisify(exec)
+const execFileAsync = promisify(execFile)
+const rootDir = path.resolve(path.dirname(fileURLToPath(import.meta.url)), '..')
 
 // Parse command line arguments
 const args = process.argv.slice(2)
@@ -40,6 +45,16 @@ interface VerificationResult {
   step: string
   success: boolean
   message: string
+  // Passed, with findings left to look at
+  warning?: boolean
+}
+
+// A finding of tools/pluginvet
+interface PluginVetDiagnostic {
+  pos: string
+  check: string
+  severity: 'error' | 'warning'
+  message: string
 }
 
 const results: VerificationResult[] = []
@@ -264,6 +279,59 @@ async function checkGoCompilation(): Promise<boolean> {
   }
 }
 
+/**
+ * Check with tools/pluginvet that init() registers the plugin and that it
+ * implements the interfaces of its info.yaml types. Template TODO stubs and
+ * fmt.Print debug output are reported as warnings.
+ */
+async function checkPluginInterfaces(): Promise<boolean> {
+  const spinner = ora('Checking plugin interfaces...').start()
+  let diagnostics: PluginVetDiagnostic[]
+  try {
+    const { stdout } = await execFileAsync('go', ['run', '.', '-json', PLUGIN_PATH], {
+      cwd: path.resolve(rootDir, TOOL_PATHS.PLUGINVET),
+      env: { ...process.env, GOWORK: 'off' },
+      timeout: 60000,
+    })
+    diagnostics = JSON.parse(stdout)
+  } catch (error: any) {
+    spinner.fail('Plugin interface check failed')
+    results.push({
+      step: 'Plugin Interfaces',
+      success: false,
+      message: `pluginvet error: ${String(error.stderr || error.message).replace(/\nexit status \d+\s*$/, '').trim()}`,
+    })
+    return false
+  }
+
+  const format = (d: PluginVetDiagnostic) =>
+    `${path.relative(PLUGIN_PATH, d.pos) || d.pos}: ${d.check}: ${d.message}`
+  const errors = diagnostics.filter(d => d.severity === 'error')
+  const warnings = diagnostics.filter(d => d.severity === 'warning')
+
+  if (errors.length > 0) {
+    spinner.fail('Plugin interface check failed')
+  } else {
+    spinner.succeed('Plugin registers itself and implements its interfaces')
+  }
+  results.push({
+    step: 'Plugin Interfaces',
+    success: errors.length === 0,
+    message: errors.length === 0
+      ? 'init() registers the plugin, which implements the interfaces of its info.yaml types'
+      : errors.map(format).join('\n   '),
+  })
+  if (warnings.length > 0) {
+    results.push({
+      step: 'Leftover Stubs',
+      success: true,
+      warning: true,
+      message: warnings.map(format).join('\n   '),
+    })
+  }
+  return errors.length === 0
+}
+
 /**
  * Check if plugin can be imported in Answer project
  */
@@ -281,15 +349,22 @@ async function checkAnswerIntegration(): Promise<boolean> {
       return false
     }
 
-    const importPath = `github.com/apache/answer-plugins/${PLUGIN_NAME}`
+    const importPath =
+      readModulePath(PLUGIN_PATH) ?? `${DEFAULT_MODULE_PATH}/${PLUGIN_NAME}`
     
     // Check if plugin is already imported
     const mainGoContent = fs.readFileSync(mainGoPath, 'utf-8')
     const isImported = mainGoContent.includes(importPath)
 
-    // Check if replace directive exists
+    // Check if replace directive exists, on its own or in a replace block,
+    // or if go.work uses the plugin instead
     const goModContent = fs.readFileSync(goModPath, 'utf-8')
-    const hasReplace = goModContent.includes(`replace ${importPath}`)
+    const goWorkPath = path.resolve(ANSWER_PROJECT_PATH, 'go.work')
+    const pluginDir = './' + path.relative(ANSWER_PROJECT_PATH, PLUGIN_PATH).split(path.sep).join('/')
+    const hasReplace =
+      goModContent.includes(`${importPath} =>`) ||
+      (fs.existsSync(goWorkPath) &&
+        fs.readFileSync(goWorkPath, 'utf-8').split('\n').some((line) => line.replace(/^\s*(use\s+)?/, '').trim() === pluginDir))
 
     if (!isImported || !hasReplace) {
       results.push({
@@ -363,9 +438,15 @@ async function verifyPlugin(): Promise<void> {
   checkGoMod()
 
   // Step 6: Try to compile
-  await checkGoCompilation()
+  const compiles = await checkGoCompilation()
+
+  // Step 7: Check registration and interfaces, the compiler's errors
+  // would only be repeated if it doesn't compile
+  if (compiles) {
+    await checkPluginInterfaces()
+  }
 
-  // Step 7: Check Answer integration (optional)
+  // Step 8: Check Answer integration (optional)
   if (hasCheckIntegration) {
     await checkAnswerIntegration()
   }
@@ -387,8 +468,8 @@ function printResults(): void {
   console.log('='.repeat(60) + '\n')
 
   results.forEach((result, index) => {
-    const icon = result.success ? '✅' : '❌'
-    const color = result.success ? '\x1b[32m' : '\x1b[31m'
+    const icon = result.warning ? '⚠️' : result.success ? '✅' : '❌'
+    const color = result.warning ? '\x1b[33m' : result.success ? '\x1b[32m' : '\x1b[31m'
     const reset = '\x1b[0m'
     
     console.log(`${icon} ${index + 1}. ${result.step}`)
@@ -400,7 +481,8 @@ function printResults(): void {
   const percentage = ((passed / total) * 100).toFixed(1)
 
   console.log('='.repeat(60))
-  console.log(`Summary: ${passed}/${total} checks passed (${percentage}%)`)
+  const warnings = results.filter(r => r.warning).length
+  console.log(`Summary: ${passed}/${total} checks passed (${percentage}%)${warnings > 0 ? `, ${warnings} with warnings` : ''}`)
   console.log('='.repeat(60) + '\n')
 }
 
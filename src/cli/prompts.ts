@@ -8,6 +8,17 @@ import {
   PLUGIN_TYPES,
   STANDARD_UI_TYPES,
   BACKEND_PLUGIN_TYPES,
+  BACKEND_PLUGIN_VARIANTS,
+  STANDARD_UI_VARIANTS,
+  BACKEND_MIXINS,
+  BACKEND_MIXIN_TYPES,
+  BACKEND_REQUIRED_MIXINS,
+  BACKEND_TYPE_CONFLICTS,
+  BackendMixin,
+  TEMPLATE_VARIANTS,
+  TemplateVariant,
+  BackendPluginType,
+  StandardUIPluginType,
 } from "../config/constants.js";
 import { getConfigPath } from "../config/config.js";
 import path from "path";
@@ -17,18 +28,58 @@ export interface PluginAnswers {
   pluginName: string;
   answerProjectPath: string;
   pluginType: "backend" | "standard";
-  backendPluginType?:
-  | "connector"
-  | "storage"
-  | "cache"
-  | "search"
-  | "user-center"
-  | "notification"
-  | "reviewer";
-  standardPluginType?: "editor" | "route" | "captcha" | "render";
+  backendPluginType?: BackendPluginType;
+  backendPluginTypes?: BackendPluginType[];
+  templateVariant?: TemplateVariant;
+  mixins?: BackendMixin[];
+  standardPluginType?: StandardUIPluginType;
   routePath?: string;
 }
 
+/**
+ * Display titles for template variants
+ */
+const VARIANT_TITLES: Record<TemplateVariant, string> = {
+  [TEMPLATE_VARIANTS.BASIC]: "Basic (Hello World)",
+  [TEMPLATE_VARIANTS.RULES]:
+    "Rule engine (keywords, regexes, links, new accounts)",
+  [TEMPLATE_VARIANTS.API]:
+    "External moderation API (OpenAI-compatible or generic classifier)",
+  [TEMPLATE_VARIANTS.SPAM]: "Spam reputation (IP/email blocklists, Akismet)",
+  [TEMPLATE_VARIANTS.MATH]: "Self-hosted math captcha (signed challenges)",
+  [TEMPLATE_VARIANTS.SITEVERIFY]: "Turnstile, hCaptcha or reCAPTCHA widget",
+  [TEMPLATE_VARIANTS.SSR]:
+    "Server-side rendering (chart fences rendered in Go and TypeScript)",
+};
+
+/**
+ * Backend Plugin sub-types to choose from
+ */
+const BACKEND_TYPE_CHOICES: { title: string; value: BackendPluginType }[] = [
+  { title: "Connector", value: BACKEND_PLUGIN_TYPES.CONNECTOR },
+  { title: "Storage", value: BACKEND_PLUGIN_TYPES.STORAGE },
+  { title: "Cache", value: BACKEND_PLUGIN_TYPES.CACHE },
+  { title: "Search", value: BACKEND_PLUGIN_TYPES.SEARCH },
+  { title: "User Center", value: BACKEND_PLUGIN_TYPES.USER_CENTER },
+  { title: "Notification", value: BACKEND_PLUGIN_TYPES.NOTIFICATION },
+  { title: "Reviewer", value: BACKEND_PLUGIN_TYPES.REVIEWER },
+  { title: "Importer", value: BACKEND_PLUGIN_TYPES.IMPORTER },
+  { title: "KV Storage", value: BACKEND_PLUGIN_TYPES.KV_STORAGE },
+  { title: "MCP Tool (AI agents)", value: BACKEND_PLUGIN_TYPES.MCP_TOOL },
+  {
+    title: "Filter (rewrite content before save)",
+    value: BACKEND_PLUGIN_TYPES.FILTER,
+  },
+];
+
+/**
+ * Questions offering each mix-in
+ */
+const MIXIN_QUESTIONS: Record<BackendMixin, string> = {
+  [BACKEND_MIXINS.KV]: "Add a typed KV storage repository?",
+  [BACKEND_MIXINS.USER_CONFIG]: "Add per-user settings (UserConfig)?",
+};
+
 /**
  * Collect plugin creation information from user
  */
@@ -84,16 +135,11 @@ export const collectPluginInfo = async (
     throw new Error("Plugin type is required");
   }
 
-  let backendPluginType:
-    | "connector"
-    | "storage"
-    | "cache"
-    | "search"
-    | "user-center"
-    | "notification"
-    | "reviewer"
-    | undefined;
-  let standardPluginType: "editor" | "route" | "captcha" | "render" | undefined;
+  let backendPluginType: BackendPluginType | undefined;
+  let backendPluginTypes: BackendPluginType[] | undefined;
+  let templateVariant: TemplateVariant | undefined;
+  const mixins: BackendMixin[] = [];
+  let standardPluginType: StandardUIPluginType | undefined;
   let routePath: string | undefined;
 
   // Step 4: Backend Plugin sub-type
@@ -102,21 +148,74 @@ export const collectPluginInfo = async (
       type: "select",
       name: "backendType",
       message: "What type of Backend Plugin?",
-      choices: [
-        { title: "Connector", value: BACKEND_PLUGIN_TYPES.CONNECTOR },
-        { title: "Storage", value: BACKEND_PLUGIN_TYPES.STORAGE },
-        { title: "Cache", value: BACKEND_PLUGIN_TYPES.CACHE },
-        { title: "Search", value: BACKEND_PLUGIN_TYPES.SEARCH },
-        { title: "User Center", value: BACKEND_PLUGIN_TYPES.USER_CENTER },
-        { title: "Notification", value: BACKEND_PLUGIN_TYPES.NOTIFICATION },
-        { title: "Reviewer", value: BACKEND_PLUGIN_TYPES.REVIEWER },
-      ],
+      choices: BACKEND_TYPE_CHOICES,
     });
 
     if (!backendType) {
       throw new Error("Backend plugin type is required");
     }
     backendPluginType = backendType;
+
+    // Step 4.1: Further sub-types implemented by the same plugin
+    const { moreTypes } = await prompts({
+      type: "multiselect",
+      name: "moreTypes",
+      message: "Should the plugin implement other types too? (optional)",
+      choices: BACKEND_TYPE_CHOICES.filter(
+        ({ value }) =>
+          value !== backendType &&
+          !BACKEND_TYPE_CONFLICTS[backendType]?.includes(value)
+      ),
+      instructions: false,
+      hint: "- Space to select, Return to continue",
+    });
+    backendPluginTypes = [backendType, ...(moreTypes ?? [])];
+
+    // Step 4.2: Template variant (if a sub-type offers more than one)
+    for (const type of backendPluginTypes) {
+      const variants = BACKEND_PLUGIN_VARIANTS[type];
+      if (!variants || variants.length <= 1) {
+        continue;
+      }
+      const { variant } = await prompts({
+        type: "select",
+        name: "variant",
+        message:
+          backendPluginTypes.length > 1
+            ? `Which ${type} template do you want to start from?`
+            : "Which template do you want to start from?",
+        choices: variants.map((value) => ({
+          title: VARIANT_TITLES[value],
+          value,
+        })),
+      });
+
+      if (!variant) {
+        throw new Error("Template variant is required");
+      }
+      templateVariant = variant;
+    }
+
+    // Step 4.3: Opt-in mix-ins
+    for (const mixin of Object.values(BACKEND_MIXINS)) {
+      const types = backendPluginTypes;
+      if (
+        types.some((type) => BACKEND_REQUIRED_MIXINS[type]?.includes(mixin)) ||
+        !types.some((type) => BACKEND_MIXIN_TYPES[mixin].includes(type))
+      ) {
+        continue;
+      }
+      const { add } = await prompts({
+        type: "confirm",
+        name: "add",
+        message: MIXIN_QUESTIONS[mixin],
+        initial: false,
+      });
+
+      if (add) {
+        mixins.push(mixin);
+      }
+    }
   }
 
   // Step 5: Standard UI Plugin sub-type
@@ -130,6 +229,8 @@ export const collectPluginInfo = async (
         { title: "Route", value: STANDARD_UI_TYPES.ROUTE },
         { title: "Captcha", value: STANDARD_UI_TYPES.CAPTCHA },
         { title: "Render", value: STANDARD_UI_TYPES.RENDER },
+        { title: "Embed", value: STANDARD_UI_TYPES.EMBED },
+        { title: "Sidebar", value: STANDARD_UI_TYPES.SIDEBAR },
       ],
     });
 
@@ -139,6 +240,25 @@ export const collectPluginInfo = async (
 
     standardPluginType = standardType;
 
+    // Step 5.1: Template variant (if the sub-type offers more than one)
+    const variants = STANDARD_UI_VARIANTS[standardType as StandardUIPluginType];
+    if (variants && variants.length > 1) {
+      const { variant } = await prompts({
+        type: "select",
+        name: "variant",
+        message: "Which template do you want to start from?",
+        choices: variants.map((value) => ({
+          title: VARIANT_TITLES[value],
+          value,
+        })),
+      });
+
+      if (!variant) {
+        throw new Error("Template variant is required");
+      }
+      templateVariant = variant;
+    }
+
     // Step 6: Route path (if route type)
     if (standardType === STANDARD_UI_TYPES.ROUTE) {
       const { route } = await prompts({
@@ -162,6 +282,9 @@ export const collectPluginInfo = async (
     answerProjectPath,
     pluginType: pluginType as "backend" | "standard",
     backendPluginType,
+    backendPluginTypes,
+    templateVariant,
+    mixins,
     standardPluginType,
     routePath,
   };
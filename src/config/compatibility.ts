@@ -0,0 +1,76 @@
+/**
+ * Compatibility of the templates with Answer releases. Maintained by hand
+ * from Answer's release notes: when a release changes a plugin interface the
+ * templates use, add an entry and keep the templates of the older signature
+ * under template/compat/<version>.
+ */
+
+/**
+ * Answer version plugins are generated for when the project's can't be
+ * detected, the one the templates are written against
+ */
+export const DEFAULT_ANSWER_VERSION = 'v1.7.0'
+
+/**
+ * Oldest Answer version plugins can be generated for
+ */
+export const MIN_ANSWER_VERSION = 'v1.4.0'
+
+/**
+ * Versions of github.com/apache/answer-plugins/util, by the oldest Answer
+ * version each builds with, newest first
+ */
+export const UTIL_VERSIONS = [
+  { answer: 'v1.4.5', util: 'v1.0.3-0.20250107030257-cf94ebc70954' },
+  { answer: 'v1.4.0', util: 'v1.0.2' },
+]
+
+/**
+ * A change of a plugin interface. Before the version, the template files of
+ * the capability are taken from template/compat/<version> where it has them.
+ */
+export interface InterfaceChange {
+  // Backend sub-type, mix-in or Standard UI type
+  capability: string
+  // First Answer version with the new signature
+  version: string
+  description: string
+}
+
+export const INTERFACE_CHANGES: InterfaceChange[] = [
+  {
+    capability: 'storage',
+    version: 'v1.4.2',
+    description:
+      'UploadFile takes an UploadFileCondition with the upload limits of the site instead of an UploadSource',
+  },
+]
+
+/**
+ * Answer version a capability or a part of the testkit needs, and what it
+ * needs from it
+ */
+export interface Requirement {
+  version: string
+  reason: string
+}
+
+export const CAPABILITY_REQUIREMENTS: Record<string, Requirement> = {
+  'kv-storage': { version: 'v1.4.5', reason: 'KV storage' },
+  kv: { version: 'v1.4.5', reason: 'KV storage' },
+  importer: { version: 'v1.4.5', reason: 'importers' },
+  reviewer: {
+    version: 'v1.4.5',
+    reason: 'KV storage, the audit log is kept in it',
+  },
+}
+
+/**
+ * Files of the testkit left out before an Answer version, by name. Plugins
+ * get the answer-dev harness only with the devserver.
+ */
+export const TESTKIT_REQUIREMENTS: Record<string, Requirement> = {
+  'kv.go': { version: 'v1.4.5', reason: 'KV storage' },
+  'kv_test.go': { version: 'v1.4.5', reason: 'KV storage' },
+  devserver: { version: 'v1.4.5', reason: 'KV storage and importers' },
+}
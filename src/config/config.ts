@@ -1,5 +1,7 @@
+import fs from 'fs'
 import path from 'path'
 import { ConfigurationError } from '../errors/index.js'
+import { MODULE_PATH_PATTERN, PROJECT_CONFIG_FILE } from './constants.js'
 
 /**
  * Application configuration interface
@@ -10,23 +12,34 @@ export interface AppConfig {
     i18n: string
     mainGo: string
     goMod: string
+    goWork: string
   }
   commands: {
     goModTidy: string
     pnpmInstall: string
     i18nMerge: string
+    goListModule: string
+    goModDownload: string
+    gitTopLevel: string
+    gitRemoteUrl: string
+    goBuild: string
   }
   timeouts: {
     default: number
     goModTidy: number
     pnpmInstall: number
     i18nMerge: number
+    goEdit: number
+    goModDownload: number
+    goBuild: number
   }
   retries: {
     default: number
     goModTidy: number
     pnpmInstall: number
   }
+  // Module path the plugins live under, empty to detect it
+  modulePath: string
 }
 
 /**
@@ -38,23 +51,33 @@ const DEFAULT_CONFIG: AppConfig = {
     i18n: 'answer-data/i18n',
     mainGo: 'cmd/answer/main.go',
     goMod: 'go.mod',
+    goWork: 'go.work',
   },
   commands: {
     goModTidy: 'go mod tidy',
     pnpmInstall: 'pnpm install',
     i18nMerge: 'go run ./cmd/answer/main.go i18n',
+    goListModule: 'go list -m -json',
+    goModDownload: 'go mod download -json',
+    gitTopLevel: 'git rev-parse --show-toplevel',
+    gitRemoteUrl: 'git remote get-url origin',
+    goBuild: 'go build ./...',
   },
   timeouts: {
     default: 30000, // 30 seconds
     goModTidy: 30000, // 30 seconds
     pnpmInstall: 120000, // 2 minutes
     i18nMerge: 60000, // 1 minute
+    goEdit: 60000, // 1 minute, the first run builds the helper
+    goModDownload: 120000, // 2 minutes
+    goBuild: 120000, // 2 minutes
   },
   retries: {
     default: 0,
     goModTidy: 1,
     pnpmInstall: 1,
   },
+  modulePath: '',
 }
 
 /**
@@ -63,11 +86,52 @@ const DEFAULT_CONFIG: AppConfig = {
 let globalConfig: AppConfig = { ...DEFAULT_CONFIG }
 
 /**
- * Load configuration from environment variables or use defaults
+ * Settings of the project config file, answer-plugin.config.json in the
+ * Answer project
+ */
+interface ProjectConfig {
+  modulePath?: string
+}
+
+/**
+ * Read the project config file, a missing one is empty
+ */
+function readProjectConfig(projectPath: string): ProjectConfig {
+  const configPath = path.resolve(projectPath, PROJECT_CONFIG_FILE)
+  if (!fs.existsSync(configPath)) {
+    return {}
+  }
+
+  let projectConfig: unknown
+  try {
+    projectConfig = JSON.parse(fs.readFileSync(configPath, 'utf-8'))
+  } catch (error: any) {
+    throw new ConfigurationError(`Invalid ${configPath}: ${error.message}`)
+  }
+  if (typeof projectConfig !== 'object' || projectConfig === null || Array.isArray(projectConfig)) {
+    throw new ConfigurationError(`Invalid ${configPath}: expected a JSON object`)
+  }
+  const { modulePath } = projectConfig as Record<string, unknown>
+  if (modulePath !== undefined && typeof modulePath !== 'string') {
+    throw new ConfigurationError(`Invalid ${configPath}: modulePath must be a string`)
+  }
+  return { modulePath }
+}
+
+/**
+ * Load configuration from the project config file and environment
+ * variables, which take precedence, or use defaults
  */
 export function loadConfig(projectPath?: string): AppConfig {
   const config: AppConfig = { ...DEFAULT_CONFIG }
 
+  if (projectPath) {
+    const projectConfig = readProjectConfig(projectPath)
+    if (projectConfig.modulePath) {
+      config.modulePath = projectConfig.modulePath
+    }
+  }
+
   // Override from environment variables if present
   if (process.env.ANSWER_PLUGINS_PATH) {
     config.answerPaths.plugins = process.env.ANSWER_PLUGINS_PATH
@@ -77,6 +141,10 @@ export function loadConfig(projectPath?: string): AppConfig {
     config.answerPaths.i18n = process.env.ANSWER_I18N_PATH
   }
 
+  if (process.env.ANSWER_PLUGIN_MODULE_PATH) {
+    config.modulePath = process.env.ANSWER_PLUGIN_MODULE_PATH
+  }
+
   if (process.env.GO_MOD_TIDY_TIMEOUT) {
     const timeout = parseInt(process.env.GO_MOD_TIDY_TIMEOUT, 10)
     if (!isNaN(timeout)) {
@@ -134,6 +202,12 @@ function validateConfig(config: AppConfig): void {
     throw new ConfigurationError('goMod path is required')
   }
 
+  if (config.modulePath && !MODULE_PATH_PATTERN.test(config.modulePath)) {
+    throw new ConfigurationError(
+      `Invalid module path ${config.modulePath}, expected e.g. git.example.com/acme/answer-plugins`
+    )
+  }
+
   // Validate timeouts (must be positive)
   Object.entries(config.timeouts).forEach(([key, value]) => {
     if (value <= 0) {
@@ -19,10 +19,83 @@ export const BACKEND_PLUGIN_TYPES = {
   USER_CENTER: 'user-center',
   NOTIFICATION: 'notification',
   REVIEWER: 'reviewer',
+  IMPORTER: 'importer',
+  KV_STORAGE: 'kv-storage',
+  MCP_TOOL: 'mcp-tool',
+  FILTER: 'filter',
 } as const
 
 export type BackendPluginType = typeof BACKEND_PLUGIN_TYPES[keyof typeof BACKEND_PLUGIN_TYPES]
 
+/**
+ * Plugin template variants
+ * The basic variant is the single-file template in template/backend,
+ * other Backend Plugin variants live in template/backend/variants/{type}/{variant}
+ * and Standard UI Plugin variants in template/ui/variants/{type}/{variant}
+ */
+export const TEMPLATE_VARIANTS = {
+  BASIC: 'basic',
+  RULES: 'rules',
+  API: 'api',
+  SPAM: 'spam',
+  MATH: 'math',
+  SITEVERIFY: 'siteverify',
+  SSR: 'ssr',
+} as const
+
+export type TemplateVariant = typeof TEMPLATE_VARIANTS[keyof typeof TEMPLATE_VARIANTS]
+
+/**
+ * Template variants offered for each Backend Plugin sub-type
+ */
+export const BACKEND_PLUGIN_VARIANTS: Partial<Record<BackendPluginType, TemplateVariant[]>> = {
+  [BACKEND_PLUGIN_TYPES.REVIEWER]: [
+    TEMPLATE_VARIANTS.BASIC,
+    TEMPLATE_VARIANTS.RULES,
+    TEMPLATE_VARIANTS.API,
+    TEMPLATE_VARIANTS.SPAM,
+  ],
+}
+
+/**
+ * Backend Plugin mix-ins, opt-in features added on top of a sub-type.
+ * Each lives in template/backend/mixins/{mixin}: a plugin.go fragment composed
+ * after the sub-type's own, helper files copied next to it and translations
+ * merged into the plugin's.
+ */
+export const BACKEND_MIXINS = {
+  KV: 'kv',
+  USER_CONFIG: 'user-config',
+} as const
+
+export type BackendMixin = typeof BACKEND_MIXINS[keyof typeof BACKEND_MIXINS]
+
+/**
+ * Backend Plugin sub-types that can opt in to each mix-in.
+ * Methods a mix-in shares with the sub-type, like SetOperator, are merged
+ * when the fragments are composed, so every sub-type can take them.
+ */
+export const BACKEND_MIXIN_TYPES: Record<BackendMixin, BackendPluginType[]> = {
+  [BACKEND_MIXINS.KV]: Object.values(BACKEND_PLUGIN_TYPES),
+  [BACKEND_MIXINS.USER_CONFIG]: Object.values(BACKEND_PLUGIN_TYPES),
+}
+
+/**
+ * Mix-ins a Backend Plugin sub-type is built on and always gets
+ */
+export const BACKEND_REQUIRED_MIXINS: Partial<Record<BackendPluginType, BackendMixin[]>> = {
+  [BACKEND_PLUGIN_TYPES.KV_STORAGE]: [BACKEND_MIXINS.KV],
+}
+
+/**
+ * Backend Plugin sub-types one plugin can't implement together, their Answer
+ * interfaces declare the same method differently, e.g. Description()
+ */
+export const BACKEND_TYPE_CONFLICTS: Partial<Record<BackendPluginType, BackendPluginType[]>> = {
+  [BACKEND_PLUGIN_TYPES.SEARCH]: [BACKEND_PLUGIN_TYPES.USER_CENTER],
+  [BACKEND_PLUGIN_TYPES.USER_CENTER]: [BACKEND_PLUGIN_TYPES.SEARCH],
+}
+
 /**
  * Standard UI Plugin sub-types
  */
@@ -31,20 +104,95 @@ export const STANDARD_UI_TYPES = {
   ROUTE: 'route',
   CAPTCHA: 'captcha',
   RENDER: 'render',
+  EMBED: 'embed',
+  SIDEBAR: 'sidebar',
 } as const
 
 export type StandardUIPluginType = typeof STANDARD_UI_TYPES[keyof typeof STANDARD_UI_TYPES]
 
+/**
+ * Template variants offered for each Standard UI Plugin sub-type, the first
+ * one is the default
+ */
+export const STANDARD_UI_VARIANTS: Partial<Record<StandardUIPluginType, TemplateVariant[]>> = {
+  [STANDARD_UI_TYPES.CAPTCHA]: [
+    TEMPLATE_VARIANTS.MATH,
+    TEMPLATE_VARIANTS.SITEVERIFY,
+  ],
+  [STANDARD_UI_TYPES.RENDER]: [
+    TEMPLATE_VARIANTS.BASIC,
+    TEMPLATE_VARIANTS.SSR,
+  ],
+}
+
 /**
  * Template paths
  */
 export const TEMPLATE_PATHS = {
+  BASE: 'template/plugin.go',
+  BASE_TEST: 'template/plugin_test.go',
   BACKEND: 'template/backend',
+  BACKEND_VARIANTS: 'template/backend/variants',
+  BACKEND_SHARED: 'template/backend/shared',
+  BACKEND_MIXINS: 'template/backend/mixins',
   STANDARD_UI_BASE: 'template/ui',
   STANDARD_UI_TYPES: 'template/ui/types',
+  STANDARD_UI_VARIANTS: 'template/ui/variants',
   I18N: 'template/i18n',
+  TESTKIT: 'template/testkit',
+  DEV: 'template/dev',
+  // Templates as they were before an Answer version, by version
+  COMPAT: 'template/compat',
+} as const
+
+/**
+ * Go helpers run by the CLI, each is a module of its own
+ */
+export const TOOL_PATHS = {
+  GOEDIT: 'tools/goedit',
+  PLUGINVET: 'tools/pluginvet',
+} as const
+
+/**
+ * Shared Go testkit, generated next to the plugins that require it. It's
+ * published with them, tagged testkit/<VERSION>, the version they require.
+ */
+export const TESTKIT = {
+  PACKAGE: 'testkit',
+  VERSION: 'v0.1.0',
 } as const
 
+/**
+ * File of a generated plugin, and of the testkit, recording the template
+ * version, the render inputs and the rendered files, the base of upgrades
+ */
+export const TEMPLATE_LOCK_FILE = 'answer-plugin.lock.json'
+
+/**
+ * Optional file of the Answer project with settings shared by everyone
+ * creating plugins in it, e.g. {"modulePath": "git.example.com/acme/answer-plugins"}
+ */
+export const PROJECT_CONFIG_FILE = 'answer-plugin.config.json'
+
+/**
+ * Module path plugins live under unless another one is configured, the module
+ * of a plugin is <module path>/<package name> and the testkit's
+ * <module path>/testkit
+ */
+export const DEFAULT_MODULE_PATH = 'github.com/apache/answer-plugins'
+
+/**
+ * Module path without a version: a lower-case domain, then path elements
+ */
+export const MODULE_PATH_PATTERN =
+  /^[a-z0-9-]+(\.[a-z0-9-]+)+(\/[A-Za-z0-9._~+-]+)*$/
+
+/**
+ * Version Answer requires of a plugin replaced by its local directory, the
+ * one go mod tidy writes for a module without releases
+ */
+export const LOCAL_MODULE_VERSION = 'v0.0.0-00010101000000-000000000000'
+
 /**
  * Answer project structure
  */
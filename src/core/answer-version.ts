@@ -0,0 +1,204 @@
+import fs from "fs";
+import path from "path";
+import { URL, fileURLToPath } from "node:url";
+import { getConfigPath } from "../config/config.js";
+import { TEMPLATE_PATHS } from "../config/constants.js";
+import {
+  DEFAULT_ANSWER_VERSION,
+  MIN_ANSWER_VERSION,
+  UTIL_VERSIONS,
+  INTERFACE_CHANGES,
+  CAPABILITY_REQUIREMENTS,
+  TESTKIT_REQUIREMENTS,
+} from "../config/compatibility.js";
+import { ValidationError } from "../errors/index.js";
+import { readGoMod } from "../utils/goedit.js";
+import { getLogger } from "./logger.js";
+
+const __dirname = path.dirname(fileURLToPath(new URL(import.meta.url)));
+const rootDir = path.resolve(__dirname, "../../");
+
+const ANSWER_MODULE = "github.com/apache/answer";
+
+const VERSION_PATTERN = /^v?(\d+)\.(\d+)\.(\d+)(-[^+\s]*)?(\+\S*)?$/;
+
+/**
+ * Compare two versions of the form vMAJOR.MINOR.PATCH, a pre-release or a
+ * pseudo-version sorts before its release
+ */
+export function compareVersions(a: string, b: string): number {
+  const parse = (version: string) => {
+    const match = version.match(VERSION_PATTERN);
+    if (!match) {
+      throw new ValidationError(`Invalid Answer version ${version}`);
+    }
+    return {
+      numbers: [match[1], match[2], match[3]].map(Number),
+      prerelease: match[4] ?? "",
+    };
+  };
+  const x = parse(a);
+  const y = parse(b);
+
+  for (let i = 0; i < 3; i++) {
+    if (x.numbers[i] !== y.numbers[i]) {
+      return x.numbers[i] - y.numbers[i];
+    }
+  }
+  if (x.prerelease === y.prerelease) {
+    return 0;
+  }
+  if (!x.prerelease || !y.prerelease) {
+    return x.prerelease ? -1 : 1;
+  }
+  return x.prerelease < y.prerelease ? -1 : 1;
+}
+
+/**
+ * Answer version of the project: the one its go.mod requires, for a build of
+ * Answer with plugins, or the VERSION of the Makefile of Answer's own source
+ */
+export function detectAnswerVersion(
+  answerProjectPath: string
+): string | undefined {
+  const goModPath = getConfigPath(answerProjectPath, "goMod");
+  if (!fs.existsSync(goModPath)) {
+    return undefined;
+  }
+  const goMod = readGoMod(goModPath);
+  const required = goMod.Require.find(
+    (mod) => mod.Path === ANSWER_MODULE
+  )?.Version;
+
+  let version = required;
+  const makefilePath = path.resolve(answerProjectPath, "Makefile");
+  if (
+    !version &&
+    goMod.Module.Path === ANSWER_MODULE &&
+    fs.existsSync(makefilePath)
+  ) {
+    const match = fs
+      .readFileSync(makefilePath, "utf-8")
+      .match(/^VERSION\s*[:?]?=\s*(\S+)/m);
+    version = match ? `v${match[1].replace(/^v/, "")}` : undefined;
+  }
+  return version && VERSION_PATTERN.test(version) ? version : undefined;
+}
+
+/**
+ * Answer version to generate a plugin for: the project's, or the default
+ * when it can't be detected. Versions older than the templates support are
+ * rejected, newer ones than the compatibility table knows are warned about.
+ */
+export function resolveAnswerVersion(answerProjectPath: string): string {
+  const logger = getLogger();
+  const version = detectAnswerVersion(answerProjectPath);
+
+  if (!version) {
+    logger.warn(
+      `Answer version of ${answerProjectPath} not detected, generating for ${DEFAULT_ANSWER_VERSION}`
+    );
+    return DEFAULT_ANSWER_VERSION;
+  }
+  if (compareVersions(version, MIN_ANSWER_VERSION) < 0) {
+    throw new ValidationError(
+      `Answer ${version} is older than ${MIN_ANSWER_VERSION}, the oldest version plugins can be generated for`
+    );
+  }
+  if (compareVersions(version, DEFAULT_ANSWER_VERSION) > 0) {
+    logger.warn(
+      `Answer ${version} is newer than ${DEFAULT_ANSWER_VERSION}, the newest version the templates are known to match. Check the plugin interfaces the plugin implements against it.`
+    );
+  }
+  logger.debug(`Generating for Answer ${version}`);
+  return version;
+}
+
+/**
+ * Version of github.com/apache/answer-plugins/util that builds with the
+ * Answer version
+ */
+export function utilVersion(answerVersion: string): string {
+  const match = UTIL_VERSIONS.find(
+    ({ answer }) => compareVersions(answerVersion, answer) >= 0
+  );
+  return (match ?? UTIL_VERSIONS[UTIL_VERSIONS.length - 1]).util;
+}
+
+const meets = (answerVersion: string, required?: string): boolean =>
+  !required || compareVersions(answerVersion, required) >= 0;
+
+/**
+ * Check that the Answer version has what the capabilities of a plugin need,
+ * and warn about the interfaces of them that change after it. The plugin is
+ * generated with their older signature.
+ */
+export function checkCompatibility(
+  answerVersion: string,
+  capabilities: string[]
+): void {
+  const unmet = capabilities.filter(
+    (capability) =>
+      !meets(answerVersion, CAPABILITY_REQUIREMENTS[capability]?.version)
+  );
+  if (unmet.length > 0) {
+    throw new ValidationError(
+      unmet
+        .map((capability) => {
+          const { version, reason } = CAPABILITY_REQUIREMENTS[capability];
+          return `${capability} needs Answer ${version} for ${reason}, the project runs ${answerVersion}`;
+        })
+        .join("\n")
+    );
+  }
+
+  const logger = getLogger();
+  for (const change of INTERFACE_CHANGES) {
+    if (
+      capabilities.includes(change.capability) &&
+      compareVersions(answerVersion, change.version) < 0
+    ) {
+      logger.warn(
+        `The ${change.capability} interface changes in Answer ${change.version}: ${change.description}. The plugin is generated for ${answerVersion}, update it when upgrading Answer.`
+      );
+    }
+  }
+}
+
+/**
+ * Template file to use for the Answer version: the one of
+ * template/compat/<version>, for the first interface change after it that
+ * has the file, or the template itself
+ */
+export function compatTemplatePath(
+  templatePath: string,
+  answerVersion: string
+): string {
+  const relativePath = path.relative(
+    path.resolve(rootDir, "template"),
+    templatePath
+  );
+  const versions = [...new Set(INTERFACE_CHANGES.map((c) => c.version))]
+    .filter((version) => compareVersions(answerVersion, version) < 0)
+    .sort(compareVersions);
+
+  for (const version of versions) {
+    const compatPath = path.resolve(
+      rootDir,
+      TEMPLATE_PATHS.COMPAT,
+      version,
+      relativePath
+    );
+    if (fs.existsSync(compatPath)) {
+      return compatPath;
+    }
+  }
+  return templatePath;
+}
+
+/**
+ * Whether a file of the testkit, by name, is generated for the Answer version
+ */
+export function hasTestkitFile(answerVersion: string, file: string): boolean {
+  return meets(answerVersion, TESTKIT_REQUIREMENTS[file]?.version);
+}
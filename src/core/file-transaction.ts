@@ -65,6 +65,30 @@ export class FileTransaction {
     }
   }
 
+  /**
+   * Delete file with transaction support, rollback restores it
+   */
+  deleteFile(filePath: string): void {
+    const resolvedPath = path.resolve(filePath)
+
+    if (!this.backups.has(resolvedPath)) {
+      this.backup(resolvedPath)
+    }
+
+    try {
+      if (fs.existsSync(resolvedPath)) {
+        fs.unlinkSync(resolvedPath)
+      }
+      this.modifiedFiles.add(resolvedPath)
+    } catch (error) {
+      this.rollback()
+      throw new FileSystemError(
+        `Failed to delete file: ${resolvedPath}`,
+        resolvedPath
+      )
+    }
+  }
+
   /**
    * Commit transaction (clear backups)
    */
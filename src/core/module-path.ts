@@ -0,0 +1,114 @@
+import fs from "fs";
+import path from "path";
+import { executeCommand } from "../utils/exec.js";
+import { validateModulePath } from "../utils/validators.js";
+import { getConfig, getConfigPath } from "../config/config.js";
+import {
+  DEFAULT_MODULE_PATH,
+  MODULE_PATH_PATTERN,
+  TESTKIT,
+} from "../config/constants.js";
+import { ValidationError } from "../errors/index.js";
+import { readGoMod } from "../utils/goedit.js";
+import { getLogger } from "./logger.js";
+
+/**
+ * Module path the plugins of the Answer project live under, the first of:
+ * the given one (the --module-path flag), ANSWER_PLUGIN_MODULE_PATH, the
+ * modulePath of answer-plugin.config.json, the origin remote of a plugins
+ * repository checked out as the plugins directory, and
+ * github.com/apache/answer-plugins
+ */
+export async function resolveModulePath(
+  answerProjectPath: string,
+  modulePath?: string
+): Promise<string> {
+  const configured = modulePath || getConfig().modulePath;
+  if (configured) {
+    const validation = validateModulePath(configured);
+    if (validation !== true) {
+      throw new ValidationError(validation);
+    }
+    return configured;
+  }
+
+  const detected = await detectModulePath(answerProjectPath);
+  if (detected) {
+    getLogger().debug(`Module path ${detected} detected from the git remote`);
+    return detected;
+  }
+  return DEFAULT_MODULE_PATH;
+}
+
+/**
+ * Module path of the origin remote of the plugins directory, when it's a git
+ * repository of its own. The remote of the Answer project names Answer, not
+ * its plugins.
+ */
+async function detectModulePath(
+  answerProjectPath: string
+): Promise<string | undefined> {
+  const pluginsPath = getConfigPath(answerProjectPath, "plugins");
+  if (!fs.existsSync(pluginsPath)) {
+    return undefined;
+  }
+
+  const config = getConfig();
+  const execOptions = { cwd: pluginsPath, timeout: config.timeouts.default };
+  try {
+    const topLevel = await executeCommand(
+      config.commands.gitTopLevel,
+      execOptions
+    );
+    if (fs.realpathSync(topLevel) !== fs.realpathSync(pluginsPath)) {
+      return undefined;
+    }
+    return remoteModulePath(
+      await executeCommand(config.commands.gitRemoteUrl, execOptions)
+    );
+  } catch (error) {
+    // Not a git repository, or without an origin remote
+    return undefined;
+  }
+}
+
+/**
+ * Module path of a git remote URL, git@host:path as well as
+ * https://host/path, without the .git suffix
+ */
+export function remoteModulePath(url: string): string | undefined {
+  const match =
+    url.match(/^[\w.-]+@([\w.-]+):(?!\/)(.+)$/) ??
+    url.match(/^[a-z][a-z+]*:\/\/(?:[^@/]+@)?([^/:]+)(?::\d+)?\/(.+)$/);
+  if (!match) {
+    return undefined;
+  }
+  const modulePath = `${match[1].toLowerCase()}/${match[2].replace(
+    /(\.git)?\/*$/,
+    ""
+  )}`;
+  return MODULE_PATH_PATTERN.test(modulePath) ? modulePath : undefined;
+}
+
+/**
+ * Module path the go.mod of a directory declares
+ */
+export function readModulePath(dir: string): string | undefined {
+  const goModPath = path.resolve(dir, "go.mod");
+  if (!fs.existsSync(goModPath)) {
+    return undefined;
+  }
+  return readGoMod(goModPath).Module.Path || undefined;
+}
+
+/**
+ * Module path of the testkit: the one of the generated copy, plugins
+ * generated under another module path keep requiring it, or
+ * <module path>/testkit before it's generated
+ */
+export function testkitModulePath(
+  testkitPath: string,
+  modulePath: string
+): string {
+  return readModulePath(testkitPath) ?? `${modulePath}/${TESTKIT.PACKAGE}`;
+}
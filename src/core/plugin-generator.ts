@@ -2,11 +2,39 @@ import fs from "fs";
 import path from "path";
 import { URL, fileURLToPath } from "node:url";
 import { PluginContext } from "../types/index.js";
-import { renderTemplate, copyTemplateFiles } from "./template-engine.js";
+import {
+  renderTemplate,
+  copyTemplateFiles,
+  readTemplateFiles,
+  composeTemplate,
+  goTopLevelNames,
+  goImportNames,
+  mergeTranslations,
+} from "./template-engine.js";
 import { executeCommand } from "../utils/exec.js";
 import { CommandExecutionError } from "../errors/index.js";
 import { getConfig } from "../config/config.js";
 import { getLogger } from "./logger.js";
+import { testkitModulePath } from "./module-path.js";
+import { writeTemplateLock } from "./template-lock.js";
+import {
+  checkCompatibility,
+  compatTemplatePath,
+  hasTestkitFile,
+  utilVersion,
+} from "./answer-version.js";
+import {
+  TEMPLATE_PATHS,
+  TEMPLATE_VARIANTS,
+  TESTKIT,
+  BACKEND_PLUGIN_VARIANTS,
+  BACKEND_REQUIRED_MIXINS,
+  BACKEND_MIXIN_TYPES,
+  BACKEND_TYPE_CONFLICTS,
+  STANDARD_UI_VARIANTS,
+  BackendMixin,
+  BackendPluginType,
+} from "../config/constants.js";
 
 const __dirname = path.dirname(fileURLToPath(new URL(import.meta.url)));
 const rootDir = path.resolve(__dirname, "../../");
@@ -21,39 +49,356 @@ export const createPluginDirectory = (context: PluginContext): void => {
 };
 
 /**
- * Generate Backend Plugin
+ * Go fragment of a template variant, mix-in or UI type, composed with the base template into {package_name}.go
  */
-export const generateBackendPlugin = (context: PluginContext): void => {
+const VARIANT_MAIN_FILE = "plugin.go";
+
+/**
+ * Directory of translations in a template, merged into the plugin's i18n
+ */
+const I18N_DIR = "i18n";
+
+/**
+ * Directory of test fixtures next to the Go helpers
+ */
+const TESTDATA_DIR = "testdata";
+
+/**
+ * Directory of the answer-dev harness, which runs the plugin without Answer
+ */
+const DEV_DIR = "cmd/answer-dev";
+
+/**
+ * Directory of the testkit's package the answer-dev harness runs on
+ */
+const DEVSERVER_DIR = "devserver";
+
+/**
+ * Resolve the backend sub-types the plugin implements, the primary one first
+ */
+export const resolveBackendTypes = (
+  context: PluginContext
+): BackendPluginType[] => {
   if (!context.backendPluginType) {
     throw new Error("Backend plugin type is required");
   }
+  const types = [
+    ...new Set([
+      context.backendPluginType,
+      ...(context.backendPluginTypes ?? []),
+    ]),
+  ];
+  for (const type of types) {
+    const conflict = types.find((t) =>
+      BACKEND_TYPE_CONFLICTS[type]?.includes(t)
+    );
+    if (conflict) {
+      throw new Error(`A plugin can't implement both ${type} and ${conflict}`);
+    }
+  }
+  return types;
+};
+
+/**
+ * Resolve the template directory of the selected variant for a sub-type.
+ * The variant belongs to the sub-type that offers it, the primary one if
+ * none does. Returns undefined for the basic variant, which uses the
+ * single-file template, and for the other sub-types.
+ */
+const resolveVariantPath = (
+  context: PluginContext,
+  type: BackendPluginType
+): string | undefined => {
+  const variant = context.templateVariant;
+  if (!variant || variant === TEMPLATE_VARIANTS.BASIC) {
+    return undefined;
+  }
+  const types = resolveBackendTypes(context);
+  const variantType =
+    types.find((t) => BACKEND_PLUGIN_VARIANTS[t]?.includes(variant)) ??
+    types[0];
+  if (type !== variantType) {
+    return undefined;
+  }
+
+  const variantPath = path.resolve(
+    rootDir,
+    TEMPLATE_PATHS.BACKEND_VARIANTS,
+    type,
+    variant
+  );
+  if (!fs.existsSync(variantPath)) {
+    throw new Error(`Template variant "${variant}" not found for type: ${type}`);
+  }
+  return variantPath;
+};
+
+/**
+ * Resolve the mix-ins to add: the ones the sub-types are built on, then the
+ * ones the user opted in to. Mix-ins none of the sub-types can take are
+ * rejected.
+ */
+const resolveMixins = (
+  context: PluginContext,
+  types: BackendPluginType[]
+): BackendMixin[] => {
+  const required = types.flatMap(
+    (type) => BACKEND_REQUIRED_MIXINS[type] ?? []
+  );
+  for (const mixin of context.mixins ?? []) {
+    if (
+      !required.includes(mixin) &&
+      !types.some((type) => BACKEND_MIXIN_TYPES[mixin]?.includes(type))
+    ) {
+      throw new Error(
+        `Mix-in "${mixin}" is not available for type: ${types.join(", ")}`
+      );
+    }
+  }
+  return [...new Set([...required, ...(context.mixins ?? [])])];
+};
+
+/**
+ * A capability of a backend plugin: a sub-type or a mix-in, with its Go
+ * fragment and the directories of its helper files and translations
+ */
+interface Capability {
+  name: string;
+  fragmentPath?: string;
+  filesPaths: string[];
+  i18nPaths: string[];
+  mixin: boolean;
+}
+
+const resolveCapabilities = (context: PluginContext): Capability[] => {
+  const types = resolveBackendTypes(context);
+  const capabilities: Capability[] = types.map((type) => {
+    const variantPath = resolveVariantPath(context, type);
+    const typeTemplatePath = path.resolve(
+      rootDir,
+      TEMPLATE_PATHS.BACKEND,
+      `${type}.go`
+    );
+    // Files every variant of the type shares, e.g. the reviewer audit log
+    const sharedPath = path.resolve(
+      rootDir,
+      TEMPLATE_PATHS.BACKEND_SHARED,
+      type
+    );
+
+    let fragmentPath: string | undefined = fs.existsSync(typeTemplatePath)
+      ? compatTemplatePath(typeTemplatePath, context.answerVersion)
+      : undefined;
+    if (variantPath) {
+      fragmentPath = path.resolve(variantPath, VARIANT_MAIN_FILE);
+    }
+    const filesPaths = [...(variantPath ? [variantPath] : []), sharedPath];
+    return {
+      name: type,
+      fragmentPath,
+      filesPaths,
+      i18nPaths: filesPaths.map((dir) => path.resolve(dir, I18N_DIR)),
+      mixin: false,
+    };
+  });
+
+  for (const mixin of resolveMixins(context, types)) {
+    const mixinPath = path.resolve(
+      rootDir,
+      TEMPLATE_PATHS.BACKEND_MIXINS,
+      mixin
+    );
+    capabilities.push({
+      name: mixin,
+      fragmentPath: path.resolve(mixinPath, VARIANT_MAIN_FILE),
+      filesPaths: [mixinPath],
+      i18nPaths: [path.resolve(mixinPath, I18N_DIR)],
+      mixin: true,
+    });
+  }
+  return capabilities;
+};
 
+/**
+ * Generate Backend Plugin.
+ * A plugin may implement several sub-types and mix-ins, their fragments are
+ * composed into one struct and their helper files and translations merged.
+ */
+export const generateBackendPlugin = (context: PluginContext): void => {
+  const types = resolveBackendTypes(context);
   const templateContext: Record<string, string> = {
     package_name: context.packageNameForGo,
     plugin_name: context.packageName, // Full package name for import paths
+    module_path: context.modulePath, // Module path the plugin lives under
+    testkit_module: testkitModule(context),
     plugin_display_name: context.pluginDisplayName,
     plugin_slug_name: context.pluginSlugName,
     info_slug_name: context.infoSlugName,
-    plugin_type: context.backendPluginType,
+    plugin_type: types.join(","),
   };
-
-  // Generate Go file from type-specific template
-  const typeTemplatePath = path.resolve(
-    rootDir,
-    `template/backend/${context.backendPluginType}.go`
+  const capabilities = resolveCapabilities(context);
+  checkCompatibility(
+    context.answerVersion,
+    capabilities.map(({ name }) => name)
   );
-  const defaultTemplatePath = path.resolve(rootDir, "template/plugin.go");
 
-  const templatePath = fs.existsSync(typeTemplatePath)
-    ? typeTemplatePath
-    : defaultTemplatePath;
+  // Compose the Go file from the base template and the fragments of the
+  // sub-types and mix-ins
+  const baseTemplatePath = path.resolve(rootDir, TEMPLATE_PATHS.BASE);
+  const base = renderTemplate(
+    fs.readFileSync(baseTemplatePath, "utf-8"),
+    templateContext,
+    baseTemplatePath
+  );
+  const fragments = capabilities
+    .filter(({ fragmentPath }) => fragmentPath)
+    .map(({ name, fragmentPath }) => ({
+      name,
+      source: renderTemplate(
+        fs.readFileSync(fragmentPath!, "utf-8"),
+        templateContext,
+        fragmentPath
+      ),
+    }));
   const goFileName = `${context.packageNameForGo}.go`;
-  const targetFile = path.resolve(context.targetPath, goFileName);
+  const files = new Map<string, { content: string; owner: string }>();
+  files.set(goFileName, {
+    content: composeTemplate(
+      base,
+      fragments,
+      capabilities[0].fragmentPath ?? baseTemplatePath
+    ),
+    owner: "base template",
+  });
 
-  const content = fs.readFileSync(templatePath, "utf-8");
-  const rendered = renderTemplate(content, templateContext, templatePath);
+  // Compose the contract tests of the sub-types that have some, e.g.
+  // template/backend/cache_test.go, into {package_name}_test.go
+  const testFragments = types
+    .map((type) => ({
+      name: type,
+      templatePath: compatTemplatePath(
+        path.resolve(rootDir, TEMPLATE_PATHS.BACKEND, `${type}_test.go`),
+        context.answerVersion
+      ),
+    }))
+    .filter(({ templatePath }) => fs.existsSync(templatePath))
+    .map(({ name, templatePath }) => ({
+      name,
+      templatePath,
+      source: renderTemplate(
+        fs.readFileSync(templatePath, "utf-8"),
+        templateContext,
+        templatePath
+      ),
+    }));
+  if (testFragments.length > 0) {
+    const baseTestPath = path.resolve(rootDir, TEMPLATE_PATHS.BASE_TEST);
+    files.set(`${context.packageNameForGo}_test.go`, {
+      content: composeTemplate(
+        renderTemplate(
+          fs.readFileSync(baseTestPath, "utf-8"),
+          templateContext,
+          baseTestPath
+        ),
+        testFragments,
+        testFragments[0].templatePath
+      ),
+      owner: "base template",
+    });
+  }
 
-  fs.writeFileSync(targetFile, rendered);
+  // The answer-dev harness, a command importing the plugin, runs on the
+  // devserver of the testkit
+  if (hasTestkitFile(context.answerVersion, DEVSERVER_DIR)) {
+    readTemplateFiles(
+      path.resolve(rootDir, TEMPLATE_PATHS.DEV),
+      templateContext
+    ).forEach((content, file) =>
+      files.set(path.join(DEV_DIR, file), { content, owner: "base template" })
+    );
+  }
+
+  // Collect the remaining files (helpers, defaults, tests). Capabilities may
+  // ship the same file, but not two different ones under the same name.
+  for (const capability of capabilities) {
+    for (const dir of capability.filesPaths) {
+      readTemplateFiles(
+        dir,
+        templateContext,
+        (file) => file !== VARIANT_MAIN_FILE && file !== I18N_DIR
+      ).forEach((content, file) => {
+        const existing = files.get(file);
+        if (existing && existing.content !== content) {
+          throw new Error(
+            `${existing.owner} and ${capability.name} both ship ${file}`
+          );
+        }
+        files.set(file, { content, owner: capability.name });
+      });
+    }
+  }
+
+  // All Go files share the package, so their declarations must not clash
+  // with each other nor with the name of a package another file imports
+  const goFiles = [...files].filter(
+    ([file]) => file.endsWith(".go") && path.dirname(file) === "."
+  );
+  const declared = new Map<string, string>();
+  for (const [file, { content }] of goFiles) {
+    for (const name of goTopLevelNames(content)) {
+      if (declared.has(name)) {
+        throw new Error(
+          `${name} is declared in both ${declared.get(name)} and ${file}`
+        );
+      }
+      declared.set(name, file);
+    }
+  }
+  for (const [file, { content }] of goFiles) {
+    for (const name of goImportNames(content)) {
+      if (declared.has(name)) {
+        throw new Error(
+          `${declared.get(name)} declares ${name}, which ${file} imports`
+        );
+      }
+    }
+  }
+
+  for (const [file, { content }] of files) {
+    const targetFile = path.resolve(context.targetPath, file);
+    fs.mkdirSync(path.dirname(targetFile), { recursive: true });
+    fs.writeFileSync(targetFile, content);
+  }
+
+  // Translations of the first sub-type that has some replace the generic
+  // ones, the others and the mix-ins' are merged into them
+  const i18nTargetPath = path.resolve(context.targetPath, I18N_DIR);
+  const replaced = new Set<string>();
+  for (const capability of capabilities) {
+    for (const dir of capability.i18nPaths) {
+      readTemplateFiles(dir, templateContext).forEach((content, file) => {
+        const targetFile = path.resolve(i18nTargetPath, file);
+        fs.mkdirSync(path.dirname(targetFile), { recursive: true });
+        if (!capability.mixin && !replaced.has(file)) {
+          fs.writeFileSync(targetFile, content);
+        } else if (fs.existsSync(targetFile)) {
+          fs.writeFileSync(
+            targetFile,
+            mergeTranslations(
+              fs.readFileSync(targetFile, "utf-8"),
+              content,
+              targetFile
+            )
+          );
+        } else {
+          fs.writeFileSync(targetFile, content);
+        }
+        if (!capability.mixin) {
+          replaced.add(file);
+        }
+      });
+    }
+  }
 
   // Generate info.yaml
   const infoYamlTemplatePath = path.resolve(
@@ -74,6 +419,55 @@ export const generateBackendPlugin = (context: PluginContext): void => {
   }
 };
 
+const isJSONObject = (value: unknown): value is Record<string, unknown> =>
+  typeof value === "object" && value !== null && !Array.isArray(value);
+
+/**
+ * Deep-merges JSON objects, values of the overlay win
+ */
+const mergeJSON = (
+  base: Record<string, unknown>,
+  overlay: Record<string, unknown>
+): Record<string, unknown> => {
+  const merged = { ...base };
+  for (const [key, value] of Object.entries(overlay)) {
+    const current = merged[key];
+    merged[key] =
+      isJSONObject(current) && isJSONObject(value)
+        ? mergeJSON(current, value)
+        : value;
+  }
+  return merged;
+};
+
+/**
+ * Resolve the template variant directory of a Standard UI Plugin, undefined
+ * for sub-types without variants. The first variant is the default.
+ */
+const resolveUIVariantPath = (context: PluginContext): string | undefined => {
+  const type = context.standardPluginType;
+  const variants = (type && STANDARD_UI_VARIANTS[type]) || [];
+  const requested =
+    context.templateVariant !== TEMPLATE_VARIANTS.BASIC
+      ? context.templateVariant
+      : undefined;
+  if (requested && !variants.includes(requested)) {
+    throw new Error(
+      `Template variant "${requested}" not found for type: ${type}`
+    );
+  }
+  const variant = requested ?? variants[0];
+  if (!type || !variant || variant === TEMPLATE_VARIANTS.BASIC) {
+    return undefined;
+  }
+  return path.resolve(
+    rootDir,
+    TEMPLATE_PATHS.STANDARD_UI_VARIANTS,
+    type,
+    variant
+  );
+};
+
 /**
  * Generate Standard UI Plugin
  */
@@ -84,6 +478,8 @@ export const generateStandardUIPlugin = (context: PluginContext): void => {
 
   const templateContext: Record<string, string> = {
     plugin_name: context.packageName, // Full package name for import paths
+    module_path: context.modulePath, // Module path the plugin lives under
+    testkit_module: testkitModule(context),
     package_name: context.packageNameForGo,
     plugin_slug_name: context.pluginSlugName,
     plugin_display_name: context.pluginDisplayName,
@@ -92,16 +488,75 @@ export const generateStandardUIPlugin = (context: PluginContext): void => {
     route_path: context.routePath || "",
   };
 
-  // Generate Go wrapper file
+  const typeTemplatePath = path.resolve(
+    rootDir,
+    `template/ui/types/${context.standardPluginType}`
+  );
+  if (!fs.existsSync(typeTemplatePath)) {
+    throw new Error(
+      `Template not found for type: ${context.standardPluginType}`
+    );
+  }
+  checkCompatibility(context.answerVersion, [context.standardPluginType]);
+
+  // A variant's files take precedence over the type's
+  const variantPath = resolveUIVariantPath(context);
+  const templateDirs = variantPath
+    ? [typeTemplatePath, variantPath]
+    : [typeTemplatePath];
+  const templateFile = (file: string): string =>
+    templateDirs
+      .map((dir) => path.resolve(dir, file))
+      .filter((filePath) => fs.existsSync(filePath))
+      .pop() ?? path.resolve(typeTemplatePath, file);
+
+  // Generate Go wrapper file. Types with a Go side of their own ship a
+  // fragment that is composed with the base template instead.
+  const goFileName = `${context.packageNameForGo}.go`;
+  const goFragmentPath = compatTemplatePath(
+    templateFile(VARIANT_MAIN_FILE),
+    context.answerVersion
+  );
   const goTemplatePath = path.resolve(rootDir, "template/ui/plugin.go");
-  if (fs.existsSync(goTemplatePath)) {
+  if (fs.existsSync(goFragmentPath)) {
+    const basePath = path.resolve(rootDir, TEMPLATE_PATHS.BASE);
+    const composed = composeTemplate(
+      renderTemplate(fs.readFileSync(basePath, "utf-8"), templateContext),
+      [
+        {
+          name: context.standardPluginType,
+          source: renderTemplate(
+            fs.readFileSync(goFragmentPath, "utf-8"),
+            templateContext,
+            goFragmentPath
+          ),
+        },
+      ],
+      goFragmentPath
+    );
+    fs.writeFileSync(path.resolve(context.targetPath, goFileName), composed);
+
+    // Copy the Go helpers next to it, and the fixtures of their tests
+    for (const dir of templateDirs) {
+      copyTemplateFiles(
+        dir,
+        context.targetPath,
+        templateContext,
+        (file) => file.endsWith(".go") && file !== VARIANT_MAIN_FILE
+      );
+      copyTemplateFiles(
+        path.resolve(dir, TESTDATA_DIR),
+        path.resolve(context.targetPath, TESTDATA_DIR),
+        templateContext
+      );
+    }
+  } else if (fs.existsSync(goTemplatePath)) {
     const goContent = fs.readFileSync(goTemplatePath, "utf-8");
     const goRendered = renderTemplate(
       goContent,
       templateContext,
       goTemplatePath
     );
-    const goFileName = `${context.packageNameForGo}.go`;
     fs.writeFileSync(path.resolve(context.targetPath, goFileName), goRendered);
   }
 
@@ -123,20 +578,31 @@ export const generateStandardUIPlugin = (context: PluginContext): void => {
     }
   }
 
-  // Copy type-specific template files
-  const typeTemplatePath = path.resolve(
-    rootDir,
-    `template/ui/types/${context.standardPluginType}`
-  );
-  if (!fs.existsSync(typeTemplatePath)) {
-    throw new Error(
-      `Template not found for type: ${context.standardPluginType}`
-    );
+  // Types and variants may add scripts and dependencies to package.json
+  const packageJsonPath = path.resolve(context.targetPath, "package.json");
+  for (const dir of templateDirs) {
+    const overlayPath = path.resolve(dir, "package.json");
+    if (fs.existsSync(overlayPath) && fs.existsSync(packageJsonPath)) {
+      const merged = mergeJSON(
+        JSON.parse(fs.readFileSync(packageJsonPath, "utf-8")),
+        JSON.parse(
+          renderTemplate(
+            fs.readFileSync(overlayPath, "utf-8"),
+            templateContext,
+            overlayPath
+          )
+        )
+      );
+      fs.writeFileSync(
+        packageJsonPath,
+        JSON.stringify(merged, null, 2) + "\n"
+      );
+    }
   }
 
   // Copy Component.tsx and index.ts
-  const componentPath = path.resolve(typeTemplatePath, "Component.tsx");
-  const indexPath = path.resolve(typeTemplatePath, "index.ts");
+  const componentPath = templateFile("Component.tsx");
+  const indexPath = templateFile("index.ts");
 
   if (fs.existsSync(componentPath)) {
     const content = fs.readFileSync(componentPath, "utf-8");
@@ -153,12 +619,25 @@ export const generateStandardUIPlugin = (context: PluginContext): void => {
     fs.writeFileSync(path.resolve(context.targetPath, "index.ts"), rendered);
   }
 
-  // Copy type-specific i18n files
-  const typeI18nPath = path.resolve(typeTemplatePath, "i18n");
-  if (fs.existsSync(typeI18nPath)) {
-    const i18nTargetPath = path.resolve(context.targetPath, "i18n");
-    fs.mkdirSync(i18nTargetPath, { recursive: true });
-    copyTemplateFiles(typeI18nPath, i18nTargetPath, templateContext);
+  // Copy the TypeScript helpers next to them, e.g. a generated API client
+  for (const dir of templateDirs) {
+    copyTemplateFiles(
+      dir,
+      context.targetPath,
+      templateContext,
+      (file) =>
+        /\.tsx?$/.test(file) && !["Component.tsx", "index.ts"].includes(file)
+    );
+  }
+
+  // Copy type-specific i18n files, then the variant's
+  for (const dir of templateDirs) {
+    const typeI18nPath = path.resolve(dir, I18N_DIR);
+    if (fs.existsSync(typeI18nPath)) {
+      const i18nTargetPath = path.resolve(context.targetPath, I18N_DIR);
+      fs.mkdirSync(i18nTargetPath, { recursive: true });
+      copyTemplateFiles(typeI18nPath, i18nTargetPath, templateContext);
+    }
   }
 };
 
@@ -241,7 +720,7 @@ export const generateReadme = (context: PluginContext): void => {
 
 This is a ${
     context.pluginType === "backend"
-      ? context.backendPluginType
+      ? resolveBackendTypes(context).join(", ")
       : context.standardPluginType
   } type plugin.
 
@@ -252,6 +731,10 @@ This is a ${
 answer-plugin install ${context.packageName}
 \`\`\`
 
+## Publishing
+
+The plugin's tests require the shared testkit, \`${testkitModule(context)} ${TESTKIT.VERSION}\`. Push the testkit with the plugin and tag it \`${TESTKIT.PACKAGE}/${TESTKIT.VERSION}\`, or projects requiring the plugin can't run \`go mod tidy\`.
+
 ## Development
 
 See the plugin documentation for development instructions.
@@ -259,11 +742,60 @@ See the plugin documentation for development instructions.
   fs.writeFileSync(path.resolve(context.targetPath, "README.md"), content);
 };
 
+/**
+ * Directory of the shared testkit, the given one or next to the plugin
+ */
+const testkitPath = (context: PluginContext): string =>
+  context.testkitPath ??
+  path.resolve(context.targetPath, "..", TESTKIT.PACKAGE);
+
+/**
+ * Module path of the testkit the plugin requires
+ */
+const testkitModule = (context: PluginContext): string =>
+  testkitModulePath(testkitPath(context), context.modulePath);
+
+/**
+ * Render the testkit with its template context, the module path and the
+ * Answer version
+ */
+export const renderTestkit = (
+  inputs: Record<string, string>
+): Map<string, string> =>
+  readTemplateFiles(
+    path.resolve(rootDir, TEMPLATE_PATHS.TESTKIT),
+    inputs,
+    (file) => hasTestkitFile(inputs.answer_version, file)
+  );
+
+/**
+ * Copy the shared testkit next to the plugin, the plugin's go.mod replaces
+ * the testkit module with it. Plugins generated side by side share one copy.
+ */
+const copyTestkit = (context: PluginContext): void => {
+  const targetPath = testkitPath(context);
+  if (fs.existsSync(targetPath)) {
+    return;
+  }
+  const inputs = {
+    testkit_module: testkitModule(context),
+    answer_version: context.answerVersion,
+  };
+  const files = renderTestkit(inputs);
+  files.forEach((content, file) => {
+    const filePath = path.resolve(targetPath, file);
+    fs.mkdirSync(path.dirname(filePath), { recursive: true });
+    fs.writeFileSync(filePath, content);
+  });
+  writeTemplateLock(targetPath, inputs, files);
+};
+
 /**
  * Initialize Go module
  */
 export const initGoModule = async (context: PluginContext): Promise<void> => {
-  const modulePath = `github.com/apache/answer-plugins/${context.packageName}`;
+  const modulePath = `${context.modulePath}/${context.packageName}`;
+  const testkit = testkitModule(context);
   const goModPath = path.resolve(context.targetPath, "go.mod");
 
   // Only create go.mod if it doesn't exist
@@ -273,12 +805,16 @@ export const initGoModule = async (context: PluginContext): Promise<void> => {
 go 1.23.0
 
 require (
-	github.com/apache/answer v1.7.0
-	github.com/apache/answer-plugins/util v1.0.3-0.20250107030257-cf94ebc70954
+	github.com/apache/answer ${context.answerVersion}
+	${testkit} ${TESTKIT.VERSION}
+	github.com/apache/answer-plugins/util ${utilVersion(context.answerVersion)}
 )
+
+replace ${testkit} => ../${TESTKIT.PACKAGE}
 `;
     fs.writeFileSync(goModPath, goModContent);
   }
+  copyTestkit(context);
 
   // Run go mod tidy
   const config = getConfig();
@@ -1,10 +1,24 @@
+import { execFileSync } from "child_process";
 import fs from "fs";
 import path from "path";
 import { load } from "js-yaml";
 import { FileTransaction } from "./file-transaction.js";
-import { FileSystemError } from "../errors/index.js";
-import { getConfigPath } from "../config/config.js";
+import {
+  CommandExecutionError,
+  FileSystemError,
+  ValidationError,
+} from "../errors/index.js";
+import { getConfig, getConfigPath } from "../config/config.js";
 import { getLogger } from "./logger.js";
+import { readModulePath, testkitModulePath } from "./module-path.js";
+import { editGoImports, editGoMod, editGoWork } from "../utils/goedit.js";
+import {
+  BACKEND_PLUGIN_TYPES,
+  STANDARD_UI_TYPES,
+  TESTKIT,
+  LOCAL_MODULE_VERSION,
+  DEFAULT_MODULE_PATH,
+} from "../config/constants.js";
 
 export interface PluginInfo {
   name: string;
@@ -16,6 +30,9 @@ export interface PluginInfo {
   slugName: string;
   path: string;
   installed: boolean;
+  // Module path of a published plugin, required at its version rather than
+  // replaced by a directory. Its path is in the module cache.
+  modulePath?: string;
 }
 
 /**
@@ -53,7 +70,7 @@ export function discoverPlugins(answerProjectPath: string): PluginInfo[] {
 /**
  * Read plugin information from directory
  */
-function readPluginInfo(
+export function readPluginInfo(
   pluginPath: string,
   pluginName: string
 ): PluginInfo | null {
@@ -67,31 +84,20 @@ function readPluginInfo(
     const infoYamlContent = fs.readFileSync(infoYamlPath, "utf-8");
     const info = load(infoYamlContent) as any;
 
-    // Determine plugin type
-    const pluginType = info.type;
+    // Determine plugin type, a composite backend plugin lists its
+    // sub-types separated by commas
+    const pluginType = String(info.type ?? "");
     let type: "backend" | "standard-ui" = "backend";
     let subType: string | undefined;
 
-    // Backend plugin types
-    const backendTypes = [
-      "connector",
-      "storage",
-      "cache",
-      "search",
-      "user-center",
-      "notification",
-      "reviewer",
-    ];
-    if (backendTypes.includes(pluginType)) {
+    const backendTypes: string[] = Object.values(BACKEND_PLUGIN_TYPES);
+    const standardTypes: string[] = Object.values(STANDARD_UI_TYPES);
+    if (pluginType.split(",").every((t) => backendTypes.includes(t))) {
       type = "backend";
       subType = pluginType;
-    } else {
-      // Standard UI plugin types
-      const standardTypes = ["editor", "route", "captcha", "render"];
-      if (standardTypes.includes(pluginType)) {
-        type = "standard-ui";
-        subType = pluginType;
-      }
+    } else if (standardTypes.includes(pluginType)) {
+      type = "standard-ui";
+      subType = pluginType;
     }
 
     return {
@@ -111,7 +117,18 @@ function readPluginInfo(
 }
 
 /**
- * Check if a plugin is installed in the Answer project
+ * Options of installPlugins and uninstallPlugins
+ */
+export interface InstallOptions {
+  // Edit go.work instead of go.mod, so the committed go.mod stays untouched
+  workspace?: boolean;
+  // GOPROXY published plugins are fetched from, e.g. file:///path/to/proxy
+  proxy?: string;
+}
+
+/**
+ * Check if a plugin is installed in the Answer project, it's imported by
+ * main.go and replaced in go.mod or used by go.work
  */
 export function checkInstallationStatus(
   plugins: PluginInfo[],
@@ -119,9 +136,11 @@ export function checkInstallationStatus(
 ): PluginInfo[] {
   const mainGoPath = getConfigPath(answerProjectPath, "mainGo");
   const goModPath = getConfigPath(answerProjectPath, "goMod");
+  const goWorkPath = getConfigPath(answerProjectPath, "goWork");
 
   let mainGoContent = "";
   let goModContent = "";
+  let workspaceDirs: string[] = [];
 
   if (fs.existsSync(mainGoPath)) {
     mainGoContent = fs.readFileSync(mainGoPath, "utf-8");
@@ -131,12 +150,25 @@ export function checkInstallationStatus(
     goModContent = fs.readFileSync(goModPath, "utf-8");
   }
 
+  if (fs.existsSync(goWorkPath)) {
+    // Lines of a go.work as written by the helper: "use ./dir" or "\t./dir"
+    workspaceDirs = fs
+      .readFileSync(goWorkPath, "utf-8")
+      .split("\n")
+      .map((line) => line.replace(/^\s*(use\s+)?/, "").trim());
+  }
+
   return plugins.map((plugin) => {
-    const importPath = `github.com/apache/answer-plugins/${plugin.packageName}`;
+    const importPath = pluginImportPath(plugin);
     const isImported =
       mainGoContent.includes(`"${importPath}"`) ||
       mainGoContent.includes(`_ "${importPath}"`);
-    const hasReplace = goModContent.includes(`replace ${importPath}`);
+    // Replacements inside a replace block don't repeat the keyword, a
+    // published plugin is only required
+    const hasReplace = plugin.modulePath
+      ? goModContent.includes(`${importPath} v`)
+      : goModContent.includes(`${importPath} =>`) ||
+        workspaceDirs.includes(localDir(answerProjectPath, plugin.path));
 
     return {
       ...plugin,
@@ -146,19 +178,123 @@ export function checkInstallationStatus(
 }
 
 /**
- * Install plugins by adding them to main.go and go.mod
+ * Import path of the plugin in main.go, the module its go.mod declares
+ */
+function pluginImportPath(plugin: PluginInfo): string {
+  return (
+    plugin.modulePath ??
+    readModulePath(plugin.path) ??
+    `${DEFAULT_MODULE_PATH}/${plugin.packageName}`
+  );
+}
+
+/**
+ * go.work only uses directories, published plugins need go.mod
+ */
+function checkWorkspacePlugins(plugins: PluginInfo[]): void {
+  const published = plugins.filter((plugin) => plugin.modulePath);
+  if (published.length > 0) {
+    throw new ValidationError(
+      `Published plugins are required in go.mod, not go.work: ${published
+        .map((plugin) => plugin.modulePath)
+        .join(", ")}`
+    );
+  }
+}
+
+/**
+ * Directory relative to the Answer project, as go.mod and go.work spell it
+ */
+function localDir(answerProjectPath: string, dir: string): string {
+  return "./" + path.relative(answerProjectPath, dir).split(path.sep).join("/");
+}
+
+/**
+ * Directory of the testkit, next to the plugins
+ */
+function testkitDir(answerProjectPath: string): string {
+  return path.resolve(
+    getConfigPath(answerProjectPath, "plugins"),
+    TESTKIT.PACKAGE
+  );
+}
+
+/**
+ * Module path of the testkit, as its go.mod declares it
+ */
+function testkitModule(answerProjectPath: string): string {
+  return testkitModulePath(testkitDir(answerProjectPath), DEFAULT_MODULE_PATH);
+}
+
+/**
+ * Whether the plugin's go.mod requires the shared testkit
+ */
+function requiresTestkit(plugin: PluginInfo, testkit: string): boolean {
+  const goModPath = path.resolve(plugin.path, "go.mod");
+  return (
+    fs.existsSync(goModPath) &&
+    fs.readFileSync(goModPath, "utf-8").includes(testkit)
+  );
+}
+
+/**
+ * Run go get on published plugins in the Answer project, with the GOPROXY
+ * they were resolved from
+ */
+function getPluginModules(
+  plugins: PluginInfo[],
+  answerProjectPath: string,
+  options: InstallOptions
+): void {
+  const config = getConfig();
+  const args = plugins.map(
+    (plugin) => `${plugin.modulePath}@${plugin.version}`
+  );
+  getLogger().debug(`go get ${args.join(" ")}`);
+  try {
+    execFileSync("go", ["get", ...args], {
+      cwd: answerProjectPath,
+      encoding: "utf-8",
+      env: {
+        ...process.env,
+        GOWORK: "off",
+        ...(options.proxy ? { GOPROXY: options.proxy } : {}),
+      },
+      stdio: ["ignore", "pipe", "pipe"],
+      timeout: config.timeouts.goModDownload,
+    });
+  } catch (error: any) {
+    throw new CommandExecutionError(
+      `go get failed for ${args.join(", ")}:\n${String(
+        error.stderr || error.message || ""
+      ).trim()}`,
+      `go get ${args.join(" ")}`,
+      error.status ?? undefined
+    );
+  }
+}
+
+/**
+ * Install plugins by adding them to main.go, and to go.mod or go.work
  * Uses transaction to ensure atomicity
  */
 export function installPlugins(
   plugins: PluginInfo[],
-  answerProjectPath: string
+  answerProjectPath: string,
+  options: InstallOptions = {}
 ): void {
   const mainGoPath = getConfigPath(answerProjectPath, "mainGo");
   const goModPath = getConfigPath(answerProjectPath, "goMod");
+  const goWorkPath = getConfigPath(answerProjectPath, "goWork");
+  const goSumPath = path.resolve(path.dirname(goModPath), "go.sum");
   const logger = getLogger();
 
   logger.debug(`Installing ${plugins.length} plugin(s)`);
 
+  if (options.workspace) {
+    checkWorkspacePlugins(plugins);
+  }
+
   if (!fs.existsSync(mainGoPath)) {
     throw new FileSystemError(`main.go not found at ${mainGoPath}`, mainGoPath);
   }
@@ -170,71 +306,65 @@ export function installPlugins(
   const transaction = new FileTransaction();
 
   try {
-    // Backup files before modification
+    // Backup files before modification, a go.work created here is removed
+    // on rollback
     transaction.backup(mainGoPath);
-    transaction.backup(goModPath);
-
-    let mainGoContent = fs.readFileSync(mainGoPath, "utf-8");
-    let goModContent = fs.readFileSync(goModPath, "utf-8");
-
-    for (const plugin of plugins) {
-      const importPath = `github.com/apache/answer-plugins/${plugin.packageName}`;
-      const localPath = `./ui/src/plugins/${plugin.packageName}`;
-
-      // Add import to main.go if not already present
-      if (!mainGoContent.includes(`"${importPath}"`)) {
-        // Find the import block
-        const importBlockRegex = /import\s*\(([\s\S]*?)\)/;
-        const match = mainGoContent.match(importBlockRegex);
-
-        if (match) {
-          // Add import inside existing import block
-          const imports = match[1];
-          const newImport = `\t_ "${importPath}"\n`;
-
-          // Check if already imported
-          if (!imports.includes(`"${importPath}"`)) {
-            // Preserve existing imports and add new one
-            const trimmedImports = imports.trim();
-            const newImports =
-              trimmedImports + (trimmedImports ? "\n" : "") + newImport;
-            mainGoContent = mainGoContent.replace(
-              importBlockRegex,
-              `import (\n${newImports})`
-            );
-          }
-        } else {
-          // No import block, check for single import
-          const singleImportRegex = /import\s+"([^"]+)"/;
-          const singleMatch = mainGoContent.match(singleImportRegex);
-
-          if (singleMatch) {
-            // Convert single import to block
-            mainGoContent = mainGoContent.replace(
-              singleImportRegex,
-              `import (\n\t"${singleMatch[1]}"\n\t_ "${importPath}"\n)`
-            );
-          } else {
-            // Add import block after package declaration
-            mainGoContent = mainGoContent.replace(
-              /(package\s+\w+\s*\n)/,
-              `$1\nimport (\n\t_ "${importPath}"\n)\n`
-            );
-          }
-        }
-      }
+    transaction.backup(options.workspace ? goWorkPath : goModPath);
+
+    // Add the blank imports to the dedicated group of main.go
+    const mainGoContent = editGoImports(
+      "add",
+      mainGoPath,
+      plugins.map(pluginImportPath)
+    );
+    transaction.writeFile(mainGoPath, mainGoContent);
 
-      // Add replace directive to go.mod if not already present
-      const replaceDirective = `replace ${importPath} => ${localPath}`;
-      if (!goModContent.includes(replaceDirective)) {
-        // Add replace directive at the end of go.mod
-        goModContent = goModContent.trim() + "\n\n" + replaceDirective + "\n";
-      }
+    // A local plugin's tests require the testkit next to it, which may be
+    // ahead of its published tag, so Answer takes it from that directory too.
+    // Published plugins get the tagged testkit from the module proxy.
+    const local = plugins.filter((plugin) => !plugin.modulePath);
+    const modules = local.map((plugin) => ({
+      path: pluginImportPath(plugin),
+      version: LOCAL_MODULE_VERSION,
+      dir: localDir(answerProjectPath, plugin.path),
+    }));
+    const testkit = testkitModule(answerProjectPath);
+    if (local.some((plugin) => requiresTestkit(plugin, testkit))) {
+      modules.push({
+        path: testkit,
+        version: TESTKIT.VERSION,
+        dir: localDir(answerProjectPath, testkitDir(answerProjectPath)),
+      });
     }
 
-    // Write files using transaction
-    transaction.writeFile(mainGoPath, mainGoContent);
-    transaction.writeFile(goModPath, goModContent);
+    if (options.workspace) {
+      // go.work uses the plugins' modules, no require or replace needed
+      const goWorkContent = editGoWork(
+        "add",
+        goWorkPath,
+        modules.map((m) => m.dir)
+      );
+      transaction.writeFile(goWorkPath, goWorkContent);
+    } else {
+      // Require each plugin and replace it by its directory, published
+      // plugins are required at their version from the module proxy
+      const goModContent = editGoMod("add", goModPath, [
+        ...modules.map((m) => `${m.path}@${m.version}=${m.dir}`),
+        ...plugins
+          .filter((plugin) => plugin.modulePath)
+          .map((plugin) => `${plugin.modulePath}@${plugin.version}`),
+      ]);
+      transaction.writeFile(goModPath, goModContent);
+
+      // go get records the checksums of the published plugins and of their
+      // dependencies in go.sum, and requires what they need that Answer
+      // doesn't. A plugin or dependency the proxy can't serve rolls back.
+      const published = plugins.filter((plugin) => plugin.modulePath);
+      if (published.length > 0) {
+        transaction.backup(goSumPath);
+        getPluginModules(published, answerProjectPath, options);
+      }
+    }
 
     // Commit transaction
     transaction.commit();
@@ -246,70 +376,85 @@ export function installPlugins(
 }
 
 /**
- * Uninstall plugins by removing them from main.go and go.mod
+ * Uninstall plugins by removing them from main.go, and from go.mod or go.work
  * Uses transaction to ensure atomicity
  */
 export function uninstallPlugins(
   plugins: PluginInfo[],
-  answerProjectPath: string
+  answerProjectPath: string,
+  options: InstallOptions = {}
 ): void {
   const mainGoPath = getConfigPath(answerProjectPath, "mainGo");
   const goModPath = getConfigPath(answerProjectPath, "goMod");
+  const goWorkPath = getConfigPath(answerProjectPath, "goWork");
+  const goFilePath = options.workspace ? goWorkPath : goModPath;
   const logger = getLogger();
 
   logger.debug(`Uninstalling ${plugins.length} plugin(s)`);
 
+  if (options.workspace) {
+    checkWorkspacePlugins(plugins);
+  }
+
   if (!fs.existsSync(mainGoPath)) {
     throw new FileSystemError(`main.go not found at ${mainGoPath}`, mainGoPath);
   }
 
-  if (!fs.existsSync(goModPath)) {
-    throw new FileSystemError(`go.mod not found at ${goModPath}`, goModPath);
+  if (!fs.existsSync(goFilePath)) {
+    throw new FileSystemError(
+      `${path.basename(goFilePath)} not found at ${goFilePath}`,
+      goFilePath
+    );
   }
 
+  // Drop the testkit once no installed plugin requires it
+  const testkit = testkitModule(answerProjectPath);
+  const removed = new Set(plugins.map((plugin) => plugin.packageName));
+  const keepTestkit = checkInstallationStatus(
+    discoverPlugins(answerProjectPath),
+    answerProjectPath
+  ).some(
+    (plugin) =>
+      plugin.installed &&
+      !removed.has(plugin.packageName) &&
+      requiresTestkit(plugin, testkit)
+  );
+
   const transaction = new FileTransaction();
 
   try {
     // Backup files before modification
     transaction.backup(mainGoPath);
-    transaction.backup(goModPath);
-
-    let mainGoContent = fs.readFileSync(mainGoPath, "utf-8");
-    let goModContent = fs.readFileSync(goModPath, "utf-8");
-
-    for (const plugin of plugins) {
-      const importPath = `github.com/apache/answer-plugins/${plugin.packageName}`;
-      const replaceDirective = `replace ${importPath} =>`;
+    transaction.backup(goFilePath);
+
+    // Remove the blank imports from main.go, with their comments
+    const mainGoContent = editGoImports(
+      "remove",
+      mainGoPath,
+      plugins.map(pluginImportPath)
+    );
+    transaction.writeFile(mainGoPath, mainGoContent);
 
-      // Remove import from main.go
-      // Remove the entire import line
-      mainGoContent = mainGoContent.replace(
-        new RegExp(`\t_ "${importPath}"\\s*\n`, "g"),
-        ""
+    if (options.workspace) {
+      const dirs = plugins.map((plugin) =>
+        localDir(answerProjectPath, plugin.path)
       );
-      mainGoContent = mainGoContent.replace(
-        new RegExp(`\t"${importPath}"\\s*\n`, "g"),
-        ""
-      );
-
-      // Remove replace directive from go.mod
-      const replaceRegex = new RegExp(
-        `replace\\s+${importPath.replace(
-          /[.*+?^${}()|[\]\\]/g,
-          "\\$&"
-        )}\\s+=>[^\\n]+\\n`,
-        "g"
+      if (!keepTestkit) {
+        dirs.push(localDir(answerProjectPath, testkitDir(answerProjectPath)));
+      }
+      transaction.writeFile(goWorkPath, editGoWork("remove", goWorkPath, dirs));
+    } else {
+      // Drop the require and replace pair of each plugin
+      const modulePaths = plugins.map(pluginImportPath);
+      if (!keepTestkit) {
+        modulePaths.push(testkit);
+      }
+      transaction.writeFile(
+        goModPath,
+        editGoMod("remove", goModPath, modulePaths)
       );
-      goModContent = goModContent.replace(replaceRegex, "");
     }
 
-    // Clean up empty import blocks
-    mainGoContent = mainGoContent.replace(/import\s*\(\s*\)\s*\n/g, "");
-
-    // Write files using transaction
-    transaction.writeFile(mainGoPath, mainGoContent);
-    transaction.writeFile(goModPath, goModContent);
-
     // Commit transaction
     transaction.commit();
   } catch (error) {
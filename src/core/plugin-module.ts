@@ -0,0 +1,117 @@
+import os from "os";
+import path from "path";
+import { executeCommand } from "../utils/exec.js";
+import { getConfig } from "../config/config.js";
+import { PluginDiscoveryError, ValidationError } from "../errors/index.js";
+import { getLogger } from "./logger.js";
+import { PluginInfo, readPluginInfo } from "./plugin-manager.js";
+
+/**
+ * Module path with an optional version or query, as `go get` takes it. The
+ * first path element is a domain, and no shell metacharacter is allowed.
+ */
+const MODULE_SPEC_PATTERN =
+  /^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+(\/[A-Za-z0-9._~+-]+)+(@[A-Za-z0-9._+-]+)?$/;
+
+export interface ModuleSpec {
+  modulePath: string;
+  query: string;
+}
+
+export interface ResolveOptions {
+  // GOPROXY to resolve from, e.g. file:///path/to/proxy for offline use
+  proxy?: string;
+}
+
+/**
+ * Whether an install argument names a published module rather than a plugin
+ * of the plugins directory, plugin names have no slash
+ */
+export function isModuleSpec(name: string): boolean {
+  return name.includes("/");
+}
+
+/**
+ * Parse module@version, the version defaults to latest
+ */
+export function parseModuleSpec(spec: string): ModuleSpec {
+  if (!MODULE_SPEC_PATTERN.test(spec)) {
+    throw new ValidationError(
+      `Invalid module ${spec}, expected a module path like github.com/apache/answer-plugins/connector-github@v1.2.0`
+    );
+  }
+  const [modulePath, query = "latest"] = spec.split("@");
+  return { modulePath, query };
+}
+
+/**
+ * Plugin info of a published module known by its path only, to uninstall it
+ */
+export function modulePluginInfo(modulePath: string): PluginInfo {
+  parseModuleSpec(modulePath);
+  return {
+    name: modulePath,
+    packageName: path.posix.basename(modulePath),
+    modulePath,
+    type: "backend",
+    version: "",
+    author: "",
+    slugName: "",
+    path: "",
+    installed: false,
+  };
+}
+
+/**
+ * Resolve a published plugin: `go list -m` turns the version query into a
+ * version, `go mod download` fetches the module into the module cache and the
+ * plugin info is read from its info.yaml
+ */
+export async function resolvePluginModule(
+  spec: string,
+  options: ResolveOptions = {}
+): Promise<PluginInfo> {
+  const { modulePath, query } = parseModuleSpec(spec);
+  const config = getConfig();
+  const logger = getLogger();
+  const execOptions = {
+    // Outside of any module, so the replacements of the Answer project, of
+    // a local copy of the plugin, don't get in the way
+    cwd: os.tmpdir(),
+    env: options.proxy
+      ? { ...process.env, GOPROXY: options.proxy }
+      : process.env,
+    timeout: config.timeouts.goModDownload,
+    retries: config.retries.default,
+  };
+
+  const listed = JSON.parse(
+    await executeCommand(
+      `${config.commands.goListModule} ${modulePath}@${query}`,
+      execOptions
+    )
+  );
+  logger.debug(`Resolved ${modulePath}@${query} to ${listed.Version}`);
+
+  const downloaded = JSON.parse(
+    await executeCommand(
+      `${config.commands.goModDownload} ${modulePath}@${listed.Version}`,
+      execOptions
+    )
+  );
+
+  const info = readPluginInfo(downloaded.Dir, modulePath);
+  if (!info) {
+    throw new PluginDiscoveryError(
+      `${modulePath}@${listed.Version} is not an Answer plugin, it has no info.yaml`,
+      downloaded.Dir
+    );
+  }
+
+  return {
+    ...info,
+    packageName: path.posix.basename(modulePath),
+    modulePath,
+    version: listed.Version,
+  };
+}
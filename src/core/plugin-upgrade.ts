@@ -0,0 +1,296 @@
+import fs from "fs";
+import os from "os";
+import path from "path";
+import { PluginContext } from "../types/index.js";
+import {
+  PLUGIN_TYPES,
+  TEMPLATE_LOCK_FILE,
+  TESTKIT,
+} from "../config/constants.js";
+import { getConfig, getConfigPath } from "../config/config.js";
+import { CommandExecutionError, ValidationError } from "../errors/index.js";
+import { executeCommand } from "../utils/exec.js";
+import { mergeFile } from "../utils/merge.js";
+import { FileTransaction } from "./file-transaction.js";
+import { getLogger } from "./logger.js";
+import { detectAnswerVersion } from "./answer-version.js";
+import {
+  createPluginDirectory,
+  generateBackendPlugin,
+  generateI18n,
+  generateInfoYaml,
+  generateReadme,
+  generateStandardUIPlugin,
+  renderTestkit,
+} from "./plugin-generator.js";
+import {
+  TemplateLock,
+  readRenderedFiles,
+  readTemplateLock,
+  templateVersion,
+  writeTemplateLock,
+} from "./template-lock.js";
+
+export interface UpgradeOptions {
+  // Build the Go packages after merging, the upgrade is rolled back if they
+  // don't build. Defaults to true.
+  buildCheck?: boolean;
+}
+
+/**
+ * Files of an upgraded directory, by what happened to them
+ */
+export interface UpgradeResult {
+  path: string;
+  fromVersion: string;
+  toVersion: string;
+  // Unchanged since generated, replaced by the new rendering
+  updated: string[];
+  // Changed both by the author and the templates, merged cleanly
+  merged: string[];
+  // Merged with conflict markers left to resolve
+  conflicts: string[];
+  // New in the templates
+  added: string[];
+  // Dropped by the templates, unchanged since generated
+  removed: string[];
+  // Deleted by the author, or changed by the author and dropped by the
+  // templates, left as they are
+  skipped: string[];
+}
+
+type PluginInputs = Omit<
+  PluginContext,
+  "targetPath" | "answerProjectPath" | "testkitPath"
+>;
+
+/**
+ * Render inputs of a plugin, its context without the paths of this machine
+ */
+const pluginInputs = (context: PluginContext): PluginInputs => {
+  const { targetPath, answerProjectPath, testkitPath, ...inputs } = context;
+  return inputs;
+};
+
+/**
+ * Record what a new plugin was generated from, the base of its upgrades.
+ * Called right after its files are generated, before go.mod and the
+ * dependencies are added.
+ */
+export const recordPluginTemplate = (context: PluginContext): void => {
+  writeTemplateLock(
+    context.targetPath,
+    pluginInputs(context),
+    readRenderedFiles(context.targetPath)
+  );
+};
+
+/**
+ * Render the files of a plugin, go.mod aside, with the current templates.
+ * They're generated in a temporary directory, so nothing is left in the
+ * plugins directory if rendering fails, and read back. The shared testkit
+ * is the one next to the plugin.
+ */
+export const renderPlugin = (context: PluginContext): Map<string, string> => {
+  const renderPath = fs.mkdtempSync(path.join(os.tmpdir(), "answer-render-"));
+  const renderContext = {
+    ...context,
+    targetPath: renderPath,
+    testkitPath:
+      context.testkitPath ??
+      path.resolve(path.dirname(context.targetPath), TESTKIT.PACKAGE),
+  };
+
+  try {
+    createPluginDirectory(renderContext);
+    generateI18n(renderContext);
+    if (context.pluginType === PLUGIN_TYPES.BACKEND) {
+      generateBackendPlugin(renderContext);
+    } else {
+      generateStandardUIPlugin(renderContext);
+      generateInfoYaml(renderContext);
+    }
+    generateReadme(renderContext);
+    return readRenderedFiles(renderPath);
+  } finally {
+    fs.rmSync(renderPath, { recursive: true, force: true });
+  }
+};
+
+/**
+ * Upgrade a plugin to the current templates. It's rendered again with the
+ * inputs of its lock, for the Answer version its go.mod requires now, and
+ * the changes since its lock are merged into its files.
+ */
+export const upgradePlugin = async (
+  pluginPath: string,
+  answerProjectPath: string,
+  options: UpgradeOptions = {}
+): Promise<UpgradeResult> => {
+  const lock = readTemplateLock(pluginPath);
+  if (!lock) {
+    throw new ValidationError(
+      `${pluginPath} has no ${TEMPLATE_LOCK_FILE}, it was generated before upgrades were recorded`
+    );
+  }
+
+  const inputs = lock.inputs as unknown as PluginInputs;
+  const context: PluginContext = {
+    ...inputs,
+    targetPath: pluginPath,
+    answerProjectPath,
+    answerVersion: detectAnswerVersion(pluginPath) ?? inputs.answerVersion,
+  };
+  return upgradeFiles(
+    pluginPath,
+    lock,
+    renderPlugin(context),
+    pluginInputs(context),
+    options
+  );
+};
+
+/**
+ * Upgrade the shared testkit of the Answer project to the current templates,
+ * undefined if it has no lock
+ */
+export const upgradeTestkit = async (
+  answerProjectPath: string,
+  options: UpgradeOptions = {}
+): Promise<UpgradeResult | undefined> => {
+  const testkitPath = path.resolve(
+    getConfigPath(answerProjectPath, "plugins"),
+    TESTKIT.PACKAGE
+  );
+  const lock = readTemplateLock(testkitPath);
+  if (!lock) {
+    return undefined;
+  }
+
+  const inputs = lock.inputs as Record<string, string>;
+  const answerVersion =
+    detectAnswerVersion(testkitPath) ?? inputs.answer_version;
+  const upgradeInputs = { ...inputs, answer_version: answerVersion };
+  return upgradeFiles(
+    testkitPath,
+    lock,
+    renderTestkit(upgradeInputs),
+    upgradeInputs,
+    options
+  );
+};
+
+/**
+ * Merge into the files of a directory what changed between the rendering
+ * its lock recorded and a new one, then record the new one. Go packages
+ * that don't build after it are rolled back.
+ */
+const upgradeFiles = async (
+  dir: string,
+  lock: TemplateLock,
+  rendered: Map<string, string>,
+  inputs: object,
+  options: UpgradeOptions
+): Promise<UpgradeResult> => {
+  const { buildCheck = true } = options;
+  const logger = getLogger();
+  const transaction = new FileTransaction();
+  const result: UpgradeResult = {
+    path: dir,
+    fromVersion: lock.templateVersion,
+    toVersion: templateVersion(),
+    updated: [],
+    merged: [],
+    conflicts: [],
+    added: [],
+    removed: [],
+    skipped: [],
+  };
+  const files = new Set([...Object.keys(lock.files), ...rendered.keys()]);
+
+  for (const file of [...files].sort()) {
+    const filePath = path.resolve(dir, file);
+    const base: string | undefined = lock.files[file];
+    const next = rendered.get(file);
+    const current = fs.existsSync(filePath)
+      ? fs.readFileSync(filePath, "utf-8")
+      : undefined;
+
+    if (next === undefined) {
+      // Dropped by the templates
+      if (current === base) {
+        transaction.deleteFile(filePath);
+        result.removed.push(file);
+      } else if (current !== undefined) {
+        result.skipped.push(file);
+      }
+      continue;
+    }
+    if (current === undefined) {
+      // Deleted by the author, whether the templates changed it or not
+      if (base === undefined) {
+        transaction.writeFile(filePath, next);
+        result.added.push(file);
+      } else {
+        result.skipped.push(file);
+      }
+      continue;
+    }
+    if (current === next || next === base) {
+      continue;
+    }
+    if (current === base) {
+      transaction.writeFile(filePath, next);
+      result.updated.push(file);
+      continue;
+    }
+
+    // Changed on both sides. A new template file the author already has
+    // merges from an empty base, their differences conflict.
+    const merged = mergeFile(current, base ?? "", next, [
+      `${file} (yours)`,
+      `${file} (template ${result.fromVersion})`,
+      `${file} (template ${result.toVersion})`,
+    ]);
+    transaction.writeFile(filePath, merged.content);
+    (merged.conflicts > 0 ? result.conflicts : result.merged).push(file);
+  }
+  writeTemplateLock(dir, inputs, rendered, transaction);
+
+  // Go files with conflict markers don't build until they're resolved
+  const isGoFile = (file: string) => file.endsWith(".go");
+  const goChanged = [
+    ...result.updated,
+    ...result.merged,
+    ...result.added,
+    ...result.removed,
+  ].some(isGoFile);
+  if (
+    buildCheck &&
+    goChanged &&
+    !result.conflicts.some(isGoFile) &&
+    fs.existsSync(path.resolve(dir, "go.mod"))
+  ) {
+    const config = getConfig();
+    try {
+      await executeCommand(config.commands.goBuild, {
+        cwd: dir,
+        timeout: config.timeouts.goBuild,
+      });
+      logger.debug(`${dir} builds after the upgrade`);
+    } catch (error) {
+      transaction.rollback();
+      if (error instanceof CommandExecutionError) {
+        throw new CommandExecutionError(
+          `${dir} doesn't build after the upgrade, it was rolled back:\n${error.message}`,
+          error.command,
+          error.exitCode
+        );
+      }
+      throw error;
+    }
+  }
+
+  transaction.commit();
+  return result;
+};
@@ -1,6 +1,8 @@
 import fs from "fs";
 import path from "path";
+import { dump, load } from "js-yaml";
 import { TemplateError } from "../errors/index.js";
+import { formatGoSource, isGoSourceError } from "../utils/goedit.js";
 import { getLogger } from "./logger.js";
 
 /**
@@ -54,16 +56,17 @@ export const renderTemplate = (
 };
 
 /**
- * Copies template files from source to destination, rendering them with context.
+ * Reads the template files under sourceDir, rendered with context and keyed
+ * by their path relative to sourceDir.
  */
-export const copyTemplateFiles = (
+export const readTemplateFiles = (
   sourceDir: string,
-  destDir: string,
   context: Record<string, string>,
   fileFilter?: (file: string) => boolean
-): void => {
+): Map<string, string> => {
+  const files = new Map<string, string>();
   if (!fs.existsSync(sourceDir)) {
-    return;
+    return files;
   }
 
   fs.readdirSync(sourceDir).forEach((file) => {
@@ -72,15 +75,682 @@ export const copyTemplateFiles = (
     }
 
     const sourcePath = path.resolve(sourceDir, file);
-    const destPath = path.resolve(destDir, file);
-
     if (fs.statSync(sourcePath).isDirectory()) {
-      fs.mkdirSync(destPath, { recursive: true });
-      copyTemplateFiles(sourcePath, destPath, context, fileFilter);
+      readTemplateFiles(sourcePath, context, fileFilter).forEach(
+        (content, relativePath) =>
+          files.set(path.join(file, relativePath), content)
+      );
     } else {
       const content = fs.readFileSync(sourcePath, "utf-8");
-      const rendered = renderTemplate(content, context);
-      fs.writeFileSync(destPath, rendered);
+      files.set(file, renderTemplate(content, context, sourcePath));
     }
   });
+  return files;
+};
+
+/**
+ * Copies template files from source to destination, rendering them with context.
+ */
+export const copyTemplateFiles = (
+  sourceDir: string,
+  destDir: string,
+  context: Record<string, string>,
+  fileFilter?: (file: string) => boolean
+): void => {
+  readTemplateFiles(sourceDir, context, fileFilter).forEach(
+    (content, relativePath) => {
+      const destPath = path.resolve(destDir, relativePath);
+      fs.mkdirSync(path.dirname(destPath), { recursive: true });
+      fs.writeFileSync(destPath, content);
+    }
+  );
+};
+
+/**
+ * Slots a fragment may fill in the base Go template.
+ * The receiver slot is derived from the fragment's methods.
+ */
+const FRAGMENT_SECTIONS = [
+  "fields",
+  "config",
+  "setup",
+  "defaults",
+  "init",
+  "body",
+] as const;
+
+const DEFAULT_RECEIVER = "p";
+
+/**
+ * Slots that open a block in the base template, a blank line can't lead them
+ */
+const BLOCK_START_SLOTS = ["config", "setup", "defaults"];
+
+/**
+ * Parses the imports of an `import (...)` block or single import lines
+ */
+const parseGoImports = (
+  source: string
+): { imports: string[]; rest: string } => {
+  const imports: string[] = [];
+  const rest = source
+    .replace(/^import \(\n([\s\S]*?)\n\)\n\n?/m, (_, block: string) => {
+      imports.push(...block.split("\n"));
+      return "";
+    })
+    .replace(/^import (.+)\n\n?/gm, (_, spec: string) => {
+      imports.push(spec);
+      return "";
+    });
+
+  return {
+    imports: imports.map((spec) => spec.trim()).filter(Boolean),
+    rest,
+  };
+};
+
+/**
+ * Formats imports the way goimports does: sorted, standard library first
+ */
+const formatGoImports = (specs: string[]): string => {
+  const importPath = (spec: string) => spec.slice(spec.indexOf('"'));
+  const unique = [...new Map(specs.map((s) => [importPath(s), s])).values()];
+  unique.sort((a, b) => (importPath(a) < importPath(b) ? -1 : 1));
+
+  const isStd = (spec: string) =>
+    !importPath(spec).split("/")[0].includes(".");
+  const groups = [unique.filter(isStd), unique.filter((s) => !isStd(s))]
+    .filter((group) => group.length > 0)
+    .map((group) => group.map((spec) => `\t${spec}`).join("\n"));
+
+  return `import (\n${groups.join("\n\n")}\n)\n`;
+};
+
+/**
+ * A Go fragment and the capability it adds, e.g. the type or mix-in name
+ */
+export interface TemplateFragment {
+  name: string;
+  source: string;
+}
+
+interface ParsedFragment {
+  name: string;
+  imports: string[];
+  sections: Record<string, string[]>;
+}
+
+/**
+ * Splits a fragment into its imports and sections
+ */
+const parseFragment = (
+  { name, source }: TemplateFragment,
+  templatePath?: string
+): ParsedFragment => {
+  const sections: Record<string, string[]> = {};
+  const { imports, rest } = parseGoImports(
+    source.replace(/^\/\*[\s\S]*?\*\/\n/, "").replace(/^package .+\n/m, "")
+  );
+
+  let current = "body";
+  for (const line of rest.split("\n")) {
+    const marker = line.match(/^\/\/section:(\w+)\s*$/);
+    if (marker) {
+      current = marker[1];
+      if (!(FRAGMENT_SECTIONS as readonly string[]).includes(current)) {
+        throw new TemplateError(
+          `Unknown template section "${current}"`,
+          templatePath
+        );
+      }
+      continue;
+    }
+    (sections[current] ??= []).push(line);
+  }
+  return { name, imports, sections };
+};
+
+/**
+ * Methods several capabilities may implement, Answer calls each of them only
+ * once so the composed plugin gets a method calling every implementation.
+ * `merge` is how the results are combined, "config" stops at the first error
+ * and gives the capabilities that took the config the previous one back.
+ */
+const MERGEABLE_METHODS: Record<
+  string,
+  {
+    params: string;
+    args: string;
+    merge: "call" | "append" | "config" | "error";
+    doc: string;
+  }
+> = {
+  RegisterUnAuthRouter: {
+    params: "router *gin.RouterGroup",
+    args: "router",
+    merge: "call",
+    doc: "registers the routes of every capability",
+  },
+  RegisterAuthUserRouter: {
+    params: "router *gin.RouterGroup",
+    args: "router",
+    merge: "call",
+    doc: "registers the routes of every capability",
+  },
+  RegisterAuthAdminRouter: {
+    params: "router *gin.RouterGroup",
+    args: "router",
+    merge: "call",
+    doc: "registers the routes of every capability",
+  },
+  SetOperator: {
+    params: "operator *plugin.KVOperator",
+    args: "operator",
+    merge: "call",
+    doc: "hands the KV storage to every capability",
+  },
+  ConfigFields: {
+    params: "",
+    args: "",
+    merge: "append",
+    doc: "lists the config fields of every capability",
+  },
+  ConfigReceiver: {
+    params: "config []byte",
+    args: "config",
+    merge: "config",
+    doc:
+      "passes the config to every capability. If one rejects it, the ones\n" +
+      "// that took it get the previous config back.",
+  },
+  UserConfigFields: {
+    params: "",
+    args: "",
+    merge: "append",
+    doc: "lists the user config fields of every capability",
+  },
+  UserConfigReceiver: {
+    params: "userID string, config []byte",
+    args: "userID, config",
+    merge: "error",
+    doc: "passes the user config to every capability, the first error stops it",
+  },
+};
+
+/**
+ * Turns a capability name into a Go identifier suffix: user-center becomes
+ * UserCenter, short words are initialisms (kv becomes KV)
+ */
+const capabilitySuffix = (name: string): string =>
+  name
+    .split(/[-_]/)
+    .map((word) =>
+      word.length <= 3
+        ? word.toUpperCase()
+        : word[0].toUpperCase() + word.slice(1)
+    )
+    .join("");
+
+interface MethodDeclaration {
+  name: string;
+  // Line range in the body, including the doc comment
+  start: number;
+  end: number;
+  empty: boolean;
+}
+
+/**
+ * Finds the methods a fragment body declares on the plugin struct
+ */
+const findMethods = (
+  body: string[],
+  structName: string
+): MethodDeclaration[] => {
+  const methods: MethodDeclaration[] = [];
+  const header = new RegExp(
+    `^func \\((?:\\w+|\\{\\{slot:receiver\\}\\}) \\*${structName}\\) (\\w+)\\(`
+  );
+  body.forEach((line, index) => {
+    const match = line.match(header);
+    if (!match) {
+      return;
+    }
+    let start = index;
+    while (start > 0 && body[start - 1].startsWith("//")) {
+      start--;
+    }
+    let end = index;
+    if (!line.endsWith("{}")) {
+      while (end < body.length - 1 && body[end] !== "}") {
+        end++;
+      }
+    }
+    const statements = body
+      .slice(index + 1, end)
+      .filter((l) => l.trim() && !l.trim().startsWith("//"));
+    methods.push({ name: match[1], start, end, empty: statements.length === 0 });
+  });
+  return methods;
+};
+
+/**
+ * Writes the method that calls the renamed implementations of a mergeable method
+ */
+const dispatcher = (
+  method: string,
+  structName: string,
+  targets: string[]
+): string => {
+  const { params, args, merge, doc } = MERGEABLE_METHODS[method];
+  const r = "{{slot:receiver}}";
+  const calls = targets.map((target) => `${r}.${target}(${args})`);
+  const funcs = targets.map((target) => `${r}.${target}`).join(", ");
+  let result = "";
+  let statements: string[] = [];
+  switch (merge) {
+    case "call":
+      statements = calls;
+      break;
+    case "append":
+      result = " []plugin.ConfigField";
+      statements = [
+        `fields := ${calls[0]}`,
+        ...calls.slice(1).map((call) => `fields = append(fields, ${call}...)`),
+        "return fields",
+      ];
+      break;
+    case "config":
+      result = " error";
+      statements = [
+        `previous, err := json.Marshal(${r}.Config)`,
+        "if err != nil {",
+        "\treturn err",
+        "}",
+        `receivers := []func(${params}) error{${funcs}}`,
+        "for i, receiver := range receivers {",
+        `\tif err := receiver(${args}); err != nil {`,
+        "\t\t// The previous config was accepted before, so it is again",
+        "\t\tfor _, applied := range receivers[:i] {",
+        "\t\t\t_ = applied(previous)",
+        "\t\t}",
+        "\t\treturn err",
+        "\t}",
+        "}",
+        "return nil",
+      ];
+      break;
+    case "error":
+      result = " error";
+      statements = [
+        `for _, receiver := range []func(${params}) error{${funcs}} {`,
+        `\tif err := receiver(${args}); err != nil {`,
+        "\t\treturn err",
+        "\t}",
+        "}",
+        "return nil",
+      ];
+      break;
+  }
+  return [
+    `// ${method} ${doc}`,
+    `func (${r} *${structName}) ${method}(${params})${result} {`,
+    ...statements.map((statement) => `\t${statement}`),
+    "}",
+  ].join("\n");
+};
+
+/**
+ * Makes the fragments' methods on the plugin struct unique. A method several
+ * fragments implement is kept as is when at most one implementation does
+ * something, otherwise each is renamed after its capability and a method
+ * calling all of them is added. Returns the added methods.
+ */
+const mergeMethods = (
+  parsed: ParsedFragment[],
+  structName: string,
+  templatePath?: string
+): string[] => {
+  const declarations = new Map<
+    string,
+    { fragment: ParsedFragment; method: MethodDeclaration }[]
+  >();
+  for (const fragment of parsed) {
+    for (const method of findMethods(fragment.sections.body ?? [], structName)) {
+      const list = declarations.get(method.name) ?? [];
+      list.push({ fragment, method });
+      declarations.set(method.name, list);
+    }
+  }
+
+  const dispatchers: string[] = [];
+  const removed = new Map<ParsedFragment, MethodDeclaration[]>();
+  for (const [name, list] of declarations) {
+    if (list.length < 2) {
+      continue;
+    }
+    if (!(name in MERGEABLE_METHODS)) {
+      throw new TemplateError(
+        `${list.map(({ fragment }) => fragment.name).join(" and ")} ` +
+          `both implement ${name}`,
+        templatePath
+      );
+    }
+
+    const implemented = list.filter(({ method }) => !method.empty);
+    const kept = implemented.length > 0 ? implemented : [list[0]];
+    for (const entry of list) {
+      if (!kept.includes(entry)) {
+        removed.set(entry.fragment, [
+          ...(removed.get(entry.fragment) ?? []),
+          entry.method,
+        ]);
+      }
+    }
+    if (kept.length === 1) {
+      continue;
+    }
+
+    const targets = kept.map(({ fragment, method }) => {
+      const renamed =
+        name[0].toLowerCase() + name.slice(1) + capabilitySuffix(fragment.name);
+      const body = fragment.sections.body;
+      for (let i = method.start; i <= method.end; i++) {
+        body[i] = body[i]
+          .replace(new RegExp(`^// ${name}\\b`), `// ${renamed}`)
+          .replace(new RegExp(`^(func \\(.+?\\)) ${name}\\(`), `$1 ${renamed}(`);
+      }
+      return renamed;
+    });
+    dispatchers.push(dispatcher(name, structName, targets));
+  }
+
+  // Drop the removed methods and the blank line after them
+  for (const [fragment, methods] of removed) {
+    const body = fragment.sections.body;
+    for (const method of methods.sort((a, b) => b.start - a.start)) {
+      const blank = body[method.end + 1] === "" ? 1 : 0;
+      body.splice(method.start, method.end - method.start + 1 + blank);
+    }
+  }
+  return dispatchers;
+};
+
+/**
+ * Key of a struct field (`Name Type`) or composite literal entry (`Name: value`)
+ */
+const entryKey = (line: string): string | undefined =>
+  line.match(/^\s*(\w+)(?::|\s)/)?.[1];
+
+/**
+ * Drops the struct fields and init entries a previous fragment already
+ * declared the same way, fragments declaring one differently conflict.
+ * An unindented line ends the entries, e.g. a section that closes the
+ * struct to declare types of its own.
+ */
+const mergeEntries = (parsed: ParsedFragment[], templatePath?: string) => {
+  for (const name of ["fields", "config", "defaults", "init"]) {
+    const seen = new Map<string, { fragment: string; entry: string }>();
+    for (const fragment of parsed) {
+      const lines = fragment.sections[name];
+      if (!lines) {
+        continue;
+      }
+      const declared = new Map<string, { fragment: string; entry: string }>();
+      const kept: string[] = [];
+      let comments: string[] = [];
+      let end = lines.findIndex((line) => /^\S/.test(line));
+      if (end === -1) {
+        end = lines.length;
+      }
+      for (const line of lines.slice(0, end)) {
+        if (line.trim().startsWith("//")) {
+          comments.push(line);
+          continue;
+        }
+        const key = entryKey(line);
+        const entry = line.trim().split(/\s+/).join(" ");
+        const previous = key ? seen.get(key) : undefined;
+        if (previous && previous.entry !== entry) {
+          throw new TemplateError(
+            `${previous.fragment} and ${fragment.name} both declare ${key} ` +
+              `in the ${name} section, differently`,
+            templatePath
+          );
+        }
+        if (!previous) {
+          kept.push(...comments, line);
+          if (key) {
+            declared.set(key, { fragment: fragment.name, entry });
+          }
+        }
+        comments = [];
+      }
+      kept.push(...comments, ...lines.slice(end));
+      declared.forEach((value, key) => seen.set(key, value));
+      fragment.sections[name] = kept;
+    }
+  }
+};
+
+/**
+ * Names of the top-level declarations of a Go file, methods excluded
+ */
+export const goTopLevelNames = (source: string): string[] => {
+  const names: string[] = [];
+  let group: string | undefined;
+  for (const line of source.split("\n")) {
+    if (group) {
+      if (line === ")") {
+        group = undefined;
+        continue;
+      }
+      const name = line.match(/^\t(\w+)\b/)?.[1];
+      if (name && name !== "_") {
+        names.push(name);
+      }
+      continue;
+    }
+    const opening = line.match(/^(const|var|type) \($/);
+    if (opening) {
+      group = opening[1];
+      continue;
+    }
+    const declaration = line.match(/^(?:func|type|var|const) (\w+)/);
+    if (declaration && declaration[1] !== "init" && declaration[1] !== "_") {
+      names.push(declaration[1]);
+    }
+  }
+  return names;
+};
+
+/**
+ * Names a Go file's imports are known by, their alias or last path element
+ */
+export const goImportNames = (source: string): string[] =>
+  parseGoImports(source)
+    .imports.map((spec) => {
+      const alias = spec.match(/^(\S+)\s+"/)?.[1];
+      return alias ?? spec.replace(/"/g, "").split("/").pop()!;
+    })
+    .filter((name) => name !== "_" && name !== ".");
+
+/**
+ * Composes a Go file from the base template and fragments, all already
+ * rendered so that imports sort by their final path.
+ *
+ * The base declares its imports normally and marks slots with
+ * `{{slot:name}}` on a line of their own. A fragment is a Go file whose
+ * imports are merged into the base, followed by sections that start with a
+ * `//section:name` line. Text before the first section belongs to the body.
+ * The type fragments come first, mix-ins follow and append to their sections.
+ * The receiver name is taken from the first method in the body, fragments
+ * that don't know it use `{{slot:receiver}}` too.
+ * When several fragments are composed, struct fields and init entries they
+ * share are declared once, and methods they share are merged, see mergeMethods.
+ * Without fragments the base is rendered with empty slots.
+ * The composed file is formatted with go/format, so the columns of merged
+ * entries line up, and composing fails if it isn't valid Go.
+ */
+export const composeTemplate = (
+  base: string,
+  fragments: TemplateFragment[] = [],
+  templatePath?: string
+): string => {
+  const parsed = fragments.map((fragment) =>
+    parseFragment(fragment, templatePath)
+  );
+  const structName = base.match(/^type (\w+) struct/m)?.[1] ?? "";
+
+  let dispatchers: string[] = [];
+  if (parsed.length > 1) {
+    mergeEntries(parsed, templatePath);
+    dispatchers = mergeMethods(parsed, structName, templatePath);
+
+    const declared = new Map<string, string>();
+    for (const fragment of parsed) {
+      const body = (fragment.sections.body ?? []).join("\n");
+      for (const name of goTopLevelNames(body)) {
+        if (declared.has(name)) {
+          throw new TemplateError(
+            `${declared.get(name)} and ${fragment.name} both declare ${name}`,
+            templatePath
+          );
+        }
+        declared.set(name, fragment.name);
+      }
+    }
+  }
+
+  // A blank line opening a section is kept, e.g. to start a new block of
+  // struct fields, except in the body which the base already separates.
+  // The sections of later fragments always start a new block, so gofmt
+  // aligns each fragment's fields on their own.
+  const section = (name: string) =>
+    parsed
+      .map(({ sections }, index) =>
+        (sections[name] ?? [])
+          .join("\n")
+          .replace(/^\n+/, () => (name === "body" ? "" : "\n"))
+          .replace(/^(?=.)/, () => (name !== "body" && index > 0 ? "\n" : ""))
+          .replace(/\s+$/, "")
+      )
+      .concat(name === "body" ? dispatchers : [])
+      .filter(Boolean)
+      .join(name === "body" ? "\n\n" : "\n")
+      .replace(/^\n+/, (blank) =>
+        BLOCK_START_SLOTS.includes(name) ? "" : blank
+      );
+
+  const receiver =
+    section("body").match(/^func \((\w+) \*\w+\)/m)?.[1] ?? DEFAULT_RECEIVER;
+
+  const { imports: baseImports, rest: baseRest } = parseGoImports(base);
+  let composed = baseRest.replace(
+    /^(package .+\n\n)/m,
+    `$1${formatGoImports([
+      ...baseImports,
+      ...parsed.flatMap(({ imports }) => imports),
+      ...(dispatchers.some((d) => d.includes("json.Marshal"))
+        ? ['"encoding/json"']
+        : []),
+    ])}\n`
+  );
+
+  // Fill every slot but the body, then tidy what empty slots left behind
+  composed = composed
+    .replace(/^[ \t]*\{\{slot:(\w+)\}\}\n/gm, (match, name: string) => {
+      if (name === "body") {
+        return match;
+      }
+      const content = section(name);
+      return content ? `${content}\n` : "";
+    })
+    .replace(/struct \{\n\s*\}/g, "struct{}")
+    .replace(/\{\n\s*\}/g, "{}");
+
+  const body = section("body");
+  composed = composed
+    .replace(/^\{\{slot:body\}\}\n/m, () => (body ? `${body}\n` : ""))
+    .replace(/\{\{slot:receiver\}\}/g, receiver);
+
+  const fileName = templatePath ?? `${structName || "composed"}.go`;
+  try {
+    return formatGoSource(composed, fileName);
+  } catch (error: any) {
+    // Without a working Go toolchain the file is still usable, go fmt it later
+    if (!isGoSourceError(error, fileName)) {
+      getLogger().warn(
+        `Couldn't format ${fileName}, it is written unformatted: ${error.message}`
+      );
+      return composed;
+    }
+    throw new TemplateError(
+      `Composing ${parsed.map(({ name }) => name).join(", ") || "the base"} ` +
+        `doesn't give valid Go: ${error.message}`,
+      templatePath
+    );
+  }
+};
+
+/**
+ * Leading comment lines of a file, e.g. the license header
+ */
+const leadingComments = (content: string): string =>
+  content.match(/^(?:#.*\n|\s*\n)*/)?.[0] ?? "";
+
+const mergeYaml = (existing: unknown, incoming: unknown): unknown => {
+  if (
+    existing &&
+    incoming &&
+    typeof existing === "object" &&
+    typeof incoming === "object" &&
+    !Array.isArray(existing)
+  ) {
+    const merged: Record<string, unknown> = { ...(existing as object) };
+    for (const [key, value] of Object.entries(incoming as object)) {
+      merged[key] = key in merged ? mergeYaml(merged[key], value) : value;
+    }
+    return merged;
+  }
+  return existing ?? incoming;
+};
+
+/**
+ * Merges the translations of another capability into a plugin's i18n file.
+ * Keys already present win, so the primary capability names the plugin.
+ * Handles the YAML translation files and the Go file of translation keys,
+ * whose constants must not be declared with different keys.
+ */
+export const mergeTranslations = (
+  existing: string,
+  incoming: string,
+  filePath: string
+): string => {
+  if (filePath.endsWith(".yaml") || filePath.endsWith(".yml")) {
+    const merged = mergeYaml(load(existing), load(incoming));
+    return leadingComments(existing) + dump(merged, { lineWidth: -1 });
+  }
+
+  if (filePath.endsWith(".go")) {
+    const constants = new Map<string, string>();
+    for (const source of [existing, incoming]) {
+      for (const [, name, value] of source.matchAll(/^\t(\w+)\s*=\s*(.+)$/gm)) {
+        if (constants.has(name) && constants.get(name) !== value) {
+          throw new TemplateError(
+            `Translation key ${name} is declared as both ` +
+              `${constants.get(name)} and ${value}`,
+            filePath
+          );
+        }
+        constants.set(name, value);
+      }
+    }
+    const width = Math.max(...[...constants.keys()].map((name) => name.length));
+    const block = [...constants]
+      .map(([name, value]) => `\t${name.padEnd(width)} = ${value}`)
+      .join("\n");
+    return existing.replace(/^const \([\s\S]*?^\)/m, `const (\n${block}\n)`);
+  }
+
+  throw new TemplateError("Don't know how to merge translations", filePath);
 };
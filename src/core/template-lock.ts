@@ -0,0 +1,97 @@
+import fs from "fs";
+import path from "path";
+import { fileURLToPath } from "url";
+import { FileTransaction } from "./file-transaction.js";
+import { TEMPLATE_LOCK_FILE } from "../config/constants.js";
+import { FileSystemError } from "../errors/index.js";
+
+const __dirname = path.dirname(fileURLToPath(new URL(import.meta.url)));
+const rootDir = path.resolve(__dirname, "../../");
+
+/**
+ * What a plugin, or the testkit, was generated from: the version of the
+ * templates, the inputs they were rendered with and the rendered files, by
+ * path relative to the directory. Upgrades merge into the files what changed
+ * between these and a new rendering.
+ */
+export interface TemplateLock {
+  templateVersion: string;
+  inputs: object;
+  files: Record<string, string>;
+}
+
+/**
+ * Version of the templates, the version of this package
+ */
+export const templateVersion = (): string =>
+  JSON.parse(fs.readFileSync(path.resolve(rootDir, "package.json"), "utf-8"))
+    .version;
+
+/**
+ * Read the lock of a directory, undefined if it has none
+ */
+export const readTemplateLock = (dir: string): TemplateLock | undefined => {
+  const lockPath = path.resolve(dir, TEMPLATE_LOCK_FILE);
+  if (!fs.existsSync(lockPath)) {
+    return undefined;
+  }
+  try {
+    return JSON.parse(fs.readFileSync(lockPath, "utf-8"));
+  } catch (error: any) {
+    throw new FileSystemError(
+      `Invalid ${TEMPLATE_LOCK_FILE}: ${error.message}`,
+      lockPath
+    );
+  }
+};
+
+/**
+ * Record the files rendered into a directory and the inputs they were
+ * rendered with
+ */
+export const writeTemplateLock = (
+  dir: string,
+  inputs: object,
+  files: Map<string, string>,
+  transaction?: FileTransaction
+): void => {
+  const lock: TemplateLock = {
+    templateVersion: templateVersion(),
+    inputs,
+    files: Object.fromEntries(
+      [...files].sort(([a], [b]) => a.localeCompare(b))
+    ),
+  };
+  const lockPath = path.resolve(dir, TEMPLATE_LOCK_FILE);
+  const content = `${JSON.stringify(lock, null, 2)}\n`;
+
+  if (transaction) {
+    transaction.writeFile(lockPath, content);
+  } else {
+    fs.writeFileSync(lockPath, content);
+  }
+};
+
+/**
+ * Read the files of a directory, by path relative to it with forward slashes
+ */
+export const readRenderedFiles = (dir: string): Map<string, string> => {
+  const files = new Map<string, string>();
+  const walk = (relativeDir: string) => {
+    fs.readdirSync(path.resolve(dir, relativeDir), {
+      withFileTypes: true,
+    }).forEach((entry) => {
+      const relativePath = path.posix.join(relativeDir, entry.name);
+      if (entry.isDirectory()) {
+        walk(relativePath);
+      } else {
+        files.set(
+          relativePath,
+          fs.readFileSync(path.resolve(dir, relativePath), "utf-8")
+        );
+      }
+    });
+  };
+  walk("");
+  return files;
+};
@@ -1,11 +1,21 @@
-import { PluginType, StandardUIPluginType, BackendPluginType } from '../config/constants.js'
+import { PluginType, StandardUIPluginType, BackendPluginType, TemplateVariant, BackendMixin } from '../config/constants.js'
 import { TransformedNames } from '../utils/name-transformer.js'
 
 export interface PluginContext extends TransformedNames {
   targetPath: string
   answerProjectPath: string
+  // Directory of the shared testkit, next to the plugin unless rendered elsewhere
+  testkitPath?: string
+  // Module path the plugin lives under, its module is modulePath/packageName
+  modulePath: string
+  // Answer version the plugin is generated for, e.g. v1.7.0
+  answerVersion: string
   pluginType: PluginType
   backendPluginType?: BackendPluginType
+  // Sub-types of a composite plugin, backendPluginType is the primary one
+  backendPluginTypes?: BackendPluginType[]
+  templateVariant?: TemplateVariant
+  mixins?: BackendMixin[]
   standardPluginType?: StandardUIPluginType
   routePath?: string
 }
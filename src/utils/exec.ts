@@ -61,12 +61,14 @@ export const executeCommand = async (
     }
   }
 
-  // All retries exhausted
+  // All retries exhausted, the output is kept as some commands report
+  // errors there, like go mod download -json
   const exitCode = (lastError as any)?.code;
+  const stdout = String((lastError as any)?.stdout ?? "").trim();
   throw new CommandExecutionError(
     `Command failed after ${retries + 1} attempt(s): ${command}\n${
       lastError?.message || ""
-    }`,
+    }${stdout ? `\n${stdout}` : ""}`,
     command,
     exitCode
   );
@@ -0,0 +1,125 @@
+import { execFileSync } from "child_process";
+import path from "path";
+import { fileURLToPath } from "url";
+import { CommandExecutionError } from "../errors/index.js";
+import { getConfig } from "../config/config.js";
+import { TOOL_PATHS } from "../config/constants.js";
+
+const __dirname = path.dirname(fileURLToPath(new URL(import.meta.url)));
+const rootDir = path.resolve(__dirname, "../../");
+
+/**
+ * Run a command of the goedit helper on a file and return the edited
+ * content, the file itself is left as it is. Given the source, the file only
+ * names it in errors.
+ */
+function runGoEdit(
+  command: string,
+  filePath: string,
+  args: string[],
+  source?: string
+): string {
+  const [kind, action] = command.split(" ");
+  const flags = source === undefined ? [] : ["-stdin"];
+
+  try {
+    return execFileSync(
+      "go",
+      ["run", ".", kind, action, ...flags, path.resolve(filePath), ...args],
+      {
+        cwd: path.resolve(rootDir, TOOL_PATHS.GOEDIT),
+        encoding: "utf-8",
+        env: { ...process.env, GOWORK: "off" },
+        input: source,
+        stdio: [source === undefined ? "ignore" : "pipe", "pipe", "pipe"],
+        timeout: getConfig().timeouts.goEdit,
+      }
+    );
+  } catch (error: any) {
+    // The helper reports file:line:col positions on stderr, go run adds the
+    // exit status
+    const detail = String(error.stderr || error.message || "")
+      .replace(/\nexit status \d+\s*$/, "")
+      .trim();
+    throw new CommandExecutionError(
+      `goedit ${command} failed on ${filePath}:\n${detail}`,
+      `go run ./${TOOL_PATHS.GOEDIT} ${command}`,
+      error.status ?? undefined
+    );
+  }
+}
+
+/**
+ * Add or remove blank imports of a Go file. The helper edits the syntax tree
+ * rather than the text.
+ */
+export function editGoImports(
+  action: "add" | "remove",
+  filePath: string,
+  importPaths: string[]
+): string {
+  return runGoEdit(`imports ${action}`, filePath, importPaths);
+}
+
+/**
+ * Add or remove modules of a go.mod with golang.org/x/mod/modfile. Adding
+ * takes MODULE@VERSION=DIR arguments for local modules, and writes the
+ * require and replace pair, or MODULE@VERSION for published ones, which are
+ * only required. Removing takes module paths and drops both.
+ */
+export function editGoMod(
+  action: "add" | "remove",
+  filePath: string,
+  args: string[]
+): string {
+  return runGoEdit(`mod ${action}`, filePath, args);
+}
+
+/**
+ * Module path and requirements of a go.mod, as goedit mod json prints them
+ */
+export interface GoModInfo {
+  Module: { Path: string };
+  Require: { Path: string; Version: string }[];
+}
+
+/**
+ * Read the module path and the requirements of a go.mod with
+ * golang.org/x/mod/modfile
+ */
+export function readGoMod(filePath: string): GoModInfo {
+  return JSON.parse(runGoEdit("mod json", filePath, []));
+}
+
+/**
+ * Add or remove use directives of a go.work, adding to a missing go.work
+ * creates it with the module next to it
+ */
+export function editGoWork(
+  action: "add" | "remove",
+  filePath: string,
+  dirs: string[]
+): string {
+  return runGoEdit(`work ${action}`, filePath, dirs);
+}
+
+/**
+ * Format Go source like gofmt, source that doesn't parse is an error at the
+ * line and column of the source, in the file it's named by
+ */
+export function formatGoSource(source: string, filePath: string): string {
+  return runGoEdit("source format", filePath, [], source);
+}
+
+/**
+ * Whether a goedit failure is an error in the file's source, reported at a
+ * file:line:col position, rather than one of the toolchain, e.g. go missing
+ * or a module download failing
+ */
+export function isGoSourceError(error: unknown, filePath: string): boolean {
+  const file = path.resolve(filePath).replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+  return (
+    error instanceof CommandExecutionError &&
+    new RegExp(`^${file}:\\d+:\\d+: `, "m").test(error.message)
+  );
+}
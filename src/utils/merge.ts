@@ -0,0 +1,70 @@
+import { execFileSync } from "child_process";
+import fs from "fs";
+import os from "os";
+import path from "path";
+import { CommandExecutionError } from "../errors/index.js";
+import { getConfig } from "../config/config.js";
+
+export interface MergeResult {
+  content: string;
+  // Number of conflicts, left in content between conflict markers
+  conflicts: number;
+}
+
+/**
+ * Three-way merge with git merge-file: the changes from base to other are
+ * applied to current. The labels name current, base and other in the
+ * conflict markers.
+ */
+export function mergeFile(
+  current: string,
+  base: string,
+  other: string,
+  labels: [string, string, string]
+): MergeResult {
+  const dir = fs.mkdtempSync(path.join(os.tmpdir(), "answer-plugin-merge-"));
+
+  try {
+    const files = (
+      [
+        ["current", current],
+        ["base", base],
+        ["other", other],
+      ] as const
+    ).map(([name, content]) => {
+      const filePath = path.join(dir, name);
+      fs.writeFileSync(filePath, content);
+      return filePath;
+    });
+    const args = [
+      "merge-file",
+      "-p",
+      ...labels.flatMap((label) => ["-L", label]),
+      ...files,
+    ];
+
+    try {
+      const content = execFileSync("git", args, {
+        encoding: "utf-8",
+        stdio: ["ignore", "pipe", "pipe"],
+        timeout: getConfig().timeouts.default,
+      });
+      return { content, conflicts: 0 };
+    } catch (error: any) {
+      // git merge-file exits with the number of conflicts, and with a
+      // negative status on errors
+      if (error.status > 0 && error.status < 128) {
+        return { content: String(error.stdout), conflicts: error.status };
+      }
+      throw new CommandExecutionError(
+        `git merge-file failed on ${labels[0]}:\n${String(
+          error.stderr || error.message || ""
+        ).trim()}`,
+        "git merge-file",
+        error.status ?? undefined
+      );
+    }
+  } finally {
+    fs.rmSync(dir, { recursive: true, force: true });
+  }
+}
@@ -1,5 +1,6 @@
 import path from "path";
 import { ValidationError } from "../errors/index.js";
+import { TESTKIT } from "../config/constants.js";
 
 /**
  * Validate that a path is within the allowed directory
@@ -26,6 +27,11 @@ export function validatePathWithinDirectory(
 const ALLOWED_COMMANDS = new Set([
   "go mod tidy",
   "go mod edit",
+  "go list -m",
+  "go mod download",
+  "go build",
+  "git rev-parse --show-toplevel",
+  "git remote get-url origin",
   "pnpm install",
   "go run",
 ]);
@@ -79,8 +85,14 @@ export function validatePluginNameSecurity(name: string): void {
     throw new ValidationError("Plugin name contains invalid characters");
   }
 
-  // Prevent reserved names
-  const reservedNames = new Set(["node_modules", ".git", "..", "."]);
+  // Prevent reserved names, the testkit is generated next to the plugins
+  const reservedNames = new Set([
+    "node_modules",
+    ".git",
+    "..",
+    ".",
+    TESTKIT.PACKAGE,
+  ]);
   if (reservedNames.has(name)) {
     throw new ValidationError(`Plugin name "${name}" is reserved`);
   }
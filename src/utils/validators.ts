@@ -1,5 +1,6 @@
 import fs from "fs";
 import { getConfigPath } from "../config/config.js";
+import { MODULE_PATH_PATTERN } from "../config/constants.js";
 
 /**
  * Validate plugin name
@@ -22,6 +23,16 @@ export const validateRoutePath = (routePath: string): string | true => {
   return true;
 };
 
+/**
+ * Validate the module path plugins live under
+ */
+export const validateModulePath = (modulePath: string): string | true => {
+  if (!MODULE_PATH_PATTERN.test(modulePath)) {
+    return `Invalid module path ${modulePath}, expected e.g. git.example.com/acme/answer-plugins`;
+  }
+  return true;
+};
+
 /**
  * Validate Answer project path
  */
@@ -21,85 +21,125 @@ package {{package_name}}
 
 import (
 	"context"
-	"embed"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
-	"github.com/apache/answer-plugins/{{plugin_name}}/i18n"
-	"github.com/apache/answer-plugins/util"
+
 	"github.com/apache/answer/plugin"
 )
 
-//go:embed info.yaml
-var Info embed.FS
+//section:fields
 
-type {{plugin_display_name}} struct {
-	Config *{{plugin_display_name}}Config
-}
+	mu    sync.Mutex
+	items map[string]cacheItem
 
-type {{plugin_display_name}}Config struct {
+//section:config
 	Endpoint string `json:"endpoint"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+
+//section:init
+
+		items: map[string]cacheItem{},
+
+//section:body
+// cacheItem is an entry of the in-memory example, a zero expires never expires
+type cacheItem struct {
+	value   string
+	expires time.Time
 }
 
-func init() {
-	plugin.Register(&{{plugin_display_name}}{
-		Config: &{{plugin_display_name}}Config{},
-	})
+// TODO: This is a Hello World example keeping the entries in memory, replace
+// entry and store with a client of the server at Config.Endpoint
+
+// entry returns the entry of key, dropping it once expired. Callers hold c.mu.
+func (c *{{plugin_display_name}}) entry(key string) (item cacheItem, exist bool) {
+	item, exist = c.items[key]
+	if exist && !item.expires.IsZero() && !time.Now().Before(item.expires) {
+		delete(c.items, key)
+		return cacheItem{}, false
+	}
+	return item, exist
 }
 
-func (c *{{plugin_display_name}}) Info() plugin.Info {
-	info := &util.Info{}
-	info.GetInfo(Info)
-
-	return plugin.Info{
-		Name:        plugin.MakeTranslator(i18n.InfoName),
-		SlugName:    info.SlugName,
-		Description: plugin.MakeTranslator(i18n.InfoDescription),
-		Author:      info.Author,
-		Version:     info.Version,
-		Link:        info.Link,
+// store saves value under key, a ttl of 0 keeps it until it is deleted. Callers hold c.mu.
+func (c *{{plugin_display_name}}) store(key, value string, ttl time.Duration) {
+	item := cacheItem{value: value}
+	if ttl > 0 {
+		item.expires = time.Now().Add(ttl)
 	}
+	c.items[key] = item
 }
 
 func (c *{{plugin_display_name}}) GetString(ctx context.Context, key string) (data string, exist bool, err error) {
-	// TODO: Implement cache get logic
-	// This is a Hello World example - implement your cache logic here
-	return "", false, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, exist := c.entry(key)
+	return item.value, exist, nil
 }
 
 func (c *{{plugin_display_name}}) SetString(ctx context.Context, key string, value string, ttl time.Duration) (err error) {
-	// TODO: Implement cache set logic
-	// This is a Hello World example - implement your cache logic here
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store(key, value, ttl)
 	return nil
 }
 
 func (c *{{plugin_display_name}}) GetInt64(ctx context.Context, key string) (data int64, exist bool, err error) {
-	// TODO: Implement cache get logic
-	return 0, false, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, exist := c.entry(key)
+	if !exist {
+		return 0, false, nil
+	}
+	data, err = strconv.ParseInt(item.value, 10, 64)
+	if err != nil {
+		return 0, true, fmt.Errorf("value of %s is not an integer", key)
+	}
+	return data, true, nil
 }
 
 func (c *{{plugin_display_name}}) SetInt64(ctx context.Context, key string, value int64, ttl time.Duration) (err error) {
-	// TODO: Implement cache set logic
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store(key, strconv.FormatInt(value, 10), ttl)
 	return nil
 }
 
+// Increase adds value to the integer under key, a missing key counts from 0.
+// The expiry of an existing entry is kept.
 func (c *{{plugin_display_name}}) Increase(ctx context.Context, key string, value int64) (data int64, err error) {
-	// TODO: Implement cache increase logic
-	return 0, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, exist := c.entry(key)
+	if exist {
+		data, err = strconv.ParseInt(item.value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value of %s is not an integer", key)
+		}
+	}
+	data += value
+	item.value = strconv.FormatInt(data, 10)
+	c.items[key] = item
+	return data, nil
 }
 
 func (c *{{plugin_display_name}}) Decrease(ctx context.Context, key string, value int64) (data int64, err error) {
-	// TODO: Implement cache decrease logic
-	return 0, nil
+	return c.Increase(ctx, key, -value)
 }
 
 func (c *{{plugin_display_name}}) Del(ctx context.Context, key string) (err error) {
-	// TODO: Implement cache delete logic
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
 	return nil
 }
 
+// Flush removes every entry of this cache
 func (c *{{plugin_display_name}}) Flush(ctx context.Context) (err error) {
-	// TODO: Implement cache flush logic
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = map[string]cacheItem{}
 	return nil
 }
-
@@ -0,0 +1,196 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestCacheContract runs the cache contract against the plugin. If your cache
+// needs a server, configure the plugin to use a test instance here.
+func TestCacheContract(t *testing.T) {
+	testCacheContract(t, func(t *testing.T) plugin.Cache {
+		return newPlugin()
+	})
+}
+
+// cacheTTL is the expiry the contract sets, raise it if the server counts in seconds
+const cacheTTL = 100 * time.Millisecond
+
+// testCacheContract checks the behaviour Answer relies on from a plugin.Cache.
+// newCache is called for every subtest, the keys are unique per subtest so a
+// shared server works too.
+func testCacheContract(t *testing.T, newCache func(t *testing.T) plugin.Cache) {
+	ctx := context.Background()
+	key := func(t *testing.T, name string) string {
+		return fmt.Sprintf("contract:%s:%s", t.Name(), name)
+	}
+
+	t.Run("missing keys", func(t *testing.T) {
+		cache := newCache(t)
+		if data, exist, err := cache.GetString(ctx, key(t, "string")); err != nil || exist || data != "" {
+			t.Errorf("GetString = %q, %v, %v, want \"\", false, nil", data, exist, err)
+		}
+		if data, exist, err := cache.GetInt64(ctx, key(t, "int")); err != nil || exist || data != 0 {
+			t.Errorf("GetInt64 = %d, %v, %v, want 0, false, nil", data, exist, err)
+		}
+		if err := cache.Del(ctx, key(t, "string")); err != nil {
+			t.Errorf("Del of a missing key: %v", err)
+		}
+	})
+
+	t.Run("set and overwrite", func(t *testing.T) {
+		cache := newCache(t)
+		k := key(t, "string")
+		for _, value := range []string{"first", "second", ""} {
+			if err := cache.SetString(ctx, k, value, 0); err != nil {
+				t.Fatal(err)
+			}
+			if data, exist, err := cache.GetString(ctx, k); err != nil || !exist || data != value {
+				t.Errorf("GetString = %q, %v, %v, want %q, true, nil", data, exist, err, value)
+			}
+		}
+
+		k = key(t, "int")
+		if err := cache.SetInt64(ctx, k, -42, 0); err != nil {
+			t.Fatal(err)
+		}
+		if data, exist, err := cache.GetInt64(ctx, k); err != nil || !exist || data != -42 {
+			t.Errorf("GetInt64 = %d, %v, %v, want -42, true, nil", data, exist, err)
+		}
+	})
+
+	t.Run("ttl expiry", func(t *testing.T) {
+		cache := newCache(t)
+		expiring, kept := key(t, "expiring"), key(t, "kept")
+		if err := cache.SetString(ctx, expiring, "value", cacheTTL); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.SetInt64(ctx, kept, 1, 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, exist, _ := cache.GetString(ctx, expiring); !exist {
+			t.Fatal("entry expired before its ttl")
+		}
+
+		time.Sleep(cacheTTL + cacheTTL/2)
+		if data, exist, err := cache.GetString(ctx, expiring); err != nil || exist {
+			t.Errorf("GetString after the ttl = %q, %v, %v, want the entry gone", data, exist, err)
+		}
+		if _, exist, err := cache.GetInt64(ctx, kept); err != nil || !exist {
+			t.Errorf("entry without a ttl expired: %v", err)
+		}
+	})
+
+	t.Run("increase and decrease", func(t *testing.T) {
+		cache := newCache(t)
+		k := key(t, "counter")
+		steps := []struct {
+			name string
+			call func() (int64, error)
+			want int64
+		}{
+			{"Increase on a missing key", func() (int64, error) { return cache.Increase(ctx, k, 3) }, 3},
+			{"Increase", func() (int64, error) { return cache.Increase(ctx, k, 2) }, 5},
+			{"Decrease", func() (int64, error) { return cache.Decrease(ctx, k, 7) }, -2},
+		}
+		for _, step := range steps {
+			data, err := step.call()
+			if err != nil || data != step.want {
+				t.Fatalf("%s = %d, %v, want %d", step.name, data, err, step.want)
+			}
+		}
+		if data, exist, err := cache.GetInt64(ctx, k); err != nil || !exist || data != -2 {
+			t.Errorf("GetInt64 = %d, %v, %v, want -2, true, nil", data, exist, err)
+		}
+
+		missing := key(t, "missing")
+		if data, err := cache.Decrease(ctx, missing, 4); err != nil || data != -4 {
+			t.Errorf("Decrease on a missing key = %d, %v, want -4", data, err)
+		}
+	})
+
+	t.Run("increase keeps the ttl", func(t *testing.T) {
+		cache := newCache(t)
+		k := key(t, "counter")
+		if err := cache.SetInt64(ctx, k, 1, cacheTTL); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cache.Increase(ctx, k, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(cacheTTL + cacheTTL/2)
+		if data, exist, err := cache.GetInt64(ctx, k); err != nil || exist {
+			t.Errorf("GetInt64 after the ttl = %d, %v, %v, want the entry gone", data, exist, err)
+		}
+	})
+
+	t.Run("del", func(t *testing.T) {
+		cache := newCache(t)
+		deleted, kept := key(t, "deleted"), key(t, "kept")
+		for _, k := range []string{deleted, kept} {
+			if err := cache.SetString(ctx, k, "value", 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := cache.Del(ctx, deleted); err != nil {
+			t.Fatal(err)
+		}
+		if _, exist, _ := cache.GetString(ctx, deleted); exist {
+			t.Error("deleted entry still exists")
+		}
+		if _, exist, _ := cache.GetString(ctx, kept); !exist {
+			t.Error("Del removed another entry")
+		}
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		cache := newCache(t)
+		if err := cache.SetString(ctx, key(t, "string"), "value", 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.SetInt64(ctx, key(t, "int"), 1, cacheTTL*100); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.Flush(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, exist, _ := cache.GetString(ctx, key(t, "string")); exist {
+			t.Error("string entry survived Flush")
+		}
+		if _, exist, _ := cache.GetInt64(ctx, key(t, "int")); exist {
+			t.Error("int entry survived Flush")
+		}
+
+		// The cache keeps working after a flush
+		if err := cache.SetString(ctx, key(t, "string"), "again", 0); err != nil {
+			t.Fatal(err)
+		}
+		if data, exist, err := cache.GetString(ctx, key(t, "string")); err != nil || !exist || data != "again" {
+			t.Errorf("GetString after Flush = %q, %v, %v, want \"again\", true, nil", data, exist, err)
+		}
+	})
+}
@@ -20,44 +20,14 @@
 package {{package_name}}
 
 import (
-	"embed"
-	"github.com/apache/answer-plugins/{{plugin_name}}/i18n"
-	"github.com/apache/answer-plugins/util"
 	"github.com/apache/answer/plugin"
 )
 
-//go:embed info.yaml
-var Info embed.FS
-
-type {{plugin_display_name}} struct {
-	Config *{{plugin_display_name}}Config
-}
-
-type {{plugin_display_name}}Config struct {
+//section:config
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
-}
-
-func init() {
-	plugin.Register(&{{plugin_display_name}}{
-		Config: &{{plugin_display_name}}Config{},
-	})
-}
-
-func (g *{{plugin_display_name}}) Info() plugin.Info {
-	info := &util.Info{}
-	info.GetInfo(Info)
-
-	return plugin.Info{
-		Name:        plugin.MakeTranslator(i18n.InfoName),
-		SlugName:    info.SlugName,
-		Description: plugin.MakeTranslator(i18n.InfoDescription),
-		Author:      info.Author,
-		Version:     info.Version,
-		Link:        info.Link,
-	}
-}
 
+//section:body
 // ConnectorLogoSVG returns the logo in SVG format
 func (g *{{plugin_display_name}}) ConnectorLogoSVG() string {
 	return `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" fill="currentColor">
@@ -87,12 +57,12 @@ func (g *{{plugin_display_name}}) ConnectorReceiver(ctx *plugin.GinContext, rece
 	// TODO: Implement OAuth callback handling
 	// This is a Hello World example - implement your OAuth callback logic here
 	return plugin.ExternalLoginUserInfo{
-		ExternalID:    "hello-world-user",
-		DisplayName:   "Hello World User",
-		Username:      "helloworld",
-		Email:         "hello@example.com",
-		Avatar:        "",
-		MetaInfo:      "",
+		ExternalID:  "hello-world-user",
+		DisplayName: "Hello World User",
+		Username:    "helloworld",
+		Email:       "hello@example.com",
+		Avatar:      "",
+		MetaInfo:    "",
 	}, nil
 }
 
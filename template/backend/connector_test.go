@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"{{testkit_module}}"
+	"github.com/apache/answer/plugin"
+)
+
+// TestConnectorContract runs the connector contract against the plugin
+func TestConnectorContract(t *testing.T) {
+	testConnectorContract(t, func(t *testing.T) plugin.Connector {
+		return newPlugin()
+	})
+}
+
+// connectorSlugPattern is what the slug may contain, it is part of the login URLs
+var connectorSlugPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// testConnectorContract checks what Answer shows of a plugin.Connector on the
+// login page, and the login endpoints as far as they work without the
+// provider. The exchange with the provider is left to your own tests.
+func testConnectorContract(t *testing.T, newConnector func(t *testing.T) plugin.Connector) {
+	t.Run("slug name", func(t *testing.T) {
+		c := newConnector(t)
+		slug := c.ConnectorSlugName()
+		if !connectorSlugPattern.MatchString(slug) {
+			t.Errorf("ConnectorSlugName() = %q, want letters, digits, _ and - only", slug)
+		}
+		if again := c.ConnectorSlugName(); again != slug {
+			t.Errorf("ConnectorSlugName() changed from %q to %q", slug, again)
+		}
+	})
+
+	t.Run("logo", func(t *testing.T) {
+		c := newConnector(t)
+		var svg struct {
+			XMLName xml.Name
+		}
+		logo := c.ConnectorLogoSVG()
+		if err := xml.NewDecoder(strings.NewReader(logo)).Decode(&svg); err != nil {
+			t.Fatalf("ConnectorLogoSVG() is not valid XML: %v", err)
+		}
+		if svg.XMLName.Local != "svg" {
+			t.Errorf("ConnectorLogoSVG() root element is <%s>, want <svg>", svg.XMLName.Local)
+		}
+	})
+	t.Run("name", func(t *testing.T) {
+		if name := testkit.Translate(t, newConnector(t).ConnectorName()); name == "" {
+			t.Error("ConnectorName() translates to an empty string")
+		}
+	})
+
+	t.Run("login", func(t *testing.T) {
+		c := newConnector(t)
+		base := testkit.UnAuthPrefix + "/connector"
+		receiverURL := "https://answer.example.com" + base + "/redirect/" + c.ConnectorSlugName()
+
+		ctx, _ := testkit.NewGinContext(t, testkit.NewRequest(t, http.MethodGet, base+"/login/"+c.ConnectorSlugName()))
+		redirectURL := c.ConnectorSender(ctx, receiverURL)
+		if redirect, err := url.Parse(redirectURL); err != nil || !redirect.IsAbs() || redirect.Host == "" {
+			t.Errorf("ConnectorSender() = %q, want an absolute URL to send the user to", redirectURL)
+		}
+
+		ctx, _ = testkit.NewGinContext(t, testkit.NewRequest(t, http.MethodGet, receiverURL+"?code=contract&state=contract"))
+		userInfo, err := c.ConnectorReceiver(ctx, receiverURL)
+		if err == nil && userInfo.ExternalID == "" {
+			t.Error("ConnectorReceiver() accepted the login without an ExternalID")
+		}
+	})
+}
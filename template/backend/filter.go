@@ -0,0 +1,192 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/apache/answer/plugin"
+)
+
+//section:fields
+
+	mu      sync.RWMutex
+	chain   Chain
+	blocked []string
+
+//section:config
+	// TicketPattern matches ticket IDs, they are linked once TicketURL is set
+	TicketPattern string `json:"ticket_pattern"`
+	// TicketURL is the link target, $0 is the whole ID and $1... its groups
+	TicketURL string `json:"ticket_url"`
+	// Shortcodes lists one "name = expansion" per line, :name: is replaced
+	Shortcodes    string `json:"shortcodes"`
+	StripTracking bool   `json:"strip_tracking"`
+	// BlockedWords lists one word or phrase per line, posts containing one are rejected
+	BlockedWords string `json:"blocked_words"`
+
+//section:setup
+	chain, err := NewChain(&{{plugin_display_name}}Config{Shortcodes: defaultShortcodes, StripTracking: true})
+	if err != nil {
+		panic(fmt.Sprintf("invalid default rules: %v", err))
+	}
+
+//section:defaults
+			TicketPattern: defaultTicketPattern,
+			Shortcodes:    defaultShortcodes,
+			StripTracking: true,
+
+//section:init
+		chain: chain,
+
+//section:body
+var (
+	_ plugin.Filter = (*{{plugin_display_name}})(nil)
+	_ plugin.Parser = (*{{plugin_display_name}})(nil)
+)
+
+const (
+	defaultTicketPattern = `\b[A-Z][A-Z0-9]+-[0-9]+\b`
+	defaultShortcodes    = "check = ✅\nwarning = ⚠️\ntm = ™"
+)
+
+// NewChain builds the rules the settings enable, in the order they run
+func NewChain(c *{{plugin_display_name}}Config) (Chain, error) {
+	var chain Chain
+	if c.TicketURL != "" {
+		rule, err := TicketLinks(c.TicketPattern, c.TicketURL)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, rule)
+	}
+	expansions, err := ParseShortcodes(c.Shortcodes)
+	if err != nil {
+		return nil, err
+	}
+	if len(expansions) > 0 {
+		chain = append(chain, Shortcodes(expansions))
+	}
+	if c.StripTracking {
+		chain = append(chain, StripTracking(TrackingParams))
+	}
+	return chain, nil
+}
+
+func (f *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:        "ticket_pattern",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigTicketPatternTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigTicketPatternDescription),
+			Value:       f.Config.TicketPattern,
+		},
+		{
+			Name:        "ticket_url",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigTicketURLTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigTicketURLDescription),
+			UIOptions: plugin.ConfigFieldUIOptions{
+				Placeholder: plugin.MakeTranslator(i18n.ConfigTicketURLPlaceholder),
+			},
+			Value: f.Config.TicketURL,
+		},
+		{
+			Name:        "shortcodes",
+			Type:        plugin.ConfigTypeTextarea,
+			Title:       plugin.MakeTranslator(i18n.ConfigShortcodesTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigShortcodesDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{Rows: "6"},
+			Value:       f.Config.Shortcodes,
+		},
+		{
+			Name:  "strip_tracking",
+			Type:  plugin.ConfigTypeSwitch,
+			Title: plugin.MakeTranslator(i18n.ConfigStripTrackingTitle),
+			UIOptions: plugin.ConfigFieldUIOptions{
+				Label: plugin.MakeTranslator(i18n.ConfigStripTrackingLabel),
+			},
+			Value: f.Config.StripTracking,
+		},
+		{
+			Name:        "blocked_words",
+			Type:        plugin.ConfigTypeTextarea,
+			Title:       plugin.MakeTranslator(i18n.ConfigBlockedWordsTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigBlockedWordsDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{Rows: "6"},
+			Value:       f.Config.BlockedWords,
+		},
+	}
+}
+
+// ConfigReceiver rejects settings the rules can't be built from, the previous rules stay active
+func (f *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+	if c.TicketPattern == "" {
+		c.TicketPattern = defaultTicketPattern
+	}
+	chain, err := NewChain(c)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Config = c
+	f.chain = chain
+	f.blocked = ParseBlockedWords(c.BlockedWords)
+	return nil
+}
+
+// BlockedError rejects text containing blocked words
+type BlockedError struct {
+	Words []string
+}
+
+func (e *BlockedError) Error() string {
+	return "the text contains blocked words: " + strings.Join(e.Words, ", ")
+}
+
+// Parse runs the rules on the Markdown of a question, answer or comment
+func (f *{{plugin_display_name}}) Parse(text string) (string, error) {
+	f.mu.RLock()
+	chain := f.chain
+	f.mu.RUnlock()
+	return chain.Apply(text), nil
+}
+
+// FilterText rejects text containing blocked words with a *BlockedError. The
+// rules don't reject anything, Parse applies them.
+func (f *{{plugin_display_name}}) FilterText(text string) error {
+	f.mu.RLock()
+	blocked := f.blocked
+	f.mu.RUnlock()
+
+	if words := FindBlockedWords(text, blocked); len(words) > 0 {
+		return &BlockedError{Words: words}
+	}
+	return nil
+}
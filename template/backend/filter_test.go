@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestFilterContract runs the filter contract against the plugin
+func TestFilterContract(t *testing.T) {
+	testFilterContract(t, func(t *testing.T) plugin.Filter {
+		return newPlugin()
+	})
+}
+
+// testFilterContract checks the behaviour Answer relies on from a plugin.Filter.
+// FilterText only accepts or rejects text, the same text must always get the
+// same verdict, and a rejection explains itself.
+func testFilterContract(t *testing.T, newFilter func(t *testing.T) plugin.Filter) {
+	texts := map[string]string{
+		"plain":    "Nothing to rewrite here.",
+		"empty":    "",
+		"markdown": "# Title\n\n```go\nfmt.Println(\"x\")\n```\n\n- [link](https://example.com/?a=1)\n",
+		"unicode":  "Grüße, 你好 ✅",
+	}
+
+	for name, text := range texts {
+		t.Run(name, func(t *testing.T) {
+			f := newFilter(t)
+			err := f.FilterText(text)
+			again := f.FilterText(text)
+			if (err == nil) != (again == nil) {
+				t.Errorf("FilterText gave %v, then %v for the same text", err, again)
+			}
+			if err != nil && err.Error() == "" {
+				t.Error("FilterText rejected the text with an empty error")
+			}
+		})
+	}
+
+	t.Run("plain text passes", func(t *testing.T) {
+		f := newFilter(t)
+		for _, name := range []string{"plain", "empty"} {
+			if err := f.FilterText(texts[name]); err != nil {
+				t.Errorf("FilterText(%q) = %v, want plain text accepted", texts[name], err)
+			}
+		}
+	})
+}
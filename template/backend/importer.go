@@ -0,0 +1,227 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+//section:fields
+
+	mu       sync.RWMutex
+	importer plugin.ImporterFunc
+	operator *plugin.KVOperator
+	// running allows a single import at a time
+	running sync.Mutex
+
+//section:config
+	// SourcePath is the export file on the server, .json or .csv
+	SourcePath string `json:"source_path"`
+	// DefaultUserEmail authors the records that have no user email
+	DefaultUserEmail string `json:"default_user_email"`
+	// DryRun validates and counts the records without importing them
+	DryRun bool `json:"dry_run"`
+
+//section:defaults
+			DryRun: true,
+
+//section:body
+// importedGroup maps source IDs to the time they were imported
+const importedGroup = "imported_ids"
+
+// ImportReport summarizes an import run
+type ImportReport struct {
+	DryRun   bool     `json:"dry_run"`
+	Total    int      `json:"total"`
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+func (i *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:        "source_path",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigSourcePathTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigSourcePathDescription),
+			Required:    true,
+			Value:       i.Config.SourcePath,
+		},
+		{
+			Name:        "default_user_email",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigDefaultUserEmailTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigDefaultUserEmailDescription),
+			Value:       i.Config.DefaultUserEmail,
+		},
+		{
+			Name:        "dry_run",
+			Type:        plugin.ConfigTypeSwitch,
+			Title:       plugin.MakeTranslator(i18n.ConfigDryRunTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigDryRunDescription),
+			UIOptions: plugin.ConfigFieldUIOptions{
+				Label: plugin.MakeTranslator(i18n.ConfigDryRunLabel),
+			},
+			Value: i.Config.DryRun,
+		},
+	}
+}
+
+func (i *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.Config = c
+	return nil
+}
+
+// RegisterImporterFunc receives the handle Answer creates questions through
+func (i *{{plugin_display_name}}) RegisterImporterFunc(ctx context.Context, importer plugin.ImporterFunc) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.importer = importer
+}
+
+// SetOperator receives the KV storage that remembers which source IDs were imported
+func (i *{{plugin_display_name}}) SetOperator(operator *plugin.KVOperator) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.operator = operator
+}
+
+func (i *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {}
+
+func (i *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {}
+
+// RegisterAuthAdminRouter lets administrators start an import:
+//
+//	POST /{{plugin_slug_name}}/import  run the import, ?dry_run=true|false overrides the setting
+func (i *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {
+	router.POST("/{{plugin_slug_name}}/import", i.runImport)
+}
+
+func (i *{{plugin_display_name}}) runImport(ctx *gin.Context) {
+	i.mu.RLock()
+	dryRun := i.Config.DryRun
+	i.mu.RUnlock()
+	if v := ctx.Query("dry_run"); v != "" {
+		dryRun = v == "true"
+	}
+
+	if !i.running.TryLock() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "an import is already running"})
+		return
+	}
+	defer i.running.Unlock()
+
+	report, err := i.Import(ctx.Request.Context(), dryRun)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "report": report})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// Import pushes the records of the configured export through Answer's importer.
+// Records whose source ID was imported before are skipped, so a failed run can
+// simply be repeated. A dry run validates and counts without importing.
+func (i *{{plugin_display_name}}) Import(ctx context.Context, dryRun bool) (*ImportReport, error) {
+	i.mu.RLock()
+	config, importer, operator := i.Config, i.importer, i.operator
+	i.mu.RUnlock()
+
+	if !dryRun && importer == nil {
+		return nil, errors.New("Answer has not registered the importer yet")
+	}
+	if !dryRun && operator == nil {
+		return nil, errors.New("KV storage is not available, imported IDs could not be remembered")
+	}
+	records, err := ReadRecords(config.SourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{DryRun: dryRun, Total: len(records)}
+	fail := func(record *Record, err error) {
+		report.Failed++
+		report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", record.ID, err))
+	}
+	seen := make(map[string]bool, len(records))
+	for _, record := range records {
+		if record.UserEmail == "" {
+			record.UserEmail = config.DefaultUserEmail
+		}
+		if err := record.Validate(); err != nil {
+			fail(record, err)
+			continue
+		}
+		if seen[record.ID] {
+			fail(record, errors.New("duplicate id in the export"))
+			continue
+		}
+		seen[record.ID] = true
+
+		if operator != nil {
+			_, err := operator.Get(ctx, plugin.KVParams{Group: importedGroup, Key: record.ID})
+			if err == nil {
+				report.Skipped++
+				continue
+			}
+			if !errors.Is(err, plugin.ErrKVKeyNotFound) {
+				fail(record, err)
+				continue
+			}
+		}
+		if dryRun {
+			report.Imported++
+			continue
+		}
+
+		if err := importer.AddQuestion(ctx, record.Question()); err != nil {
+			fail(record, err)
+			continue
+		}
+		err := operator.Set(ctx, plugin.KVParams{
+			Group: importedGroup,
+			Key:   record.ID,
+			Value: time.Now().UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			// The question exists now, a rerun would import it twice
+			return report, fmt.Errorf("record %s imported but not remembered, stopping: %w", record.ID, err)
+		}
+		report.Imported++
+	}
+	return report, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestImporterContract runs the importer contract against the plugin
+func TestImporterContract(t *testing.T) {
+	testImporterContract(t, func(t *testing.T) plugin.Importer {
+		return newPlugin()
+	})
+}
+
+// recordingImporter is the importer Answer would register, it records the questions
+type recordingImporter struct {
+	questions []plugin.QuestionImporterInfo
+}
+
+func (r *recordingImporter) AddQuestion(ctx context.Context, questionInfo plugin.QuestionImporterInfo) error {
+	r.questions = append(r.questions, questionInfo)
+	return nil
+}
+
+// testImporterContract checks the behaviour Answer relies on from a
+// plugin.Importer: Answer registers its importer when the plugin loads, and
+// again when it reloads, questions must only be added once an import runs.
+func testImporterContract(t *testing.T, newImporter func(t *testing.T) plugin.Importer) {
+	t.Run("registering adds no questions", func(t *testing.T) {
+		i := newImporter(t)
+		first, second := &recordingImporter{}, &recordingImporter{}
+		i.RegisterImporterFunc(context.Background(), first)
+		i.RegisterImporterFunc(context.Background(), second)
+		if n := len(first.questions) + len(second.questions); n != 0 {
+			t.Errorf("RegisterImporterFunc added %d questions, want none before an import runs", n)
+		}
+	})
+}
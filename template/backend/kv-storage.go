@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+//section:body
+// cursorGroup is the KV group of the sync cursors
+const cursorGroup = "sync_cursors"
+
+// SyncCursor remembers how far a sync with an external source got, an example of the
+// state a plugin keeps in KV storage
+type SyncCursor struct {
+	Source    string    `json:"source"`
+	Cursor    string    `json:"cursor"`
+	Runs      int       `json:"runs"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// cursors is cheap to build, the KV storage arrives after init through SetOperator
+func (s *{{plugin_display_name}}) cursors() *Repository[SyncCursor] {
+	return NewRepository[SyncCursor](s.kv, cursorGroup)
+}
+
+func (s *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {}
+
+func (s *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {}
+
+// RegisterAuthAdminRouter exposes the sync cursors to administrators:
+//
+//	GET    /{{plugin_slug_name}}/cursors          cursors ordered by source, ?prefix=, ?page= and ?page_size=
+//	GET    /{{plugin_slug_name}}/cursors/:source  one cursor
+//	PUT    /{{plugin_slug_name}}/cursors/:source  advance the cursor, body {"cursor": "..."}
+//	DELETE /{{plugin_slug_name}}/cursors/:source  forget the cursor, the next sync starts over
+func (s *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {
+	group := router.Group("/{{plugin_slug_name}}/cursors")
+	group.GET("", s.listCursors)
+	group.GET("/:source", s.getCursor)
+	group.PUT("/:source", s.advanceCursor)
+	group.DELETE("/:source", s.deleteCursor)
+}
+
+func (s *{{plugin_display_name}}) listCursors(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	pageSize, _ := strconv.Atoi(ctx.Query("page_size"))
+	result, err := s.cursors().List(ctx.Request.Context(), ListOptions{
+		Prefix:   ctx.Query("prefix"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (s *{{plugin_display_name}}) getCursor(ctx *gin.Context) {
+	cursor, err := s.cursors().Get(ctx.Request.Context(), ctx.Param("source"))
+	if errors.Is(err, plugin.ErrKVKeyNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "no cursor for " + ctx.Param("source")})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, cursor)
+}
+
+// advanceCursor updates the cursor in a transaction so concurrent syncs count every run
+func (s *{{plugin_display_name}}) advanceCursor(ctx *gin.Context) {
+	req := struct {
+		Cursor string `json:"cursor" binding:"required"`
+	}{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source := ctx.Param("source")
+	cursor, err := s.cursors().Update(ctx.Request.Context(), source, func(current *SyncCursor) (*SyncCursor, error) {
+		if current == nil {
+			current = &SyncCursor{Source: source}
+		}
+		current.Cursor = req.Cursor
+		current.Runs++
+		current.UpdatedAt = time.Now().UTC()
+		return current, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, cursor)
+}
+
+func (s *{{plugin_display_name}}) deleteCursor(ctx *gin.Context) {
+	if err := s.cursors().Delete(ctx.Request.Context(), ctx.Param("source")); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
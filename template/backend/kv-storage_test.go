@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestKVStorageContract runs the KV storage contract against the plugin
+func TestKVStorageContract(t *testing.T) {
+	testKVStorageContract(t, func(t *testing.T) plugin.KVStorage {
+		return newPlugin()
+	})
+}
+
+// testKVStorageContract checks the behaviour Answer relies on from a
+// plugin.KVStorage: the operator arrives after init, and again with every
+// reload of the plugin. The repository built on it has its own tests.
+func testKVStorageContract(t *testing.T, newStorage func(t *testing.T) plugin.KVStorage) {
+	t.Run("operator set again", func(t *testing.T) {
+		s := newStorage(t)
+		s.SetOperator(&plugin.KVOperator{})
+		s.SetOperator(&plugin.KVOperator{})
+	})
+}
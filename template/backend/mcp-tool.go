@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+//section:fields
+
+	mu    sync.RWMutex
+	tools *ToolSet
+
+//section:config
+	// SiteURL is the address of this Answer site, the tools call its API
+	SiteURL string `json:"site_url"`
+
+//section:init
+		// Listed before the site URL is set, calling them fails until then
+		tools: NewToolSet(Tools(&AnswerClient{})...),
+
+//section:body
+func (m *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:        "site_url",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigSiteURLTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigSiteURLDescription),
+			Required:    true,
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeUrl},
+			Value:       m.Config.SiteURL,
+		},
+	}
+}
+
+func (m *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+	client := NewAnswerClient(&http.Client{Timeout: 10 * time.Second}, strings.TrimRight(c.SiteURL, "/"))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Config = c
+	m.tools = NewToolSet(Tools(client)...)
+	return nil
+}
+
+func (m *{{plugin_display_name}}) toolSet() *ToolSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tools
+}
+
+func (m *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {}
+
+// RegisterAuthUserRouter serves the tools to MCP clients over the streamable HTTP transport:
+//
+//	POST /{{plugin_slug_name}}/mcp  JSON-RPC requests (initialize, tools/list, tools/call)
+//
+// Agents authenticate like any other API client, with a user's access token.
+func (m *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {
+	router.POST("/{{plugin_slug_name}}/mcp", func(ctx *gin.Context) {
+		ServeMCP(ctx, m.Info(), m.toolSet())
+	})
+}
+
+func (m *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {}
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestMCPToolContract runs the MCP tool contract against the plugin
+func TestMCPToolContract(t *testing.T) {
+	testMCPToolContract(t, func(t *testing.T) plugin.Config {
+		return newPlugin()
+	})
+}
+
+// testMCPToolContract checks the settings page Answer builds from the
+// plugin.Config: every field has a unique name, and saving the page, which
+// sends the values back keyed by name, keeps them.
+func testMCPToolContract(t *testing.T, newConfig func(t *testing.T) plugin.Config) {
+	t.Run("settings round trip", func(t *testing.T) {
+		c := newConfig(t)
+		values := map[string]any{}
+		for _, field := range c.ConfigFields() {
+			if field.Name == "" {
+				t.Fatalf("config field %+v has no name", field)
+			}
+			if _, ok := values[field.Name]; ok {
+				t.Fatalf("two config fields are named %q", field.Name)
+			}
+			values[field.Name] = field.Value
+			if field.Type == plugin.ConfigTypeInput {
+				values[field.Name] = "https://answer.example.com"
+			}
+		}
+		config, err := json.Marshal(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ConfigReceiver(config); err != nil {
+			t.Fatalf("ConfigReceiver(%s): %v", config, err)
+		}
+
+		for _, field := range c.ConfigFields() {
+			got, _ := json.Marshal(field.Value)
+			want, _ := json.Marshal(values[field.Name])
+			if string(got) != string(want) {
+				t.Errorf("config field %q = %s after saving, want %s", field.Name, got, want)
+			}
+		}
+	})
+}
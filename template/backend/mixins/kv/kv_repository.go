@@ -0,0 +1,214 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/answer/plugin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	scanPageSize    = 200
+)
+
+// errNotConnected is returned until Answer calls SetOperator
+var errNotConnected = errors.New("the KV storage is not connected yet")
+
+// Repository stores values of type T as JSON in one KV group, keyed by string.
+// Get returns an error matching plugin.ErrKVKeyNotFound for missing keys.
+type Repository[T any] struct {
+	store KVStore
+	group string
+}
+
+// Entry is a stored value with its key
+type Entry[T any] struct {
+	Key   string `json:"key"`
+	Value *T     `json:"value"`
+}
+
+// ListOptions selects a page of entries, ordered by key
+type ListOptions struct {
+	// Prefix keeps only the keys that start with it
+	Prefix   string
+	Page     int
+	PageSize int
+}
+
+// Page is one page of a List result
+type Page[T any] struct {
+	Items    []*Entry[T] `json:"items"`
+	Total    int         `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+}
+
+// NewRepository returns a repository over the group, a nil store fails every call
+func NewRepository[T any](store KVStore, group string) *Repository[T] {
+	return &Repository[T]{store: store, group: group}
+}
+
+// With returns the repository bound to another store, typically the one a transaction
+// hands out, so several repositories can change their groups in the same transaction
+func (r *Repository[T]) With(store KVStore) *Repository[T] {
+	return &Repository[T]{store: store, group: r.group}
+}
+
+// Get decodes the value stored under key
+func (r *Repository[T]) Get(ctx context.Context, key string) (*T, error) {
+	if r.store == nil {
+		return nil, errNotConnected
+	}
+	raw, err := r.store.Get(ctx, plugin.KVParams{Group: r.group, Key: key})
+	if err != nil {
+		return nil, err
+	}
+	return r.decode(key, raw)
+}
+
+// Put stores value under key, replacing what was there
+func (r *Repository[T]) Put(ctx context.Context, key string, value *T) error {
+	if r.store == nil {
+		return errNotConnected
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode %s/%s: %w", r.group, key, err)
+	}
+	return r.store.Set(ctx, plugin.KVParams{Group: r.group, Key: key, Value: string(raw)})
+}
+
+// Delete removes key, deleting a missing key is not an error
+func (r *Repository[T]) Delete(ctx context.Context, key string) error {
+	if r.store == nil {
+		return errNotConnected
+	}
+	return r.store.Del(ctx, plugin.KVParams{Group: r.group, Key: key})
+}
+
+// Update reads key, passes the value to fn and writes fn's result back in one transaction.
+// fn gets nil when the key does not exist, returning nil deletes the key and returning an
+// error rolls the transaction back.
+func (r *Repository[T]) Update(ctx context.Context, key string, fn func(current *T) (*T, error)) (updated *T, err error) {
+	if r.store == nil {
+		return nil, errNotConnected
+	}
+	err = r.store.Tx(ctx, func(ctx context.Context, tx KVStore) error {
+		repo := r.With(tx)
+		current, err := repo.Get(ctx, key)
+		if err != nil && !errors.Is(err, plugin.ErrKVKeyNotFound) {
+			return err
+		}
+		updated, err = fn(current)
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return repo.Delete(ctx, key)
+		}
+		return repo.Put(ctx, key, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Scan calls fn for every entry whose key starts with prefix, ordered by key, and stops at
+// the first error. The KV storage has no key index, so it reads the whole group.
+func (r *Repository[T]) Scan(ctx context.Context, prefix string, fn func(entry *Entry[T]) error) error {
+	entries, err := r.scan(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns one page of the entries matching opts
+func (r *Repository[T]) List(ctx context.Context, opts ListOptions) (*Page[T], error) {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	if opts.PageSize < 1 {
+		opts.PageSize = defaultPageSize
+	}
+	if opts.PageSize > maxPageSize {
+		opts.PageSize = maxPageSize
+	}
+
+	entries, err := r.scan(ctx, opts.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page[T]{Items: []*Entry[T]{}, Total: len(entries), Page: opts.Page, PageSize: opts.PageSize}
+	if start := (opts.Page - 1) * opts.PageSize; start < len(entries) {
+		page.Items = entries[start:min(start+opts.PageSize, len(entries))]
+	}
+	return page, nil
+}
+
+// scan loads and decodes the matching entries of the group, sorted by key
+func (r *Repository[T]) scan(ctx context.Context, prefix string) ([]*Entry[T], error) {
+	if r.store == nil {
+		return nil, errNotConnected
+	}
+	var entries []*Entry[T]
+	for page := 1; ; page++ {
+		values, err := r.store.GetByGroup(ctx, plugin.KVParams{Group: r.group, Page: page, PageSize: scanPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for key, raw := range values {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			value, err := r.decode(key, raw)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, &Entry[T]{Key: key, Value: value})
+		}
+		if len(values) < scanPageSize {
+			break
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+func (r *Repository[T]) decode(key, raw string) (*T, error) {
+	value := new(T)
+	if err := json.Unmarshal([]byte(raw), value); err != nil {
+		return nil, fmt.Errorf("decode %s/%s: %w", r.group, key, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,327 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+
+	"{{testkit_module}}"
+)
+
+type testItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// testStore adapts the testkit's KV storage to KVStore, as operatorStore does
+// for Answer's operator
+type testStore struct {
+	*testkit.KV
+}
+
+func newTestStore() *testStore {
+	return &testStore{testkit.NewKV()}
+}
+
+func (s *testStore) Tx(ctx context.Context, fn func(ctx context.Context, tx KVStore) error) error {
+	return s.KV.Tx(ctx, func(ctx context.Context, kv *testkit.KV) error {
+		return fn(ctx, &testStore{kv})
+	})
+}
+
+func TestRepositoryGetPutDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository[testItem](newTestStore(), "items")
+
+	if _, err := repo.Get(ctx, "a"); !errors.Is(err, plugin.ErrKVKeyNotFound) {
+		t.Fatalf("Get missing key: got %v, want ErrKVKeyNotFound", err)
+	}
+	if err := repo.Put(ctx, "a", &testItem{Name: "first", Count: 1}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.Get(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != (testItem{Name: "first", Count: 1}) {
+		t.Errorf("Get: got %+v", got)
+	}
+
+	if err := repo.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(ctx, "a"); !errors.Is(err, plugin.ErrKVKeyNotFound) {
+		t.Errorf("Get deleted key: got %v, want ErrKVKeyNotFound", err)
+	}
+}
+
+func TestRepositoryKeepsGroupsApart(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	items := NewRepository[testItem](store, "items")
+	others := NewRepository[testItem](store, "others")
+
+	if err := items.Put(ctx, "a", &testItem{Name: "item"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := others.Get(ctx, "a"); !errors.Is(err, plugin.ErrKVKeyNotFound) {
+		t.Errorf("Get from another group: got %v, want ErrKVKeyNotFound", err)
+	}
+}
+
+func TestRepositoryInvalidJSON(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	repo := NewRepository[testItem](store, "items")
+	_ = store.Set(ctx, plugin.KVParams{Group: "items", Key: "bad", Value: "{"})
+
+	if _, err := repo.Get(ctx, "bad"); err == nil {
+		t.Error("Get: expected a decode error")
+	}
+	if _, err := repo.List(ctx, ListOptions{}); err == nil {
+		t.Error("List: expected a decode error")
+	}
+}
+
+func TestRepositoryScanPrefix(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository[testItem](newTestStore(), "items")
+	// More keys than one GetByGroup page holds
+	for i := 0; i < 450; i++ {
+		prefix := "even"
+		if i%2 == 1 {
+			prefix = "odd"
+		}
+		key := fmt.Sprintf("%s:%03d", prefix, i)
+		if err := repo.Put(ctx, key, &testItem{Count: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var keys []string
+	err := repo.Scan(ctx, "odd:", func(entry *Entry[testItem]) error {
+		keys = append(keys, entry.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 225 {
+		t.Fatalf("Scan: got %d keys, want 225", len(keys))
+	}
+	if keys[0] != "odd:001" || keys[224] != "odd:449" || !sort.StringsAreSorted(keys) {
+		t.Errorf("Scan: keys are not in order, first %s last %s", keys[0], keys[224])
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.Scan(ctx, "", func(entry *Entry[testItem]) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Scan: got %v after %d calls, want to stop after the first", err, calls)
+	}
+}
+
+func TestRepositoryList(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository[testItem](newTestStore(), "items")
+	for i := 0; i < 25; i++ {
+		if err := repo.Put(ctx, fmt.Sprintf("k%02d", i), &testItem{Count: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		opts      ListOptions
+		wantTotal int
+		wantKeys  []string
+	}{
+		{"defaults", ListOptions{}, 25, []string{"k00", "k19"}},
+		{"second page", ListOptions{Page: 2, PageSize: 10}, 25, []string{"k10", "k19"}},
+		{"last page", ListOptions{Page: 3, PageSize: 10}, 25, []string{"k20", "k24"}},
+		{"past the end", ListOptions{Page: 4, PageSize: 10}, 25, nil},
+		{"prefix", ListOptions{Prefix: "k1", PageSize: 5}, 10, []string{"k10", "k14"}},
+		{"no match", ListOptions{Prefix: "x"}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.List(ctx, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if page.Total != tt.wantTotal {
+				t.Errorf("Total: got %d, want %d", page.Total, tt.wantTotal)
+			}
+			if tt.wantKeys == nil {
+				if len(page.Items) != 0 {
+					t.Errorf("Items: got %d, want none", len(page.Items))
+				}
+				return
+			}
+			first, last := page.Items[0], page.Items[len(page.Items)-1]
+			if first.Key != tt.wantKeys[0] || last.Key != tt.wantKeys[1] {
+				t.Errorf("Items: got %s..%s, want %s..%s", first.Key, last.Key, tt.wantKeys[0], tt.wantKeys[1])
+			}
+			if first.Value.Count != int(first.Key[1]-'0')*10+int(first.Key[2]-'0') {
+				t.Errorf("Items: %s has value %+v", first.Key, first.Value)
+			}
+		})
+	}
+}
+
+func TestRepositoryUpdate(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository[testItem](newTestStore(), "items")
+	increment := func(current *testItem) (*testItem, error) {
+		if current == nil {
+			current = &testItem{Name: "counter"}
+		}
+		current.Count++
+		return current, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := repo.Update(ctx, "c", increment); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := repo.Get(ctx, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Count != 2 {
+		t.Errorf("Count: got %d, want 2", got.Count)
+	}
+
+	// An error rolls back, nothing is written
+	failed := errors.New("failed")
+	_, err = repo.Update(ctx, "c", func(current *testItem) (*testItem, error) {
+		current.Count = 100
+		return nil, failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Update: got %v, want the error of fn", err)
+	}
+	if got, _ := repo.Get(ctx, "c"); got.Count != 2 {
+		t.Errorf("Count after a failed update: got %d, want 2", got.Count)
+	}
+
+	// Returning nil deletes the key
+	if _, err := repo.Update(ctx, "c", func(*testItem) (*testItem, error) { return nil, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(ctx, "c"); !errors.Is(err, plugin.ErrKVKeyNotFound) {
+		t.Errorf("Get after delete: got %v, want ErrKVKeyNotFound", err)
+	}
+}
+
+func TestRepositoryUpdateConcurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository[testItem](newTestStore(), "items")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Update(ctx, "c", func(current *testItem) (*testItem, error) {
+				if current == nil {
+					current = &testItem{}
+				}
+				current.Count++
+				return current, nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, err := repo.Get(ctx, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Count != 50 {
+		t.Errorf("Count: got %d, want 50", got.Count)
+	}
+}
+
+func TestRepositoryTxAcrossGroups(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	pending := NewRepository[testItem](store, "pending")
+	done := NewRepository[testItem](store, "done")
+	if err := pending.Put(ctx, "job", &testItem{Name: "job"}); err != nil {
+		t.Fatal(err)
+	}
+
+	move := func(fail bool) error {
+		return store.Tx(ctx, func(ctx context.Context, tx KVStore) error {
+			job, err := pending.With(tx).Get(ctx, "job")
+			if err != nil {
+				return err
+			}
+			if err := pending.With(tx).Delete(ctx, "job"); err != nil {
+				return err
+			}
+			if fail {
+				return errors.New("failed")
+			}
+			return done.With(tx).Put(ctx, "job", job)
+		})
+	}
+
+	if err := move(true); err == nil {
+		t.Fatal("expected the transaction to fail")
+	}
+	if _, err := pending.Get(ctx, "job"); err != nil {
+		t.Errorf("job left pending after a rollback: %v", err)
+	}
+
+	if err := move(false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pending.Get(ctx, "job"); !errors.Is(err, plugin.ErrKVKeyNotFound) {
+		t.Errorf("job still pending: %v", err)
+	}
+	if _, err := done.Get(ctx, "job"); err != nil {
+		t.Errorf("job not done: %v", err)
+	}
+}
+
+func TestRepositoryNotConnected(t *testing.T) {
+	repo := NewRepository[testItem](nil, "items")
+	if _, err := repo.Get(context.Background(), "a"); err == nil {
+		t.Error("Get: expected an error without a store")
+	}
+	if _, err := repo.Update(context.Background(), "a", func(*testItem) (*testItem, error) { return nil, nil }); err == nil {
+		t.Error("Update: expected an error without a store")
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+
+	"github.com/apache/answer/plugin"
+)
+
+// KVStore is the part of Answer's KV storage the repositories use.
+// NewOperatorStore adapts *plugin.KVOperator, tests use an in-memory fake.
+type KVStore interface {
+	Get(ctx context.Context, params plugin.KVParams) (string, error)
+	Set(ctx context.Context, params plugin.KVParams) error
+	Del(ctx context.Context, params plugin.KVParams) error
+	GetByGroup(ctx context.Context, params plugin.KVParams) (map[string]string, error)
+	// Tx runs fn in a transaction, fn must only use the store it receives
+	Tx(ctx context.Context, fn func(ctx context.Context, tx KVStore) error) error
+}
+
+// NewOperatorStore wraps the operator Answer passes to SetOperator
+func NewOperatorStore(operator *plugin.KVOperator) KVStore {
+	return &operatorStore{operator}
+}
+
+type operatorStore struct {
+	*plugin.KVOperator
+}
+
+func (s *operatorStore) Tx(ctx context.Context, fn func(ctx context.Context, tx KVStore) error) error {
+	return s.KVOperator.Tx(ctx, func(ctx context.Context, kv *plugin.KVOperator) error {
+		return fn(ctx, &operatorStore{kv})
+	})
+}
@@ -0,0 +1,34 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"github.com/apache/answer/plugin"
+)
+
+//section:fields
+
+	// kv is set once Answer hands over the KV storage, see SetOperator
+	kv KVStore
+
+//section:body
+// SetOperator receives Answer's KV storage, build typed repositories on it with NewRepository
+func ({{slot:receiver}} *{{plugin_display_name}}) SetOperator(operator *plugin.KVOperator) {
+	{{slot:receiver}}.kv = NewOperatorStore(operator)
+}
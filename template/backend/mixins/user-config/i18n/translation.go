@@ -0,0 +1,27 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package i18n
+
+const (
+	UserConfigEnabledTitle      = "plugin.{{info_slug_name}}.backend.user_config.enabled.title"
+	UserConfigEnabledLabel      = "plugin.{{info_slug_name}}.backend.user_config.enabled.label"
+	UserConfigTargetTitle       = "plugin.{{info_slug_name}}.backend.user_config.target.title"
+	UserConfigTargetDescription = "plugin.{{info_slug_name}}.backend.user_config.target.description"
+)
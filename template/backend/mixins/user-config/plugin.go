@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"github.com/apache/answer/plugin"
+)
+
+//section:fields
+
+	// userConfigs keeps the settings each user saved, see UserConfigReceiver
+	userConfigs *UserConfigs
+
+//section:init
+
+		userConfigs: NewUserConfigs(),
+
+//section:body
+// UserConfigFields describes the settings every user can change on their plugin settings page
+func ({{slot:receiver}} *{{plugin_display_name}}) UserConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:  "enabled",
+			Type:  plugin.ConfigTypeSwitch,
+			Title: plugin.MakeTranslator(i18n.UserConfigEnabledTitle),
+			UIOptions: plugin.ConfigFieldUIOptions{
+				Label: plugin.MakeTranslator(i18n.UserConfigEnabledLabel),
+			},
+		},
+		{
+			Name:        "target",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.UserConfigTargetTitle),
+			Description: plugin.MakeTranslator(i18n.UserConfigTargetDescription),
+		},
+	}
+}
+
+// UserConfigReceiver keeps the settings a user saved, look them up with userConfigs.Get
+func ({{slot:receiver}} *{{plugin_display_name}}) UserConfigReceiver(userID string, config []byte) error {
+	userConfig, err := ParseUserConfig(config)
+	if err != nil {
+		return err
+	}
+	{{slot:receiver}}.userConfigs.Set(userID, userConfig)
+	return nil
+}
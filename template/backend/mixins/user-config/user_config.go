@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// UserConfig holds the settings a user picked for this plugin
+type UserConfig struct {
+	// Enabled stays off until the user turns the plugin on
+	Enabled bool `json:"enabled"`
+	// Target is where the user wants to be reached, e.g. a webhook URL or chat handle
+	Target string `json:"target"`
+}
+
+// ParseUserConfig decodes the settings as Answer passes them to UserConfigReceiver
+func ParseUserConfig(data []byte) (*UserConfig, error) {
+	c := &UserConfig{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid user config: %w", err)
+	}
+	c.Target = strings.TrimSpace(c.Target)
+	if c.Enabled && c.Target == "" {
+		return nil, fmt.Errorf("invalid user config: target is required when enabled")
+	}
+	return c, nil
+}
+
+// UserConfigs is a concurrency-safe cache of the settings by user ID
+type UserConfigs struct {
+	mu      sync.RWMutex
+	configs map[string]UserConfig
+}
+
+// NewUserConfigs returns an empty cache, UserConfigReceiver fills it
+func NewUserConfigs() *UserConfigs {
+	return &UserConfigs{configs: map[string]UserConfig{}}
+}
+
+// Get returns the user's settings, the zero UserConfig if the user saved none
+func (u *UserConfigs) Get(userID string) UserConfig {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.configs[userID]
+}
+
+// Set replaces the user's settings
+func (u *UserConfigs) Set(userID string, config *UserConfig) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.configs[userID] = *config
+}
+
+// Enabled lists the users who turned the plugin on, e.g. to broadcast to them
+func (u *UserConfigs) Enabled() map[string]UserConfig {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	enabled := make(map[string]UserConfig, len(u.configs))
+	for userID, config := range u.configs {
+		if config.Enabled {
+			enabled[userID] = config
+		}
+	}
+	return enabled
+}
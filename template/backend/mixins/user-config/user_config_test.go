@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"testing"
+)
+
+func TestUserConfigReceiver(t *testing.T) {
+	p := &{{plugin_display_name}}{userConfigs: NewUserConfigs()}
+
+	if err := p.UserConfigReceiver("1", []byte(`{"enabled": true, "target": " https://hooks.example.com/1 "}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.UserConfigReceiver("2", []byte(`{"enabled": false}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := p.userConfigs.Get("1"); !got.Enabled || got.Target != "https://hooks.example.com/1" {
+		t.Errorf("user 1: got %+v", got)
+	}
+	if got := p.userConfigs.Get("unknown"); got.Enabled {
+		t.Errorf("unknown user: got %+v, want the zero config", got)
+	}
+	if enabled := p.userConfigs.Enabled(); len(enabled) != 1 || enabled["1"].Target == "" {
+		t.Errorf("Enabled() = %+v, want only user 1", enabled)
+	}
+}
+
+func TestUserConfigReceiverRejectsInvalidConfig(t *testing.T) {
+	p := &{{plugin_display_name}}{userConfigs: NewUserConfigs()}
+	p.userConfigs.Set("1", &UserConfig{Enabled: true, Target: "kept"})
+
+	for name, config := range map[string]string{
+		"not JSON":       `enabled`,
+		"missing target": `{"enabled": true, "target": "  "}`,
+	} {
+		if err := p.UserConfigReceiver("1", []byte(config)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if got := p.userConfigs.Get("1"); got.Target != "kept" {
+		t.Errorf("a rejected config replaced the saved one: %+v", got)
+	}
+}
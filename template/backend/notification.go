@@ -20,51 +20,33 @@
 package {{package_name}}
 
 import (
-	"context"
-	"embed"
 	"fmt"
-	"github.com/apache/answer-plugins/{{plugin_name}}/i18n"
-	"github.com/apache/answer-plugins/util"
+
 	"github.com/apache/answer/plugin"
 )
 
-//go:embed info.yaml
-var Info embed.FS
-
-type {{plugin_display_name}} struct {
-	Config *{{plugin_display_name}}Config
-}
-
-type {{plugin_display_name}}Config struct {
+//section:config
 	WebhookURL string `json:"webhook_url"`
 	APIKey     string `json:"api_key"`
-}
 
-func init() {
-	plugin.Register(&{{plugin_display_name}}{
-		Config: &{{plugin_display_name}}Config{},
-	})
-}
+//section:body
+var _ plugin.Notification = (*{{plugin_display_name}})(nil)
 
-func (n *{{plugin_display_name}}) Info() plugin.Info {
-	info := &util.Info{}
-	info.GetInfo(Info)
+// GetNewQuestionSubscribers returns the IDs of the users Answer sends every new question to
+func (n *{{plugin_display_name}}) GetNewQuestionSubscribers() (userIDs []string) {
+	// TODO: Implement new question subscribers, e.g. from the users' settings
+	return nil
+}
 
-	return plugin.Info{
-		Name:        plugin.MakeTranslator(i18n.InfoName),
-		SlugName:    info.SlugName,
-		Description: plugin.MakeTranslator(i18n.InfoDescription),
-		Author:      info.Author,
-		Version:     info.Version,
-		Link:        info.Link,
+// Notify sends a message. Answer doesn't get an error back, log failures here.
+func (n *{{plugin_display_name}}) Notify(msg plugin.NotificationMessage) {
+	if n.Config.WebhookURL == "" {
+		// Not configured yet, skip the message
+		return
 	}
-}
 
-func (n *{{plugin_display_name}}) Notify(ctx context.Context, msg plugin.NotificationMessage) error {
 	// TODO: Implement notification sending logic
 	// This is a Hello World example - implement your notification logic here
 	fmt.Printf("Notification: Sending message to %s (type: %s)\n",
 		msg.ReceiverUserID, msg.Type)
-	return nil
 }
-
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestNotificationContract runs the notification contract against the plugin
+func TestNotificationContract(t *testing.T) {
+	testNotificationContract(t, func(t *testing.T) plugin.Notification {
+		return newPlugin()
+	})
+}
+
+// testNotificationContract checks the behaviour Answer relies on from a
+// plugin.Notification. newNotifier returns the plugin as installed, before an
+// administrator configures it.
+func testNotificationContract(t *testing.T, newNotifier func(t *testing.T) plugin.Notification) {
+	msg := plugin.NotificationMessage{
+		Type:                   plugin.NotificationNewQuestion,
+		ReceiverUserID:         "user-1",
+		ReceiverLang:           "en_US",
+		TriggerUserDisplayName: "Contract",
+		QuestionTitle:          "How does the contract work?",
+		QuestionUrl:            "https://answer.example.com/questions/1",
+	}
+
+	t.Run("unconfigured", func(t *testing.T) {
+		n := newNotifier(t)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			n.Notify(msg)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("Notify before the plugin is configured kept running, want the message skipped")
+		}
+	})
+
+	t.Run("new question subscribers", func(t *testing.T) {
+		n := newNotifier(t)
+		for _, userID := range n.GetNewQuestionSubscribers() {
+			if userID == "" {
+				t.Error("GetNewQuestionSubscribers returned an empty user ID")
+			}
+		}
+	})
+}
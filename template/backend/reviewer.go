@@ -20,60 +20,59 @@
 package {{package_name}}
 
 import (
-	"embed"
-	"fmt"
-	"github.com/apache/answer-plugins/{{plugin_name}}/i18n"
-	"github.com/apache/answer-plugins/util"
+	"time"
+
 	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/log"
 )
 
-//go:embed info.yaml
-var Info embed.FS
-
-type {{plugin_display_name}} struct {
-	Config *{{plugin_display_name}}Config
-}
+//section:fields
+	audit  *AuditLog
 
-type {{plugin_display_name}}Config struct {
+//section:config
 	APIKey string `json:"api_key"`
-}
 
-func init() {
-	plugin.Register(&{{plugin_display_name}}{
-		Config: &{{plugin_display_name}}Config{},
-	})
-}
-
-func (r *{{plugin_display_name}}) Info() plugin.Info {
-	info := &util.Info{}
-	info.GetInfo(Info)
-
-	return plugin.Info{
-		Name:        plugin.MakeTranslator(i18n.InfoName),
-		SlugName:    info.SlugName,
-		Description: plugin.MakeTranslator(i18n.InfoDescription),
-		Author:      info.Author,
-		Version:     info.Version,
-		Link:        info.Link,
-	}
-}
+//section:init
+		audit:  NewAuditLog(),
 
+//section:body
+// Review decides on the content and records the decision in the audit log
 func (r *{{plugin_display_name}}) Review(content *plugin.ReviewContent) (result *plugin.ReviewResult) {
-	// TODO: Implement content review logic
-	// This is a Hello World example - implement your reviewer logic here
-	fmt.Printf("Reviewer: Reviewing content for title: %s\nContent: %s\n",
-		content.Title, content.Content)
+	start := time.Now()
+	result, rule := r.review(content)
+	r.audit.Record(content, result, rule, start)
+	return result
+}
 
-	// Simulate review decision
+// review returns the result and the name of the rule that decided it. The
+// example sends long content to the review queue, replace it with your rules.
+func (r *{{plugin_display_name}}) review(content *plugin.ReviewContent) (result *plugin.ReviewResult, rule string) {
 	result = &plugin.ReviewResult{}
 	if len(content.Content) > 50 {
+		log.Debugf("{{plugin_slug_name}}: %s %q needs review, its content is too long", content.ObjectType, content.Title)
 		result.Approved = false
+		result.ReviewStatus = plugin.ReviewStatusNeedReview
 		result.Reason = "Content too long (simulated)"
-		return result
+		return result, "max-length"
 	}
 
 	result.Approved = true
+	result.ReviewStatus = plugin.ReviewStatusApproved
 	result.Reason = "Content looks good (simulated)"
-	return result
+	return result, ""
+}
+
+// SetOperator receives the KV storage the audit log is written to
+func (r *{{plugin_display_name}}) SetOperator(operator *plugin.KVOperator) {
+	r.audit.SetStore(operator)
 }
 
+func (r *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {}
+
+func (r *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {}
+
+// RegisterAuthAdminRouter exposes the audit log under /{{plugin_slug_name}}/audit
+func (r *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {
+	r.audit.RegisterRoutes(router.Group("/{{plugin_slug_name}}/audit"))
+}
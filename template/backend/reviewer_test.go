@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestReviewerContract runs the reviewer contract against the plugin
+func TestReviewerContract(t *testing.T) {
+	testReviewerContract(t, func(t *testing.T) plugin.Reviewer {
+		return newPlugin()
+	})
+}
+
+// testReviewerContract checks that a plugin.Reviewer decides on any content,
+// including the odd one, and that its results are consistent
+func testReviewerContract(t *testing.T, newReviewer func(t *testing.T) plugin.Reviewer) {
+	contents := map[string]*plugin.ReviewContent{
+		"question": {
+			ObjectType: "question",
+			Title:      "How do I configure the reviewer?",
+			Content:    "I installed the plugin and would like to know where its settings are.",
+			Tags:       []string{"plugins"},
+			IP:         "203.0.113.7",
+		},
+		"answer":  {ObjectType: "answer", Content: "Open the admin panel, the settings are under Plugins."},
+		"comment": {ObjectType: "comment", Content: "Thanks!"},
+		"empty":   {ObjectType: "question"},
+		"long":    {ObjectType: "answer", Content: strings.Repeat("A very long answer. ", 2000)},
+		"links":   {ObjectType: "comment", Content: "See https://example.com and https://example.org/page?x=1"},
+	}
+
+	for name, content := range contents {
+		t.Run(name, func(t *testing.T) {
+			r := newReviewer(t)
+			result := r.Review(content)
+			if result == nil {
+				t.Fatal("Review returned no result")
+			}
+			if result.Approved != (result.ReviewStatus == plugin.ReviewStatusApproved) {
+				t.Errorf("Review = approved %v with status %q, approved results have status %q and the others not",
+					result.Approved, result.ReviewStatus, plugin.ReviewStatusApproved)
+			}
+			switch result.ReviewStatus {
+			case plugin.ReviewStatusApproved:
+			case plugin.ReviewStatusNeedReview, plugin.ReviewStatusDeleteDirectly:
+				if result.Reason == "" {
+					t.Errorf("Review = %q without a reason, moderators need one", result.ReviewStatus)
+				}
+			default:
+				t.Errorf("Review = unknown status %q", result.ReviewStatus)
+			}
+		})
+	}
+}
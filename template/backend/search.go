@@ -21,69 +21,193 @@ package {{package_name}}
 
 import (
 	"context"
-	"embed"
-	"fmt"
-	"github.com/apache/answer-plugins/{{plugin_name}}/i18n"
-	"github.com/apache/answer-plugins/util"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/apache/answer/plugin"
+	"github.com/segmentfault/pacman/log"
 )
 
-//go:embed info.yaml
-var Info embed.FS
+//section:fields
 
-type {{plugin_display_name}} struct {
-	Config *{{plugin_display_name}}Config
-}
+	mu    sync.RWMutex
+	index map[string]*plugin.SearchContent
 
-type {{plugin_display_name}}Config struct {
+//section:config
 	Endpoint string `json:"endpoint"`
 	APIKey   string `json:"api_key"`
+
+//section:init
+
+		index: map[string]*plugin.SearchContent{},
+
+//section:body
+var _ plugin.Search = (*{{plugin_display_name}})(nil)
+
+const (
+	defaultSearchPageSize = 20
+	syncPageSize          = 100
+)
+
+// TODO: This is a Hello World example indexing the contents in memory, replace
+// it with requests to the search engine at Config.Endpoint
+
+// Description is shown on Answer's search page. Set Icon to an SVG and Link to
+// the search engine's site.
+func (s *{{plugin_display_name}}) Description() plugin.SearchDesc {
+	return plugin.SearchDesc{}
 }
 
-func init() {
-	plugin.Register(&{{plugin_display_name}}{
-		Config: &{{plugin_display_name}}Config{},
-	})
+// RegisterSyncer indexes the questions and answers Answer already has. Answer
+// calls it in the request that saves the plugin's config, so the contents are
+// indexed in the background.
+func (s *{{plugin_display_name}}) RegisterSyncer(ctx context.Context, syncer plugin.SearchSyncer) {
+	go s.sync(context.WithoutCancel(ctx), syncer)
 }
 
-func (s *{{plugin_display_name}}) Info() plugin.Info {
-	info := &util.Info{}
-	info.GetInfo(Info)
-
-	return plugin.Info{
-		Name:        plugin.MakeTranslator(i18n.InfoName),
-		SlugName:    info.SlugName,
-		Description: plugin.MakeTranslator(i18n.InfoDescription),
-		Author:      info.Author,
-		Version:     info.Version,
-		Link:        info.Link,
+// sync indexes every page of questions, then every page of answers
+func (s *{{plugin_display_name}}) sync(ctx context.Context, syncer plugin.SearchSyncer) {
+	pages := map[string]func(ctx context.Context, page, pageSize int) ([]*plugin.SearchContent, error){
+		"questions": syncer.GetQuestionsPage,
+		"answers":   syncer.GetAnswersPage,
+	}
+	for _, name := range []string{"questions", "answers"} {
+		for page := 1; ; page++ {
+			contents, err := pages[name](ctx, page, syncPageSize)
+			if err != nil {
+				log.Errorf("{{plugin_slug_name}}: sync %s page %d: %v", name, page, err)
+				break
+			}
+			for _, content := range contents {
+				if err := s.UpdateContent(ctx, content); err != nil {
+					log.Errorf("{{plugin_slug_name}}: index %s: %v", content.ObjectID, err)
+				}
+			}
+			if len(contents) < syncPageSize {
+				break
+			}
+		}
 	}
 }
 
-func (s *{{plugin_display_name}}) SearchContents(_ context.Context, cond *plugin.SearchBasicCond) (
+// SearchContents returns a page of the questions and answers matching every
+// word, the best matches first, and the total number of matches
+func (s *{{plugin_display_name}}) SearchContents(ctx context.Context, cond *plugin.SearchBasicCond) (
 	results []plugin.SearchResult, total int64, err error) {
-	// TODO: Implement search logic
-	// This is a Hello World example - implement your search logic here
-	fmt.Printf("Search: Searching with page %d, size %d\n", cond.Page, cond.PageSize)
-	
-	// Return a dummy search result
-	results = []plugin.SearchResult{
-		{
-			ID:   "hello-world-id",
-			Type: "question",
-		},
+	return s.search(cond, "")
+}
+
+// SearchQuestions is SearchContents limited to questions
+func (s *{{plugin_display_name}}) SearchQuestions(ctx context.Context, cond *plugin.SearchBasicCond) (
+	results []plugin.SearchResult, total int64, err error) {
+	return s.search(cond, "question")
+}
+
+// SearchAnswers is SearchContents limited to answers
+func (s *{{plugin_display_name}}) SearchAnswers(ctx context.Context, cond *plugin.SearchBasicCond) (
+	results []plugin.SearchResult, total int64, err error) {
+	return s.search(cond, "answer")
+}
+
+// search returns a page of the contents of contentType, any type if empty,
+// matching the condition
+func (s *{{plugin_display_name}}) search(cond *plugin.SearchBasicCond, contentType string) (
+	results []plugin.SearchResult, total int64, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type match struct {
+		content *plugin.SearchContent
+		hits    int
+	}
+	var matches []match
+	for _, content := range s.index {
+		if contentType != "" && content.Type != contentType {
+			continue
+		}
+		if !matchCond(content, cond) {
+			continue
+		}
+		if hits, ok := matchWords(content, cond.Words); ok {
+			matches = append(matches, match{content: content, hits: hits})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.hits != b.hits {
+			return a.hits > b.hits
+		}
+		if a.content.Created != b.content.Created {
+			return a.content.Created > b.content.Created
+		}
+		return a.content.ObjectID < b.content.ObjectID
+	})
+
+	page, pageSize := cond.Page, cond.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultSearchPageSize
+	}
+	results = []plugin.SearchResult{}
+	for i := (page - 1) * pageSize; i < len(matches) && i < page*pageSize; i++ {
+		results = append(results, plugin.SearchResult{
+			ID:   matches[i].content.ObjectID,
+			Type: matches[i].content.Type,
+		})
+	}
+	return results, int64(len(matches)), nil
+}
+
+// matchCond reports whether the content passes the condition's filters
+func matchCond(content *plugin.SearchContent, cond *plugin.SearchBasicCond) bool {
+	if cond.UserID != "" && content.UserID != cond.UserID {
+		return false
+	}
+	if cond.QuestionID != "" && content.QuestionID != cond.QuestionID {
+		return false
+	}
+	accepted := cond.QuestionAccepted
+	if content.Type == "answer" {
+		accepted = cond.AnswerAccepted
+	}
+	if (accepted == plugin.AcceptedCondTrue && !content.HasAccepted) ||
+		(accepted == plugin.AcceptedCondFalse && content.HasAccepted) {
+		return false
 	}
-	total = 1
-	return results, total, nil
+	return content.Score >= int64(cond.VoteAmount) &&
+		content.Views >= int64(cond.ViewAmount) &&
+		content.Answers >= int64(cond.AnswerAmount)
 }
 
+// matchWords reports whether the title or content contains every word, ignoring
+// case, and how often the words occur
+func matchWords(content *plugin.SearchContent, words []string) (hits int, ok bool) {
+	text := strings.ToLower(content.Title + "\n" + content.Content)
+	for _, word := range words {
+		n := strings.Count(text, strings.ToLower(word))
+		if n == 0 {
+			return 0, false
+		}
+		hits += n
+	}
+	return hits, true
+}
+
+// UpdateContent indexes the content, replacing the one with the same object ID
 func (s *{{plugin_display_name}}) UpdateContent(ctx context.Context, content *plugin.SearchContent) (err error) {
-	// TODO: Implement content update logic
+	indexed := *content
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index[content.ObjectID] = &indexed
 	return nil
 }
 
 func (s *{{plugin_display_name}}) DeleteContent(ctx context.Context, contentID string) (err error) {
-	// TODO: Implement content deletion logic
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.index, contentID)
 	return nil
 }
-
@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestSearchContract runs the search contract against the plugin. If your search
+// engine needs a server, configure the plugin to use a test index here.
+func TestSearchContract(t *testing.T) {
+	testSearchContract(t, func(t *testing.T) plugin.Search {
+		return newPlugin()
+	})
+}
+
+// pagedSyncer serves the questions and answers in pages, the way Answer's
+// syncer does
+type pagedSyncer struct {
+	questions, answers []*plugin.SearchContent
+}
+
+func (s pagedSyncer) GetQuestionsPage(_ context.Context, page, pageSize int) ([]*plugin.SearchContent, error) {
+	return pageOf(s.questions, page, pageSize), nil
+}
+
+func (s pagedSyncer) GetAnswersPage(_ context.Context, page, pageSize int) ([]*plugin.SearchContent, error) {
+	return pageOf(s.answers, page, pageSize), nil
+}
+
+func pageOf(contents []*plugin.SearchContent, page, pageSize int) []*plugin.SearchContent {
+	start := (page - 1) * pageSize
+	if start >= len(contents) {
+		return nil
+	}
+	return contents[start:min(start+pageSize, len(contents))]
+}
+
+// testSearchContract checks the behaviour Answer relies on from a plugin.Search.
+// newIndex is called for every subtest and returns an empty index.
+func testSearchContract(t *testing.T, newIndex func(t *testing.T) plugin.Search) {
+	ctx := context.Background()
+	index := func(t *testing.T, s plugin.Search, contents ...*plugin.SearchContent) {
+		t.Helper()
+		for _, content := range contents {
+			if err := s.UpdateContent(ctx, content); err != nil {
+				t.Fatalf("UpdateContent(%s): %v", content.ObjectID, err)
+			}
+		}
+	}
+	search := func(t *testing.T, s plugin.Search, page, pageSize int, words ...string) ([]string, int64) {
+		t.Helper()
+		results, total, err := s.SearchContents(ctx, &plugin.SearchBasicCond{Words: words, Page: page, PageSize: pageSize})
+		if err != nil {
+			t.Fatalf("SearchContents(%q, page %d): %v", words, page, err)
+		}
+		ids := make([]string, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		return ids, total
+	}
+	question := func(id, title string) *plugin.SearchContent {
+		return &plugin.SearchContent{ObjectID: id, Type: "question", Title: title, Content: "Body of " + title, Created: 1700000000}
+	}
+	answer := func(id, questionID, content string) *plugin.SearchContent {
+		return &plugin.SearchContent{ObjectID: id, Type: "answer", QuestionID: questionID, Content: content, Created: 1700000000}
+	}
+
+	t.Run("finds indexed content", func(t *testing.T) {
+		s := newIndex(t)
+		index(t, s,
+			question("q1", "How to configure the contract"),
+			answer("a1", "q1", "Unrelated answer"),
+		)
+
+		results, total, err := s.SearchContents(ctx, &plugin.SearchBasicCond{Words: []string{"CONTRACT"}, Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || len(results) != 1 || results[0].ID != "q1" || results[0].Type != "question" {
+			t.Errorf("SearchContents = %+v, total %d, want q1 (question), total 1", results, total)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		s := newIndex(t)
+		index(t, s, question("q1", "Contract"))
+		if ids, total := search(t, s, 1, 10, "nothing-matches-this"); len(ids) != 0 || total != 0 {
+			t.Errorf("search = %v, total %d, want no results", ids, total)
+		}
+	})
+
+	t.Run("pagination totals", func(t *testing.T) {
+		s := newIndex(t)
+		const matching, pageSize = 7, 3
+		for i := 0; i < matching; i++ {
+			index(t, s, question(fmt.Sprintf("q%d", i), fmt.Sprintf("Paged contract %d", i)))
+		}
+		index(t, s, question("other", "Something else"))
+
+		seen := map[string]bool{}
+		for page, want := range []int{3, 3, 1, 0} {
+			ids, total := search(t, s, page+1, pageSize, "paged")
+			if total != matching {
+				t.Errorf("page %d: total = %d, want %d on every page", page+1, total, matching)
+			}
+			if len(ids) != want {
+				t.Errorf("page %d: %d results, want %d", page+1, len(ids), want)
+			}
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("page %d: %s was already on an earlier page", page+1, id)
+				}
+				seen[id] = true
+			}
+		}
+		if len(seen) != matching {
+			t.Errorf("the pages hold %d results, want all %d", len(seen), matching)
+		}
+	})
+
+	t.Run("update replaces", func(t *testing.T) {
+		s := newIndex(t)
+		index(t, s, question("q1", "Original title"))
+		index(t, s, question("q1", "Edited title"))
+
+		if ids, total := search(t, s, 1, 10, "original"); len(ids) != 0 || total != 0 {
+			t.Errorf("search for the old title = %v, total %d, want no results", ids, total)
+		}
+		if ids, total := search(t, s, 1, 10, "edited"); len(ids) != 1 || total != 1 {
+			t.Errorf("search for the new title = %v, total %d, want [q1], total 1", ids, total)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		s := newIndex(t)
+		index(t, s, question("q1", "Deleted contract"), question("q2", "Kept contract"))
+		if err := s.DeleteContent(ctx, "q1"); err != nil {
+			t.Fatal(err)
+		}
+		if ids, total := search(t, s, 1, 10, "contract"); len(ids) != 1 || ids[0] != "q2" || total != 1 {
+			t.Errorf("search after DeleteContent = %v, total %d, want [q2], total 1", ids, total)
+		}
+		if err := s.DeleteContent(ctx, "never-indexed"); err != nil {
+			t.Errorf("DeleteContent of unknown content: %v", err)
+		}
+	})
+
+	t.Run("questions and answers", func(t *testing.T) {
+		s := newIndex(t)
+		index(t, s, question("q1", "Typed contract"), answer("a1", "q1", "Typed contract answer"))
+
+		for name, searchType := range map[string]func(context.Context, *plugin.SearchBasicCond) ([]plugin.SearchResult, int64, error){
+			"question": s.SearchQuestions,
+			"answer":   s.SearchAnswers,
+		} {
+			results, total, err := searchType(ctx, &plugin.SearchBasicCond{Words: []string{"typed"}, Page: 1, PageSize: 10})
+			if err != nil {
+				t.Fatalf("search %ss: %v", name, err)
+			}
+			if total != 1 || len(results) != 1 || results[0].Type != name {
+				t.Errorf("search %ss = %+v, total %d, want one %s, total 1", name, results, total, name)
+			}
+		}
+	})
+
+	t.Run("syncer", func(t *testing.T) {
+		s := newIndex(t)
+		syncer := pagedSyncer{answers: []*plugin.SearchContent{answer("a1", "q0", "Synced answer")}}
+		for i := 0; i < 250; i++ {
+			syncer.questions = append(syncer.questions, question(fmt.Sprintf("q%d", i), "Synced question"))
+		}
+		s.RegisterSyncer(ctx, syncer)
+
+		// Indexing may run in the background, wait for it
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			_, total := search(t, s, 1, 10, "synced")
+			if total == 251 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%d contents indexed after RegisterSyncer, want all 251 the syncer has", total)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
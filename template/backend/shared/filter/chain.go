@@ -0,0 +1,193 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SegmentKind tells rules what part of the Markdown a segment is
+type SegmentKind int
+
+const (
+	// SegmentText is prose, text rules rewrite it
+	SegmentText SegmentKind = iota
+	// SegmentURL is a link destination, an autolink or a bare URL, URL rules rewrite it
+	SegmentURL
+	// SegmentLiteral is code or link text, no rule touches it
+	SegmentLiteral
+)
+
+// Segment is a piece of Markdown
+type Segment struct {
+	Kind SegmentKind
+	Text string
+}
+
+// Rule rewrites the segments of one kind
+type Rule struct {
+	Name  string
+	Kind  SegmentKind
+	Apply func(s string) string
+}
+
+// Chain applies rules in order. Every rule sees the segments of the original Markdown,
+// so a link a text rule creates is not rewritten by a URL rule.
+type Chain []*Rule
+
+// Apply rewrites markdown, code blocks, code spans and link texts are left as they are
+func (c Chain) Apply(markdown string) string {
+	if len(c) == 0 {
+		return markdown
+	}
+	var out strings.Builder
+	out.Grow(len(markdown))
+	for _, segment := range Segments(markdown) {
+		text := segment.Text
+		for _, rule := range c {
+			if rule.Kind == segment.Kind && segment.Kind != SegmentLiteral {
+				text = rule.Apply(text)
+			}
+		}
+		out.WriteString(text)
+	}
+	return out.String()
+}
+
+var (
+	fenceRegexp = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	// inlineRegexp finds, in order: code spans, inline links, autolinks and bare URLs
+	inlineRegexp = regexp.MustCompile("(`+)|(!?\\[[^\\]\\n]*\\]\\()|(<https?://[^>\\s]+>)|(https?://[^\\s<>()\\[\\]]+)")
+)
+
+// Segments splits markdown into segments whose texts concatenate to the input
+func Segments(markdown string) []Segment {
+	var segments []Segment
+	add := func(kind SegmentKind, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(segments); n > 0 && segments[n-1].Kind == kind {
+			segments[n-1].Text += text
+			return
+		}
+		segments = append(segments, Segment{Kind: kind, Text: text})
+	}
+
+	lines := strings.SplitAfter(markdown, "\n")
+	fence := ""
+	previousBlank, inIndentedCode := true, false
+	for _, line := range lines {
+		content := strings.TrimRight(line, "\r\n")
+		blank := strings.TrimSpace(content) == ""
+		switch {
+		case fence != "":
+			// Inside a fenced block until a fence at least as long as the opening one
+			if m := fenceRegexp.FindStringSubmatch(content); m != nil && strings.HasPrefix(m[1], fence) &&
+				strings.TrimSpace(content[len(m[0]):]) == "" {
+				fence = ""
+			}
+			add(SegmentLiteral, line)
+		case fenceRegexp.MatchString(content):
+			m := fenceRegexp.FindStringSubmatch(content)
+			fence = m[1]
+			add(SegmentLiteral, line)
+		case !blank && (previousBlank || inIndentedCode) && isIndentedCode(content):
+			inIndentedCode = true
+			add(SegmentLiteral, line)
+		default:
+			if !blank {
+				inIndentedCode = false
+			}
+			for _, segment := range inlineSegments(line) {
+				add(segment.Kind, segment.Text)
+			}
+		}
+		previousBlank = blank
+	}
+	return segments
+}
+
+func isIndentedCode(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// inlineSegments splits one line, code spans and links don't span lines here
+func inlineSegments(line string) []Segment {
+	var segments []Segment
+	for line != "" {
+		loc := inlineRegexp.FindStringSubmatchIndex(line)
+		if loc == nil {
+			segments = append(segments, Segment{Kind: SegmentText, Text: line})
+			break
+		}
+		segments = append(segments, Segment{Kind: SegmentText, Text: line[:loc[0]]})
+		rest := line[loc[0]:]
+
+		switch {
+		case loc[2] >= 0:
+			// A code span closes with a backtick run of the same length
+			ticks := line[loc[2]:loc[3]]
+			end := strings.Index(rest[len(ticks):], ticks)
+			if end < 0 {
+				segments = append(segments, Segment{Kind: SegmentText, Text: ticks})
+				line = rest[len(ticks):]
+				continue
+			}
+			end += 2 * len(ticks)
+			segments = append(segments, Segment{Kind: SegmentLiteral, Text: rest[:end]})
+			line = rest[end:]
+		case loc[4] >= 0:
+			// [text]( is followed by the destination up to the closing parenthesis
+			opening := line[loc[4]:loc[5]]
+			end := strings.IndexByte(rest[len(opening):], ')')
+			if end < 0 {
+				segments = append(segments, Segment{Kind: SegmentText, Text: opening})
+				line = rest[len(opening):]
+				continue
+			}
+			destination := rest[len(opening) : len(opening)+end]
+			target, title, _ := strings.Cut(destination, " ")
+			segments = append(segments,
+				Segment{Kind: SegmentLiteral, Text: opening},
+				Segment{Kind: SegmentURL, Text: target},
+			)
+			if title != "" {
+				segments = append(segments, Segment{Kind: SegmentLiteral, Text: " " + title})
+			}
+			segments = append(segments, Segment{Kind: SegmentLiteral, Text: ")"})
+			line = rest[len(opening)+end+1:]
+		case loc[6] >= 0:
+			autolink := line[loc[6]:loc[7]]
+			segments = append(segments,
+				Segment{Kind: SegmentLiteral, Text: "<"},
+				Segment{Kind: SegmentURL, Text: autolink[1 : len(autolink)-1]},
+				Segment{Kind: SegmentLiteral, Text: ">"},
+			)
+			line = rest[len(autolink):]
+		default:
+			// Trailing punctuation ends the sentence, not the URL
+			url := strings.TrimRight(line[loc[8]:loc[9]], ".,;:!?'\"*_")
+			segments = append(segments, Segment{Kind: SegmentURL, Text: url})
+			line = rest[len(url):]
+		}
+	}
+	return segments
+}
@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected.md files with the current output")
+
+// goldenConfig are the settings every golden case is filtered with
+var goldenConfig = &{{plugin_display_name}}Config{
+	TicketPattern: defaultTicketPattern,
+	TicketURL:     "https://issues.example.com/browse/$0",
+	Shortcodes:    defaultShortcodes,
+	StripTracking: true,
+}
+
+// TestGolden parses testdata/golden/<case>/input.md and compares it with expected.md.
+// Add a case by adding a directory, run go test -update to write its expected.md.
+func TestGolden(t *testing.T) {
+	chain, err := NewChain(goldenConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &{{plugin_display_name}}{Config: goldenConfig, chain: chain}
+
+	dirs, err := filepath.Glob(filepath.Join("testdata", "golden", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) == 0 {
+		t.Fatal("no golden cases in testdata/golden")
+	}
+	for _, dir := range dirs {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join(dir, "input.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rewritten, err := p.Parse(string(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedPath := filepath.Join(dir, "expected.md")
+			if *update {
+				if err := os.WriteFile(expectedPath, []byte(rewritten), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(expectedPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rewritten != string(expected) {
+				t.Errorf("output differs from %s\n--- got\n%s\n--- want\n%s", expectedPath, rewritten, expected)
+			}
+		})
+	}
+}
+
+func TestFilterTextRejectsBlockedWords(t *testing.T) {
+	p := newPlugin()
+	if err := p.ConfigReceiver([]byte(`{"blocked_words": "# spam\nBuy Now\n\ncasino"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var blocked *BlockedError
+	if err := p.FilterText("Please BUY NOW at the casino"); !errors.As(err, &blocked) {
+		t.Fatalf("FilterText = %v, want a *BlockedError", err)
+	}
+	if want := []string{"Buy Now", "casino"}; strings.Join(blocked.Words, ",") != strings.Join(want, ",") {
+		t.Errorf("BlockedError.Words = %q, want %q", blocked.Words, want)
+	}
+	// Text the rules rewrite isn't rejected, and comment lines block nothing
+	for _, text := range []string{"See PROJ-1 :check: https://x.test/?utm_source=a", "no spam here"} {
+		if err := p.FilterText(text); err != nil {
+			t.Errorf("FilterText(%q) = %v, want it accepted", text, err)
+		}
+	}
+}
+
+// TestSegmentsRoundTrip checks that segmenting never loses or adds text
+func TestSegmentsRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"plain text\n",
+		"unclosed `code and [link](",
+		"```\nunclosed fence\n",
+		"[a](https://x.test/?q=1 \"title\") <https://y.test> https://z.test/.\r\n",
+	}
+	for _, input := range inputs {
+		var b strings.Builder
+		for _, segment := range Segments(input) {
+			b.WriteString(segment.Text)
+		}
+		if b.String() != input {
+			t.Errorf("Segments(%q) joined to %q", input, b.String())
+		}
+	}
+}
+
+func TestConfigReceiverRejectsInvalidRules(t *testing.T) {
+	p := &{{plugin_display_name}}{Config: goldenConfig}
+	for _, config := range []string{
+		`{"ticket_pattern": "([", "ticket_url": "https://issues.example.com/$0"}`,
+		`{"shortcodes": "no equals sign"}`,
+	} {
+		if err := p.ConfigReceiver([]byte(config)); err == nil {
+			t.Errorf("ConfigReceiver(%s): expected an error", config)
+		}
+	}
+	if *p.Config != *goldenConfig {
+		t.Errorf("a rejected config replaced the active one: %+v", p.Config)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// TicketLinks links ticket IDs matching pattern, urlTemplate expands like regexp.Expand,
+// e.g. "https://issues.example.com/browse/$0"
+func TicketLinks(pattern, urlTemplate string) (*Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket pattern: %w", err)
+	}
+	return &Rule{
+		Name: "ticket-links",
+		Kind: SegmentText,
+		Apply: func(s string) string {
+			return re.ReplaceAllStringFunc(s, func(id string) string {
+				target := re.ExpandString(nil, urlTemplate, id, re.FindStringSubmatchIndex(id))
+				return "[" + id + "](" + string(target) + ")"
+			})
+		},
+	}, nil
+}
+
+var shortcodeRegexp = regexp.MustCompile(`:([a-z0-9_+-]+):`)
+
+// Shortcodes replaces :name: with its expansion, unknown names are kept
+func Shortcodes(expansions map[string]string) *Rule {
+	return &Rule{
+		Name: "shortcodes",
+		Kind: SegmentText,
+		Apply: func(s string) string {
+			return shortcodeRegexp.ReplaceAllStringFunc(s, func(code string) string {
+				if expansion, ok := expansions[code[1:len(code)-1]]; ok {
+					return expansion
+				}
+				return code
+			})
+		},
+	}
+}
+
+// ParseShortcodes reads one "name = expansion" per line, "#" starts a comment line
+func ParseShortcodes(text string) (map[string]string, error) {
+	expansions := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	line := 0
+	for scanner.Scan() {
+		line++
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		name, expansion, ok := strings.Cut(entry, "=")
+		name = strings.Trim(strings.TrimSpace(name), ":")
+		if !ok || !shortcodeRegexp.MatchString(":"+name+":") {
+			return nil, fmt.Errorf("shortcodes line %d: want \"name = expansion\"", line)
+		}
+		expansions[name] = strings.TrimSpace(expansion)
+	}
+	return expansions, scanner.Err()
+}
+
+// ParseBlockedWords reads one word or phrase per line, "#" starts a comment line
+func ParseBlockedWords(text string) []string {
+	var words []string
+	for _, line := range strings.Split(text, "\n") {
+		word := strings.TrimSpace(line)
+		if word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// FindBlockedWords returns the words text contains, ignoring case
+func FindBlockedWords(text string, words []string) []string {
+	var found []string
+	text = strings.ToLower(text)
+	for _, word := range words {
+		if strings.Contains(text, strings.ToLower(word)) {
+			found = append(found, word)
+		}
+	}
+	return found
+}
+
+// TrackingParams are the query parameters StripTracking removes, a trailing "*" matches a prefix
+var TrackingParams = []string{"utm_*", "fbclid", "gclid", "dclid", "msclkid", "mc_cid", "mc_eid", "igshid", "_hsenc", "_hsmi"}
+
+// StripTracking removes tracking parameters from URLs and keeps the rest of the query as it was
+func StripTracking(params []string) *Rule {
+	tracked := func(key string) bool {
+		key = strings.ToLower(key)
+		for _, p := range params {
+			prefix, isPrefix := strings.CutSuffix(p, "*")
+			if key == p || isPrefix && strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return &Rule{
+		Name: "strip-tracking",
+		Kind: SegmentURL,
+		Apply: func(s string) string {
+			// Cut the query out of the string so the rest of the URL stays byte for byte
+			base, fragment, _ := strings.Cut(s, "#")
+			prefix, query, ok := strings.Cut(base, "?")
+			if !ok || query == "" {
+				return s
+			}
+			pairs := strings.Split(query, "&")
+			kept := make([]string, 0, len(pairs))
+			for _, pair := range pairs {
+				key, _, _ := strings.Cut(pair, "=")
+				if unescaped, err := url.QueryUnescape(key); err == nil {
+					key = unescaped
+				}
+				if !tracked(key) {
+					kept = append(kept, pair)
+				}
+			}
+			if len(kept) == len(pairs) {
+				return s
+			}
+			if len(kept) > 0 {
+				prefix += "?" + strings.Join(kept, "&")
+			}
+			if strings.Contains(s, "#") {
+				prefix += "#" + fragment
+			}
+			return prefix
+		},
+	}
+}
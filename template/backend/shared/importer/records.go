@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/answer/plugin"
+)
+
+// Record is a question of the export being migrated
+type Record struct {
+	// ID identifies the question in the source system, it makes imports idempotent
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Content   string   `json:"content"`
+	Tags      []string `json:"tags"`
+	UserEmail string   `json:"user_email"`
+}
+
+// ReadRecords reads an export, a JSON array of records or a CSV file with a
+// header row (id,title,content,tags,user_email; tags separated by ";")
+func ReadRecords(path string) ([]*Record, error) {
+	if path == "" {
+		return nil, errors.New("no source file configured")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return readJSONRecords(f)
+	case ".csv":
+		return readCSVRecords(f)
+	}
+	return nil, fmt.Errorf("unsupported export format %q, use .json or .csv", filepath.Ext(path))
+}
+
+func readJSONRecords(r io.Reader) ([]*Record, error) {
+	var records []*Record
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("parse JSON export: %w", err)
+	}
+	return records, nil
+}
+
+func readCSVRecords(r io.Reader) ([]*Record, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	for _, required := range []string{"id", "title", "content"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV export has no %q column", required)
+		}
+	}
+
+	var records []*Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse CSV export: %w", err)
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		record := &Record{
+			ID:        get("id"),
+			Title:     get("title"),
+			Content:   get("content"),
+			UserEmail: get("user_email"),
+		}
+		for _, tag := range strings.Split(get("tags"), ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				record.Tags = append(record.Tags, tag)
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+// Validate checks the fields Answer needs to create the question
+func (r *Record) Validate() error {
+	switch {
+	case r.ID == "":
+		return errors.New("missing id")
+	case strings.TrimSpace(r.Title) == "":
+		return errors.New("missing title")
+	case strings.TrimSpace(r.Content) == "":
+		return errors.New("missing content")
+	case len(r.Tags) == 0:
+		return errors.New("at least one tag is required")
+	case r.UserEmail == "":
+		return errors.New("missing user email and no default user email configured")
+	}
+	return nil
+}
+
+// Question converts the record for Answer's importer
+func (r *Record) Question() plugin.QuestionImporterInfo {
+	return plugin.QuestionImporterInfo{
+		Title:     r.Title,
+		Content:   r.Content,
+		Tags:      r.Tags,
+		UserEmail: r.UserEmail,
+	}
+}
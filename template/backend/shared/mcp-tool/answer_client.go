@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const maxResponseBytes = 4 << 20
+
+// AnswerClient calls the public API of the Answer site, /answer/api/v1
+type AnswerClient struct {
+	client  *http.Client
+	siteURL string
+}
+
+// NewAnswerClient returns a client for the site at siteURL, without a trailing slash
+func NewAnswerClient(client *http.Client, siteURL string) *AnswerClient {
+	return &AnswerClient{client: client, siteURL: siteURL}
+}
+
+// SiteURL returns the site address links are built from
+func (c *AnswerClient) SiteURL() string {
+	return c.siteURL
+}
+
+// Get calls an API path such as "/question/page" and decodes the data of the response into data
+func (c *AnswerClient) Get(ctx context.Context, path string, query url.Values, data any) error {
+	if c.siteURL == "" {
+		return errors.New("the site URL is not configured")
+	}
+	endpoint := c.siteURL + "/answer/api/v1" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := c.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	// Answer wraps every response, errors carry a message next to the status
+	envelope := struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s %s", path, resp.Status, envelope.Msg)
+	}
+	return json.Unmarshal(envelope.Data, data)
+}
@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+// protocolVersions are the MCP revisions ServeMCP speaks, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+const maxRequestBytes = 1 << 20
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type toolDescription struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description,omitempty"`
+	InputSchema  *Schema `json:"inputSchema"`
+	OutputSchema *Schema `json:"outputSchema,omitempty"`
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content           []toolContent `json:"content"`
+	StructuredContent any           `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
+}
+
+// ServeMCP answers one JSON-RPC message of the MCP streamable HTTP transport.
+// Responses are plain JSON, the server never opens an SSE stream.
+func ServeMCP(ctx *gin.Context, info plugin.Info, tools *ToolSet) {
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxRequestBytes))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+			Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+		return
+	}
+	req := &rpcRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		ctx.JSON(http.StatusBadRequest, rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+			Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+		return
+	}
+	// Notifications and responses from the client need no answer
+	if len(req.ID) == 0 {
+		ctx.Status(http.StatusAccepted)
+		return
+	}
+
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{Code: codeInvalidRequest, Message: "not a JSON-RPC 2.0 request"}
+		ctx.JSON(http.StatusOK, resp)
+		return
+	}
+
+	switch req.Method {
+	case "initialize":
+		resp.Result = initialize(req.Params, info)
+	case "ping":
+		resp.Result = struct{}{}
+	case "tools/list":
+		list := make([]toolDescription, 0, len(tools.List()))
+		for _, tool := range tools.List() {
+			list = append(list, toolDescription{
+				Name:         tool.Name,
+				Description:  tool.Description,
+				InputSchema:  tool.InputSchema,
+				OutputSchema: tool.OutputSchema,
+			})
+		}
+		resp.Result = gin.H{"tools": list}
+	case "tools/call":
+		result, rpcErr := callTool(ctx, req.Params, tools)
+		if rpcErr != nil {
+			resp.Error = rpcErr
+		} else {
+			resp.Result = result
+		}
+	default:
+		resp.Error = &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func initialize(params json.RawMessage, info plugin.Info) gin.H {
+	requested := struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}{}
+	_ = json.Unmarshal(params, &requested)
+
+	version := protocolVersions[0]
+	for _, v := range protocolVersions {
+		if v == requested.ProtocolVersion {
+			version = v
+		}
+	}
+	return gin.H{
+		"protocolVersion": version,
+		"capabilities":    gin.H{"tools": gin.H{"listChanged": false}},
+		"serverInfo":      gin.H{"name": info.SlugName, "version": info.Version},
+	}
+}
+
+// callTool reports failures of the tool in the result so the agent can read them and retry,
+// only unknown tools and malformed params are protocol errors
+func callTool(ctx *gin.Context, params json.RawMessage, tools *ToolSet) (*toolResult, *rpcError) {
+	call := struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}{}
+	if err := json.Unmarshal(params, &call); err != nil || call.Name == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "tools/call needs a tool name"}
+	}
+
+	output, err := tools.Call(ctx.Request.Context(), call.Name, call.Arguments)
+	if errors.Is(err, ErrUnknownTool) {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	if err != nil {
+		return &toolResult{Content: []toolContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+
+	text, err := json.Marshal(output)
+	if err != nil {
+		return &toolResult{Content: []toolContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	result := &toolResult{Content: []toolContent{{Type: "text", Text: string(text)}}}
+	// Structured content must be an object, other results are only sent as text
+	var structured map[string]any
+	if json.Unmarshal(text, &structured) == nil && structured != nil {
+		result.StructuredContent = structured
+	}
+	return result, nil
+}
@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Tools declares the tools of the plugin, add yours here
+func Tools(client *AnswerClient) []*Tool {
+	return []*Tool{
+		questionsByTagTool(client),
+	}
+}
+
+// QuestionsByTagInput are the arguments of questions_by_tag
+type QuestionsByTagInput struct {
+	Tag      string `json:"tag"`
+	Order    string `json:"order"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+// QuestionsByTagOutput is the result of questions_by_tag
+type QuestionsByTagOutput struct {
+	Total     int               `json:"total"`
+	Questions []*QuestionResult `json:"questions"`
+}
+
+// QuestionResult is a question as the tools return it
+type QuestionResult struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	Tags        []string `json:"tags"`
+	AnswerCount int      `json:"answer_count"`
+	VoteCount   int      `json:"vote_count"`
+	ViewCount   int      `json:"view_count"`
+	CreatedAt   int64    `json:"created_at"`
+}
+
+func questionsByTagTool(client *AnswerClient) *Tool {
+	return &Tool{
+		Name:        "questions_by_tag",
+		Description: "List the questions of this Answer site that have a tag, with links, answer and vote counts.",
+		InputSchema: Closed(&Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"tag": {Type: "string", Description: "Tag slug, e.g. \"golang\"", MinLength: Bound(1)},
+				"order": {
+					Type:        "string",
+					Description: "Sort order",
+					Enum:        []any{"newest", "active", "score", "unanswered"},
+					Default:     "newest",
+				},
+				"page":      {Type: "integer", Minimum: Bound(1.0), Default: 1},
+				"page_size": {Type: "integer", Minimum: Bound(1.0), Maximum: Bound(50.0), Default: 10},
+			},
+			Required: []string{"tag"},
+		}),
+		OutputSchema: &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"total": {Type: "integer", Minimum: Bound(0.0)},
+				"questions": {
+					Type: "array",
+					Items: &Schema{
+						Type: "object",
+						Properties: map[string]*Schema{
+							"id":           {Type: "string"},
+							"title":        {Type: "string"},
+							"url":          {Type: "string"},
+							"tags":         {Type: "array", Items: &Schema{Type: "string"}},
+							"answer_count": {Type: "integer"},
+							"vote_count":   {Type: "integer"},
+							"view_count":   {Type: "integer"},
+							"created_at":   {Type: "integer"},
+						},
+						Required: []string{"id", "title", "url", "tags"},
+					},
+				},
+			},
+			Required: []string{"total", "questions"},
+		},
+		Example: `{"tag": "golang", "order": "score", "page_size": 5}`,
+		Handler: Handle(func(ctx context.Context, input *QuestionsByTagInput) (*QuestionsByTagOutput, error) {
+			return questionsByTag(ctx, client, input)
+		}),
+	}
+}
+
+func questionsByTag(ctx context.Context, client *AnswerClient, input *QuestionsByTagInput) (*QuestionsByTagOutput, error) {
+	if input.Order == "" {
+		input.Order = "newest"
+	}
+	if input.Page < 1 {
+		input.Page = 1
+	}
+	if input.PageSize < 1 {
+		input.PageSize = 10
+	}
+
+	page := struct {
+		Count int `json:"count"`
+		List  []struct {
+			ID          string `json:"id"`
+			Title       string `json:"title"`
+			URLTitle    string `json:"url_title"`
+			AnswerCount int    `json:"answer_count"`
+			VoteCount   int    `json:"vote_count"`
+			ViewCount   int    `json:"view_count"`
+			CreatedAt   int64  `json:"created_at"`
+			Tags        []struct {
+				SlugName string `json:"slug_name"`
+			} `json:"tags"`
+		} `json:"list"`
+	}{}
+	query := url.Values{
+		"tag":       {input.Tag},
+		"order":     {input.Order},
+		"page":      {strconv.Itoa(input.Page)},
+		"page_size": {strconv.Itoa(input.PageSize)},
+	}
+	if err := client.Get(ctx, "/question/page", query, &page); err != nil {
+		return nil, err
+	}
+
+	output := &QuestionsByTagOutput{Total: page.Count, Questions: []*QuestionResult{}}
+	for _, q := range page.List {
+		result := &QuestionResult{
+			ID:          q.ID,
+			Title:       q.Title,
+			URL:         fmt.Sprintf("%s/questions/%s/%s", client.SiteURL(), q.ID, q.URLTitle),
+			Tags:        []string{},
+			AnswerCount: q.AnswerCount,
+			VoteCount:   q.VoteCount,
+			ViewCount:   q.ViewCount,
+			CreatedAt:   q.CreatedAt,
+		}
+		for _, tag := range q.Tags {
+			result.Tags = append(result.Tags, tag.SlugName)
+		}
+		output.Questions = append(output.Questions, result)
+	}
+	return output, nil
+}
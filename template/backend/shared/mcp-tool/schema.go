@@ -0,0 +1,178 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is the subset of JSON Schema the tools declare their input and output with.
+// It marshals to the JSON Schema MCP clients expect.
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// AdditionalProperties rejects unknown object properties when false
+	AdditionalProperties *bool    `json:"additionalProperties,omitempty"`
+	Items                *Schema  `json:"items,omitempty"`
+	Enum                 []any    `json:"enum,omitempty"`
+	Minimum              *float64 `json:"minimum,omitempty"`
+	Maximum              *float64 `json:"maximum,omitempty"`
+	MinLength            *int     `json:"minLength,omitempty"`
+	MaxLength            *int     `json:"maxLength,omitempty"`
+	Default              any      `json:"default,omitempty"`
+}
+
+// Bound returns a pointer for the Minimum, Maximum, MinLength and MaxLength fields
+func Bound[T int | float64](v T) *T {
+	return &v
+}
+
+// Closed marks an object schema as rejecting unknown properties
+func Closed(s *Schema) *Schema {
+	closed := false
+	s.AdditionalProperties = &closed
+	return s
+}
+
+// Validate checks a value decoded from JSON into any against the schema
+func (s *Schema) Validate(value any) error {
+	return s.validate("$", value)
+}
+
+func (s *Schema) validate(path string, value any) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.validateType(path, value); err != nil {
+		return err
+	}
+	if len(s.Enum) > 0 && !s.inEnum(value) {
+		return fmt.Errorf("%s: %v is not one of %v", path, value, s.Enum)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			return fmt.Errorf("%s: %v is less than %v", path, v, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return fmt.Errorf("%s: %v is greater than %v", path, v, *s.Maximum)
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			return fmt.Errorf("%s: shorter than %d characters", path, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fmt.Errorf("%s: longer than %d characters", path, *s.MaxLength)
+		}
+	case []any:
+		for i, item := range v {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unknown property %q", path, name)
+				}
+				continue
+			}
+			if err := property.validate(path+"."+name, v[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateType(path string, value any) error {
+	var ok bool
+	switch s.Type {
+	case "":
+		return nil
+	case "object":
+		_, ok = value.(map[string]any)
+	case "array":
+		_, ok = value.([]any)
+	case "string":
+		_, ok = value.(string)
+	case "number":
+		_, ok = value.(float64)
+	case "integer":
+		v, isNumber := value.(float64)
+		ok = isNumber && v == math.Trunc(v)
+	case "boolean":
+		_, ok = value.(bool)
+	case "null":
+		ok = value == nil
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+	if !ok {
+		return fmt.Errorf("%s: expected %s, got %s", path, s.Type, jsonType(value))
+	}
+	return nil
+}
+
+func (s *Schema) inEnum(value any) bool {
+	for _, allowed := range s.Enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) && jsonType(allowed) == jsonType(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, int:
+		return "number"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", value), "*")
+}
@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ToolHandler runs a tool with its raw JSON arguments, the result is marshaled to JSON
+type ToolHandler func(ctx context.Context, args json.RawMessage) (any, error)
+
+// Tool is a function agents can call
+type Tool struct {
+	// Name is unique within the plugin, e.g. "questions_by_tag"
+	Name        string
+	Description string
+	InputSchema *Schema
+	// OutputSchema describes the result, clients get it as structured content
+	OutputSchema *Schema
+	// Example is a sample input, the generated tests call the tool with it
+	Example string
+	Handler ToolHandler
+}
+
+// Handle adapts a typed function to a ToolHandler, the arguments are decoded into In
+func Handle[In, Out any](fn func(ctx context.Context, input *In) (*Out, error)) ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		input := new(In)
+		if len(bytes.TrimSpace(args)) > 0 {
+			if err := json.Unmarshal(args, input); err != nil {
+				return nil, &InputError{err}
+			}
+		}
+		return fn(ctx, input)
+	}
+}
+
+// InputError reports arguments that don't match the tool's input schema
+type InputError struct {
+	Err error
+}
+
+func (e *InputError) Error() string {
+	return "invalid arguments: " + e.Err.Error()
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnknownTool is returned by Call for names no tool has
+var ErrUnknownTool = errors.New("unknown tool")
+
+// ToolSet holds the tools of the plugin in declaration order
+type ToolSet struct {
+	tools  []*Tool
+	byName map[string]*Tool
+}
+
+// NewToolSet panics on duplicate names, tools are declared in code
+func NewToolSet(tools ...*Tool) *ToolSet {
+	set := &ToolSet{byName: make(map[string]*Tool, len(tools))}
+	for _, tool := range tools {
+		if _, ok := set.byName[tool.Name]; ok {
+			panic(fmt.Sprintf("duplicate tool %q", tool.Name))
+		}
+		set.tools = append(set.tools, tool)
+		set.byName[tool.Name] = tool
+	}
+	return set
+}
+
+// List returns the tools in declaration order
+func (s *ToolSet) List() []*Tool {
+	return s.tools
+}
+
+// Call validates the arguments against the tool's input schema and runs it
+func (s *ToolSet) Call(ctx context.Context, name string, args json.RawMessage) (any, error) {
+	tool, ok := s.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTool, name)
+	}
+	if len(bytes.TrimSpace(args)) == 0 {
+		args = json.RawMessage("{}")
+	}
+	var decoded any
+	if err := json.Unmarshal(args, &decoded); err != nil {
+		return nil, &InputError{err}
+	}
+	if err := tool.InputSchema.Validate(decoded); err != nil {
+		return nil, &InputError{err}
+	}
+	return tool.Handler(ctx, args)
+}
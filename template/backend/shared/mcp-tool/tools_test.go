@@ -0,0 +1,224 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+
+	"{{testkit_module}}"
+)
+
+// answerFixtures are the responses of the fake Answer site by API path.
+// Add the endpoints your tools call.
+var answerFixtures = map[string]string{
+	"/answer/api/v1/question/page": `{"code": 200, "msg": "Success.", "data": {"count": 1, "list": [{
+		"id": "10010000000000001", "title": "How do I use generics?", "url_title": "how-do-i-use-generics",
+		"answer_count": 2, "vote_count": 5, "view_count": 42, "created_at": 1700000000,
+		"tags": [{"slug_name": "golang", "display_name": "Go"}]
+	}]}}`,
+}
+
+func newFakeAnswerSite(t *testing.T) *AnswerClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := answerFixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "msg": "no fixture for ` + r.URL.Path + `"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewAnswerClient(server.Client(), server.URL)
+}
+
+// TestTools calls every tool with its example and checks the input and output against the schemas
+func TestTools(t *testing.T) {
+	tools := NewToolSet(Tools(newFakeAnswerSite(t))...)
+	if len(tools.List()) == 0 {
+		t.Fatal("no tools declared")
+	}
+
+	for _, tool := range tools.List() {
+		t.Run(tool.Name, func(t *testing.T) {
+			if tool.Description == "" {
+				t.Error("missing description")
+			}
+			if tool.InputSchema == nil || tool.InputSchema.Type != "object" {
+				t.Fatal("the input schema must describe an object")
+			}
+			if tool.OutputSchema == nil {
+				t.Fatal("missing output schema")
+			}
+			if tool.Example == "" {
+				t.Fatal("missing example input")
+			}
+
+			var example any
+			if err := json.Unmarshal([]byte(tool.Example), &example); err != nil {
+				t.Fatalf("example is not JSON: %v", err)
+			}
+			if err := tool.InputSchema.Validate(example); err != nil {
+				t.Fatalf("example does not match the input schema: %v", err)
+			}
+
+			output, err := tools.Call(context.Background(), tool.Name, json.RawMessage(tool.Example))
+			if err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+			raw, err := json.Marshal(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded any
+			if err := json.Unmarshal(raw, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if err := tool.OutputSchema.Validate(decoded); err != nil {
+				t.Errorf("output does not match the output schema: %v\n%s", err, raw)
+			}
+		})
+	}
+}
+
+func TestToolsRejectInvalidArguments(t *testing.T) {
+	tools := NewToolSet(Tools(newFakeAnswerSite(t))...)
+	for _, tool := range tools.List() {
+		_, err := tools.Call(context.Background(), tool.Name, json.RawMessage(`[]`))
+		if _, ok := err.(*InputError); !ok {
+			t.Errorf("%s: got %v, want an InputError for an array", tool.Name, err)
+		}
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema := Closed(&Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name":  {Type: "string", MinLength: Bound(1), MaxLength: Bound(5)},
+			"count": {Type: "integer", Minimum: Bound(1.0), Maximum: Bound(10.0)},
+			"kind":  {Type: "string", Enum: []any{"a", "b"}},
+			"tags":  {Type: "array", Items: &Schema{Type: "string"}},
+		},
+		Required: []string{"name"},
+	})
+
+	tests := []struct {
+		input string
+		valid bool
+	}{
+		{`{"name": "x"}`, true},
+		{`{"name": "x", "count": 3, "kind": "b", "tags": ["a"]}`, true},
+		{`{}`, false},
+		{`{"name": ""}`, false},
+		{`{"name": "toolong"}`, false},
+		{`{"name": "x", "count": 1.5}`, false},
+		{`{"name": "x", "count": 0}`, false},
+		{`{"name": "x", "count": 11}`, false},
+		{`{"name": "x", "kind": "c"}`, false},
+		{`{"name": "x", "tags": [1]}`, false},
+		{`{"name": "x", "other": true}`, false},
+		{`"x"`, false},
+	}
+	for _, tt := range tests {
+		var value any
+		if err := json.Unmarshal([]byte(tt.input), &value); err != nil {
+			t.Fatal(err)
+		}
+		err := schema.Validate(value)
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%s): got %v, want valid=%v", tt.input, err, tt.valid)
+		}
+	}
+}
+
+func TestServeMCP(t *testing.T) {
+	tools := NewToolSet(Tools(newFakeAnswerSite(t))...)
+	info := plugin.Info{SlugName: "{{plugin_slug_name}}", Version: "test"}
+
+	post := func(body string) (int, map[string]any) {
+		t.Helper()
+		req := testkit.NewRequest(t, http.MethodPost, "/mcp", testkit.WithBody("application/json", []byte(body)))
+		ctx, rec := testkit.NewGinContext(t, req)
+		ServeMCP(ctx, info, tools)
+		ctx.Writer.WriteHeaderNow()
+		resp := map[string]any{}
+		if rec.Body.Len() > 0 {
+			testkit.DecodeJSON(t, rec, &resp)
+		}
+		return rec.Code, resp
+	}
+	result := func(resp map[string]any) map[string]any {
+		t.Helper()
+		r, ok := resp["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("no result: %v", resp)
+		}
+		return r
+	}
+
+	_, resp := post(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`)
+	if v := result(resp)["protocolVersion"]; v != "2025-03-26" {
+		t.Errorf("initialize: got protocol %v, want the client's", v)
+	}
+
+	if code, _ := post(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`); code != http.StatusAccepted {
+		t.Errorf("notification: got status %d, want 202", code)
+	}
+
+	_, resp = post(`{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`)
+	if listed := result(resp)["tools"].([]any); len(listed) != len(tools.List()) {
+		t.Errorf("tools/list: got %d tools, want %d", len(listed), len(tools.List()))
+	}
+
+	for _, tool := range tools.List() {
+		_, resp = post(`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "` + tool.Name + `", "arguments": ` + tool.Example + `}}`)
+		r := result(resp)
+		if r["isError"] == true || r["structuredContent"] == nil {
+			t.Errorf("tools/call %s: got %v", tool.Name, r)
+		}
+	}
+
+	// Invalid arguments are reported to the agent as a tool error
+	for _, tool := range tools.List() {
+		if len(tool.InputSchema.Required) == 0 {
+			continue
+		}
+		_, resp = post(`{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "` + tool.Name + `", "arguments": {}}}`)
+		if r := result(resp); r["isError"] != true {
+			t.Errorf("tools/call %s without required arguments: got %v, want isError", tool.Name, r)
+		}
+	}
+
+	_, resp = post(`{"jsonrpc": "2.0", "id": 5, "method": "tools/call", "params": {"name": "nope"}}`)
+	if resp["error"] == nil || resp["result"] != nil {
+		t.Errorf("tools/call of an unknown tool: got %v, want an error", resp)
+	}
+
+	_, resp = post(`{"jsonrpc": "2.0", "id": 6, "method": "resources/list"}`)
+	if e, _ := resp["error"].(map[string]any); e == nil || e["code"] != float64(codeMethodNotFound) {
+		t.Errorf("unknown method: got %v", resp)
+	}
+}
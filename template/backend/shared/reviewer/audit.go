@@ -0,0 +1,609 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	auditGroup         = "audit"
+	auditFeedbackGroup = "audit_feedback"
+	auditMetaGroup     = "audit_meta"
+	auditQueueSize     = 1024
+	auditPageSize      = 200
+	auditPruneInterval = time.Hour
+
+	// defaultAuditRetention is how long entries are kept unless SetRetention says otherwise
+	defaultAuditRetention = 90 * 24 * time.Hour
+)
+
+var errAuditNotConnected = errors.New("audit log is not connected to the KV storage yet")
+
+// AuditEntry records a single Review call. Entries are never updated,
+// moderator feedback is stored separately under the same ID.
+type AuditEntry struct {
+	ID         string              `json:"id"`
+	Time       time.Time           `json:"time"`
+	ObjectType string              `json:"object_type"`
+	InputHash  string              `json:"input_hash"`
+	Decision   plugin.ReviewStatus `json:"decision"`
+	Approved   bool                `json:"approved"`
+	// Rule names what decided, empty when nothing matched
+	Rule      string         `json:"rule"`
+	Reason    string         `json:"reason"`
+	LatencyMS float64        `json:"latency_ms"`
+	Feedback  *AuditFeedback `json:"feedback,omitempty"`
+}
+
+// AuditFeedback is a moderator's verdict on an audit entry
+type AuditFeedback struct {
+	FalsePositive bool      `json:"false_positive"`
+	Note          string    `json:"note"`
+	MarkedAt      time.Time `json:"marked_at"`
+}
+
+// AuditRuleStats summarizes the decisions of one rule, for tuning rules and thresholds
+type AuditRuleStats struct {
+	Rule           string `json:"rule"`
+	Total          int    `json:"total"`
+	Flagged        int    `json:"flagged"`
+	FalsePositives int    `json:"false_positives"`
+}
+
+// auditStore is the part of the KV operator the audit log uses
+type auditStore interface {
+	Get(ctx context.Context, params plugin.KVParams) (string, error)
+	Set(ctx context.Context, params plugin.KVParams) error
+	Del(ctx context.Context, params plugin.KVParams) error
+	GetByGroup(ctx context.Context, params plugin.KVParams) (map[string]string, error)
+}
+
+// AuditLog appends review decisions to the KV storage. Entries are written
+// in the background so Review never waits on the database.
+//
+// Entries are keyed by a sequence number, so a page of the log is read
+// without loading the rest of it. The range of numbers in use is kept in
+// the audit_meta group, which assumes a single Answer instance writes the log.
+type AuditLog struct {
+	queue     chan AuditEntry
+	connected chan struct{}
+	retention atomic.Int64
+	// pending are the entries waiting for SetStore, only the writer uses it
+	pending []AuditEntry
+
+	mu    sync.RWMutex
+	store auditStore
+	// first and next are the sequence numbers of the oldest entry kept and
+	// of the next one, loaded from the store on first use
+	loaded      bool
+	first, next int64
+}
+
+// NewAuditLog creates the log and starts its writer
+func NewAuditLog() *AuditLog {
+	a := &AuditLog{
+		queue:     make(chan AuditEntry, auditQueueSize),
+		connected: make(chan struct{}, 1),
+	}
+	a.retention.Store(int64(defaultAuditRetention))
+	go a.write()
+	return a
+}
+
+// SetStore connects the log to the KV storage. Up to auditQueueSize entries
+// recorded before are kept and written then, later ones are dropped.
+func (a *AuditLog) SetStore(store auditStore) {
+	a.mu.Lock()
+	a.store = store
+	a.loaded = false
+	a.mu.Unlock()
+
+	select {
+	case a.connected <- struct{}{}:
+	default:
+	}
+}
+
+// SetRetention sets how long entries are kept, 0 keeps them all. Older
+// entries and their feedback are deleted every hour.
+func (a *AuditLog) SetRetention(retention time.Duration) {
+	a.retention.Store(int64(retention))
+}
+
+// bounds returns the store and the range of sequence numbers in the log
+func (a *AuditLog) bounds(ctx context.Context) (store auditStore, first, next int64, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.store == nil {
+		return nil, 0, 0, errAuditNotConnected
+	}
+	if !a.loaded {
+		if a.first, err = getAuditMeta(ctx, a.store, "first"); err != nil {
+			return nil, 0, 0, err
+		}
+		if a.next, err = getAuditMeta(ctx, a.store, "next"); err != nil {
+			return nil, 0, 0, err
+		}
+		a.loaded = true
+	}
+	return a.store, a.first, a.next, nil
+}
+
+func getAuditMeta(ctx context.Context, store auditStore, key string) (int64, error) {
+	value, err := store.Get(ctx, plugin.KVParams{Group: auditMetaGroup, Key: key})
+	if errors.Is(err, plugin.ErrKVKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func setAuditMeta(ctx context.Context, store auditStore, key string, value int64) error {
+	return store.Set(ctx, plugin.KVParams{Group: auditMetaGroup, Key: key, Value: strconv.FormatInt(value, 10)})
+}
+
+// auditID is the key of the entry with the sequence number seq, zero-padded
+// so that IDs sort in the order entries were written
+func auditID(seq int64) string {
+	return fmt.Sprintf("%019d", seq)
+}
+
+// ContentHash identifies the reviewed content without keeping it in the log
+func ContentHash(content *plugin.ReviewContent) string {
+	h := sha256.New()
+	for _, part := range []string{content.ObjectType, content.Title, content.Content, strings.Join(content.Tags, "\x00")} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Record queues an entry for the review that started at start. It drops the
+// entry rather than block when the queue is full, a nil log records nothing.
+func (a *AuditLog) Record(content *plugin.ReviewContent, result *plugin.ReviewResult, rule string, start time.Time) {
+	if a == nil {
+		return
+	}
+	entry := AuditEntry{
+		Time:       start.UTC(),
+		ObjectType: content.ObjectType,
+		InputHash:  ContentHash(content),
+		Decision:   result.ReviewStatus,
+		Approved:   result.Approved,
+		Rule:       rule,
+		Reason:     result.Reason,
+		LatencyMS:  float64(time.Since(start).Microseconds()) / 1000,
+	}
+	select {
+	case a.queue <- entry:
+	default:
+		log.Warnf("{{plugin_slug_name}}: audit queue full, dropped %s entry", entry.ObjectType)
+	}
+}
+
+func (a *AuditLog) write() {
+	ticker := time.NewTicker(auditPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case entry := <-a.queue:
+			a.append(context.Background(), entry)
+		case <-a.connected:
+			a.flush(context.Background())
+		case <-ticker.C:
+			a.flush(context.Background())
+			a.prune(context.Background())
+		}
+	}
+}
+
+// append stores the entry after the ones waiting for SetStore. Until it is
+// called, entries wait as well, up to auditQueueSize of them.
+func (a *AuditLog) append(ctx context.Context, entry AuditEntry) {
+	if len(a.pending) >= auditQueueSize {
+		log.Warnf("{{plugin_slug_name}}: audit log not connected, dropped %s entry", entry.ObjectType)
+	} else {
+		a.pending = append(a.pending, entry)
+	}
+	a.flush(ctx)
+}
+
+// flush stores the waiting entries in the order they were recorded, unless
+// the log isn't connected yet
+func (a *AuditLog) flush(ctx context.Context) {
+	for len(a.pending) > 0 {
+		if errors.Is(a.writeEntry(ctx, a.pending[0]), errAuditNotConnected) {
+			return
+		}
+		a.pending = a.pending[1:]
+	}
+	a.pending = nil
+}
+
+// writeEntry numbers the entry and stores it. The counter is saved first, an
+// entry that fails to be written leaves a gap rather than a reused ID. Only
+// errAuditNotConnected is returned, other errors are logged and drop the entry.
+func (a *AuditLog) writeEntry(ctx context.Context, entry AuditEntry) error {
+	store, _, next, err := a.bounds(ctx)
+	if errors.Is(err, errAuditNotConnected) {
+		return err
+	}
+	if err != nil {
+		log.Errorf("{{plugin_slug_name}}: load audit log: %v", err)
+		return nil
+	}
+
+	entry.ID = auditID(next)
+	if err := setAuditMeta(ctx, store, "next", next+1); err != nil {
+		log.Errorf("{{plugin_slug_name}}: write audit entry %s: %v", entry.ID, err)
+		return nil
+	}
+	a.mu.Lock()
+	a.next = next + 1
+	a.mu.Unlock()
+
+	value, _ := json.Marshal(entry)
+	err = store.Set(ctx, plugin.KVParams{Group: auditGroup, Key: entry.ID, Value: string(value)})
+	if err != nil {
+		log.Errorf("{{plugin_slug_name}}: write audit entry %s: %v", entry.ID, err)
+	}
+	return nil
+}
+
+// prune deletes the entries older than the retention, with their feedback.
+// Entries are numbered in time order, so it stops at the first one it keeps.
+func (a *AuditLog) prune(ctx context.Context) {
+	retention := time.Duration(a.retention.Load())
+	if retention <= 0 {
+		return
+	}
+	store, first, next, err := a.bounds(ctx)
+	if err != nil {
+		if !errors.Is(err, errAuditNotConnected) {
+			log.Errorf("{{plugin_slug_name}}: load audit log: %v", err)
+		}
+		return
+	}
+
+	cutoff := time.Now().Add(-retention)
+	seq := first
+	for ; seq < next; seq++ {
+		id := auditID(seq)
+		value, err := store.Get(ctx, plugin.KVParams{Group: auditGroup, Key: id})
+		if errors.Is(err, plugin.ErrKVKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			log.Errorf("{{plugin_slug_name}}: prune audit log: %v", err)
+			break
+		}
+		entry := &AuditEntry{}
+		if json.Unmarshal([]byte(value), entry) == nil && !entry.Time.Before(cutoff) {
+			break
+		}
+		if err := store.Del(ctx, plugin.KVParams{Group: auditFeedbackGroup, Key: id}); err != nil {
+			log.Errorf("{{plugin_slug_name}}: prune audit log: %v", err)
+			break
+		}
+		if err := store.Del(ctx, plugin.KVParams{Group: auditGroup, Key: id}); err != nil {
+			log.Errorf("{{plugin_slug_name}}: prune audit log: %v", err)
+			break
+		}
+	}
+	if seq == first {
+		return
+	}
+	if err := setAuditMeta(ctx, store, "first", seq); err != nil {
+		log.Errorf("{{plugin_slug_name}}: prune audit log: %v", err)
+		return
+	}
+	a.mu.Lock()
+	a.first = seq
+	a.mu.Unlock()
+}
+
+// Entries loads a page of the log with feedback attached, newest first, and
+// the number of entries in the log. Entries that failed to be written leave
+// their page short.
+func (a *AuditLog) Entries(ctx context.Context, page, pageSize int) ([]*AuditEntry, int, error) {
+	store, first, next, err := a.bounds(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]*AuditEntry, 0, pageSize)
+	top := next - 1 - int64((page-1)*pageSize)
+	for seq := top; seq >= first && seq > top-int64(pageSize); seq-- {
+		entry, err := getAuditEntry(ctx, store, auditID(seq))
+		if errors.Is(err, plugin.ErrKVKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, int(next - first), nil
+}
+
+// getAuditEntry loads an entry with its feedback
+func getAuditEntry(ctx context.Context, store auditStore, id string) (*AuditEntry, error) {
+	value, err := store.Get(ctx, plugin.KVParams{Group: auditGroup, Key: id})
+	if err != nil {
+		return nil, err
+	}
+	entry := &AuditEntry{}
+	if err := json.Unmarshal([]byte(value), entry); err != nil {
+		return nil, fmt.Errorf("invalid audit entry %s: %w", id, err)
+	}
+	value, err = store.Get(ctx, plugin.KVParams{Group: auditFeedbackGroup, Key: id})
+	if errors.Is(err, plugin.ErrKVKeyNotFound) {
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry.Feedback = &AuditFeedback{}
+	_ = json.Unmarshal([]byte(value), entry.Feedback)
+	return entry, nil
+}
+
+// each calls fn with every entry of the log, feedback attached, reading it a
+// page of the storage at a time. Entries come in the order of the storage.
+func (a *AuditLog) each(ctx context.Context, fn func(entry *AuditEntry) error) error {
+	store, _, _, err := a.bounds(ctx)
+	if err != nil {
+		return err
+	}
+	// Feedback is only given on a few entries, it is loaded up front
+	feedback := map[string]string{}
+	err = eachPage(ctx, store, auditFeedbackGroup, func(values map[string]string) error {
+		for k, v := range values {
+			feedback[k] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return eachPage(ctx, store, auditGroup, func(values map[string]string) error {
+		ids := make([]string, 0, len(values))
+		for id := range values {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			entry := &AuditEntry{}
+			if err := json.Unmarshal([]byte(values[id]), entry); err != nil {
+				log.Warnf("{{plugin_slug_name}}: skip invalid audit entry %s: %v", id, err)
+				continue
+			}
+			if value, ok := feedback[id]; ok {
+				entry.Feedback = &AuditFeedback{}
+				_ = json.Unmarshal([]byte(value), entry.Feedback)
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func eachPage(ctx context.Context, store auditStore, group string, fn func(values map[string]string) error) error {
+	for page := 1; ; page++ {
+		values, err := store.GetByGroup(ctx, plugin.KVParams{Group: group, Page: page, PageSize: auditPageSize})
+		if err != nil {
+			return err
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+		if len(values) < auditPageSize {
+			return nil
+		}
+	}
+}
+
+// Mark stores a moderator's feedback on an entry
+func (a *AuditLog) Mark(ctx context.Context, id string, feedback *AuditFeedback) error {
+	store, _, _, err := a.bounds(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := store.Get(ctx, plugin.KVParams{Group: auditGroup, Key: id}); err != nil {
+		return err
+	}
+	feedback.MarkedAt = time.Now().UTC()
+	value, _ := json.Marshal(feedback)
+	return store.Set(ctx, plugin.KVParams{Group: auditFeedbackGroup, Key: id, Value: string(value)})
+}
+
+// RuleStats counts decisions and false positives per rule
+func (a *AuditLog) RuleStats(ctx context.Context) ([]AuditRuleStats, error) {
+	byRule := map[string]*AuditRuleStats{}
+	err := a.each(ctx, func(entry *AuditEntry) error {
+		stats, ok := byRule[entry.Rule]
+		if !ok {
+			stats = &AuditRuleStats{Rule: entry.Rule}
+			byRule[entry.Rule] = stats
+		}
+		stats.Total++
+		if !entry.Approved {
+			stats.Flagged++
+		}
+		if entry.Feedback != nil && entry.Feedback.FalsePositive {
+			stats.FalsePositives++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]AuditRuleStats, 0, len(byRule))
+	for _, stats := range byRule {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Rule < result[j].Rule })
+	return result, nil
+}
+
+// RegisterRoutes exposes the log to administrators:
+//
+//	GET /                      entries, newest first, ?page=&page_size=&decision=&rule=&false_positive=true
+//	GET /rules                 decisions and false positives per rule
+//	GET /export                the whole log, ?format=csv or jsonl (default)
+//	PUT /:id/feedback          mark an entry, body {"false_positive": true, "note": "..."}
+func (a *AuditLog) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("", a.listEntries)
+	group.GET("/rules", a.ruleStats)
+	group.GET("/export", a.export)
+	group.PUT("/:id/feedback", a.markEntry)
+}
+
+// listEntries reads only the requested page of the log. Filters need the
+// whole log, which retention keeps bounded.
+func (a *AuditLog) listEntries(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > auditPageSize {
+		pageSize = 20
+	}
+
+	decision, rule := ctx.Query("decision"), ctx.Query("rule")
+	falsePositive := ctx.Query("false_positive") == "true"
+	if decision == "" && rule == "" && !falsePositive {
+		entries, count, err := a.Entries(ctx.Request.Context(), page, pageSize)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"count": count, "list": entries})
+		return
+	}
+
+	filtered := []*AuditEntry{}
+	err := a.each(ctx.Request.Context(), func(entry *AuditEntry) error {
+		switch {
+		case decision != "" && string(entry.Decision) != decision:
+		case rule != "" && entry.Rule != rule:
+		case falsePositive && (entry.Feedback == nil || !entry.Feedback.FalsePositive):
+		default:
+			filtered = append(filtered, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].ID > filtered[j].ID })
+	start := min((page-1)*pageSize, len(filtered))
+	end := min(start+pageSize, len(filtered))
+	ctx.JSON(http.StatusOK, gin.H{"count": len(filtered), "list": filtered[start:end]})
+}
+
+func (a *AuditLog) ruleStats(ctx *gin.Context) {
+	stats, err := a.RuleStats(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"rules": stats})
+}
+
+// export streams the log a page at a time. Once the first entry is written
+// the status can't change, a later error only ends the file early.
+func (a *AuditLog) export(ctx *gin.Context) {
+	if _, _, _, err := a.bounds(ctx.Request.Context()); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var write func(e *AuditEntry) error
+	var flush func()
+	if ctx.Query("format") == "csv" {
+		ctx.Header("Content-Disposition", `attachment; filename="{{plugin_slug_name}}-audit.csv"`)
+		ctx.Header("Content-Type", "text/csv")
+		w := csv.NewWriter(ctx.Writer)
+		_ = w.Write([]string{"id", "time", "object_type", "input_hash", "decision", "rule", "reason", "latency_ms", "false_positive", "note"})
+		write = func(e *AuditEntry) error {
+			falsePositive, note := "", ""
+			if e.Feedback != nil {
+				falsePositive, note = strconv.FormatBool(e.Feedback.FalsePositive), e.Feedback.Note
+			}
+			return w.Write([]string{e.ID, e.Time.Format(time.RFC3339), e.ObjectType, e.InputHash, string(e.Decision),
+				e.Rule, e.Reason, strconv.FormatFloat(e.LatencyMS, 'f', 3, 64), falsePositive, note})
+		}
+		flush = w.Flush
+	} else {
+		ctx.Header("Content-Disposition", `attachment; filename="{{plugin_slug_name}}-audit.jsonl"`)
+		ctx.Header("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(ctx.Writer)
+		write = func(e *AuditEntry) error { return enc.Encode(e) }
+		flush = func() {}
+	}
+
+	if err := a.each(ctx.Request.Context(), write); err != nil {
+		log.Errorf("{{plugin_slug_name}}: export audit log: %v", err)
+	}
+	flush()
+}
+
+func (a *AuditLog) markEntry(ctx *gin.Context) {
+	feedback := &AuditFeedback{}
+	if err := ctx.ShouldBindJSON(feedback); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err := a.Mark(ctx.Request.Context(), ctx.Param("id"), feedback)
+	if errors.Is(err, plugin.ErrKVKeyNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "audit entry not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"id": ctx.Param("id"), "feedback": feedback})
+}
@@ -0,0 +1,391 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+
+	"{{testkit_module}}"
+)
+
+// newTestAuditLog returns a log connected to the testkit's KV storage
+func newTestAuditLog(t *testing.T) (*AuditLog, *testkit.KV) {
+	t.Helper()
+	kv := testkit.NewKV()
+	a := NewAuditLog()
+	a.SetStore(kv)
+	return a, kv
+}
+
+// recordAudit records n decisions of rule, starting at start
+func recordAudit(a *AuditLog, n int, rule string, start time.Time) {
+	for i := 0; i < n; i++ {
+		content := &plugin.ReviewContent{ObjectType: "answer", Content: fmt.Sprintf("content %d", i)}
+		result := &plugin.ReviewResult{Approved: rule == "", ReviewStatus: plugin.ReviewStatusApproved}
+		if rule != "" {
+			result.ReviewStatus, result.Reason = plugin.ReviewStatusNeedReview, "matched "+rule
+		}
+		a.Record(content, result, rule, start)
+	}
+}
+
+// waitForAudit waits until the writer has stored n entries
+func waitForAudit(t *testing.T, kv *testkit.KV, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(kv.Data()[auditGroup]) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("audit log has %d entries after 5s, want %d", len(kv.Data()[auditGroup]), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// serveAudit sends a request to the routes of the log
+func serveAudit(t *testing.T, a *AuditLog, method, target string, opts ...testkit.RequestOption) *httptest.ResponseRecorder {
+	t.Helper()
+	engine := gin.New()
+	a.RegisterRoutes(engine.Group("/audit"))
+	return testkit.Serve(engine, testkit.NewRequest(t, method, target, opts...))
+}
+
+func TestAuditRecordAndEntries(t *testing.T) {
+	ctx := context.Background()
+	a := NewAuditLog()
+	// Entries recorded before the storage is connected wait for it
+	recordAudit(a, 5, "", time.Now())
+	if _, _, err := a.Entries(ctx, 1, 10); err == nil {
+		t.Error("Entries before SetStore: got no error")
+	}
+	kv := testkit.NewKV()
+	a.SetStore(kv)
+	recordAudit(a, 20, "links", time.Now())
+	waitForAudit(t, kv, 25)
+
+	for _, value := range kv.Data()[auditGroup] {
+		if strings.Contains(value, "content ") {
+			t.Fatalf("entry %s stores the content", value)
+		}
+	}
+
+	tests := []struct {
+		page, pageSize int
+		want           []string
+	}{
+		{page: 1, pageSize: 10, want: []string{auditID(24), auditID(15)}},
+		{page: 3, pageSize: 10, want: []string{auditID(4), auditID(0)}},
+		{page: 4, pageSize: 10},
+		{page: 2, pageSize: 20, want: []string{auditID(4), auditID(0)}},
+	}
+	for _, tt := range tests {
+		entries, count, err := a.Entries(ctx, tt.page, tt.pageSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 25 {
+			t.Errorf("Entries(%d, %d): count %d, want 25", tt.page, tt.pageSize, count)
+		}
+		if len(tt.want) == 0 {
+			if len(entries) != 0 {
+				t.Errorf("Entries(%d, %d): got %d entries, want none", tt.page, tt.pageSize, len(entries))
+			}
+			continue
+		}
+		if len(entries) == 0 || entries[0].ID != tt.want[0] || entries[len(entries)-1].ID != tt.want[1] {
+			t.Errorf("Entries(%d, %d): got %d entries, want %s to %s", tt.page, tt.pageSize, len(entries), tt.want[0], tt.want[1])
+			continue
+		}
+		for i := 1; i < len(entries); i++ {
+			if entries[i].ID >= entries[i-1].ID {
+				t.Errorf("Entries(%d, %d): %s after %s, want newest first", tt.page, tt.pageSize, entries[i].ID, entries[i-1].ID)
+			}
+		}
+	}
+
+	entries, _, _ := a.Entries(ctx, 1, 1)
+	want := ContentHash(&plugin.ReviewContent{ObjectType: "answer", Content: "content 19"})
+	if e := entries[0]; e.InputHash != want || e.Rule != "links" || e.Approved || e.Decision != plugin.ReviewStatusNeedReview {
+		t.Errorf("newest entry: got %+v", e)
+	}
+
+	// A restarted plugin continues the sequence
+	b := NewAuditLog()
+	b.SetStore(kv)
+	recordAudit(b, 1, "", time.Now())
+	waitForAudit(t, kv, 26)
+	if entries, count, _ := b.Entries(ctx, 1, 1); count != 26 || entries[0].ID != auditID(25) {
+		t.Errorf("after restart: got count %d and %+v", count, entries)
+	}
+}
+
+func TestAuditBuffersUntilConnected(t *testing.T) {
+	a := NewAuditLog()
+	// Wait for the writer to take each batch, so none is dropped by a full queue
+	drain := func() {
+		deadline := time.Now().Add(5 * time.Second)
+		for len(a.queue) > 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("the writer left %d entries in the queue", len(a.queue))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	recordAudit(a, auditQueueSize, "kept", time.Now())
+	drain()
+	// Past the buffer entries are dropped, and the writer keeps taking them
+	recordAudit(a, 10, "dropped", time.Now())
+	drain()
+
+	kv := testkit.NewKV()
+	a.SetStore(kv)
+	waitForAudit(t, kv, auditQueueSize)
+	recordAudit(a, 1, "after", time.Now())
+	waitForAudit(t, kv, auditQueueSize+1)
+
+	for id, value := range kv.Data()[auditGroup] {
+		if strings.Contains(value, `"rule":"dropped"`) {
+			t.Errorf("entry %s was recorded past the buffer, want it dropped", id)
+		}
+	}
+}
+
+func TestAuditMark(t *testing.T) {
+	ctx := context.Background()
+	a, kv := newTestAuditLog(t)
+	recordAudit(a, 2, "links", time.Now())
+	waitForAudit(t, kv, 2)
+
+	if err := a.Mark(ctx, auditID(0), &AuditFeedback{FalsePositive: true, Note: "a docs link"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Mark(ctx, auditID(7), &AuditFeedback{FalsePositive: true}); !errors.Is(err, plugin.ErrKVKeyNotFound) {
+		t.Errorf("Mark unknown entry: got %v, want ErrKVKeyNotFound", err)
+	}
+	entries, _, err := a.Entries(ctx, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].Feedback != nil {
+		t.Errorf("unmarked entry: got feedback %+v", entries[0].Feedback)
+	}
+	if f := entries[1].Feedback; f == nil || !f.FalsePositive || f.Note != "a docs link" || f.MarkedAt.IsZero() {
+		t.Errorf("marked entry: got feedback %+v", f)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		body testkit.RequestOption
+		want int
+	}{
+		{name: "entry", id: auditID(1), body: testkit.WithJSON(map[string]any{"false_positive": true}), want: http.StatusOK},
+		{name: "unknown entry", id: auditID(7), body: testkit.WithJSON(map[string]any{"false_positive": true}), want: http.StatusNotFound},
+		{name: "invalid body", id: auditID(1), body: testkit.WithBody("application/json", []byte("{")), want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := serveAudit(t, a, http.MethodPut, "/audit/"+tt.id+"/feedback", tt.body)
+			if res.Code != tt.want {
+				t.Errorf("got %d %s, want %d", res.Code, res.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditRetention(t *testing.T) {
+	ctx := context.Background()
+	a, kv := newTestAuditLog(t)
+	a.SetRetention(24 * time.Hour)
+	recordAudit(a, 3, "old", time.Now().Add(-48*time.Hour))
+	recordAudit(a, 2, "new", time.Now())
+	waitForAudit(t, kv, 5)
+	if err := a.Mark(ctx, auditID(1), &AuditFeedback{FalsePositive: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	a.prune(ctx)
+	entries, count, err := a.Entries(ctx, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || len(entries) != 2 || entries[1].ID != auditID(3) {
+		t.Errorf("after prune: got count %d and %d entries", count, len(entries))
+	}
+	data := kv.Data()
+	if len(data[auditGroup]) != 2 || len(data[auditFeedbackGroup]) != 0 {
+		t.Errorf("after prune: storage keeps %d entries and %d feedback", len(data[auditGroup]), len(data[auditFeedbackGroup]))
+	}
+	if data[auditMetaGroup]["first"] != "3" {
+		t.Errorf("after prune: first = %q, want 3", data[auditMetaGroup]["first"])
+	}
+
+	// Nothing is kept forever unless asked to
+	a.SetRetention(0)
+	recordAudit(a, 1, "old", time.Now().Add(-48*time.Hour))
+	waitForAudit(t, kv, 3)
+	a.prune(ctx)
+	if _, count, _ := a.Entries(ctx, 1, 10); count != 3 {
+		t.Errorf("retention 0: got count %d, want 3", count)
+	}
+}
+
+func TestAuditListFilters(t *testing.T) {
+	ctx := context.Background()
+	a, kv := newTestAuditLog(t)
+	recordAudit(a, 3, "", time.Now())
+	recordAudit(a, 4, "links", time.Now())
+	recordAudit(a, 5, "caps", time.Now())
+	waitForAudit(t, kv, 12)
+	for _, id := range []string{auditID(3), auditID(8)} {
+		if err := a.Mark(ctx, id, &AuditFeedback{FalsePositive: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query url.Values
+		count int
+		ids   []string
+	}{
+		{name: "page", query: url.Values{"page": {"2"}, "page_size": {"5"}}, count: 12, ids: []string{auditID(6), auditID(5), auditID(4), auditID(3), auditID(2)}},
+		{name: "page size out of range", query: url.Values{"page_size": {"1000"}}, count: 12},
+		{name: "rule", query: url.Values{"rule": {"links"}}, count: 4, ids: []string{auditID(6), auditID(5), auditID(4), auditID(3)}},
+		{name: "decision", query: url.Values{"decision": {string(plugin.ReviewStatusApproved)}}, count: 3, ids: []string{auditID(2), auditID(1), auditID(0)}},
+		{name: "false positives", query: url.Values{"false_positive": {"true"}}, count: 2, ids: []string{auditID(8), auditID(3)}},
+		{name: "filtered page", query: url.Values{"rule": {"caps"}, "page": {"2"}, "page_size": {"2"}}, count: 5, ids: []string{auditID(9), auditID(8)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := serveAudit(t, a, http.MethodGet, "/audit", testkit.WithQuery(tt.query))
+			if res.Code != http.StatusOK {
+				t.Fatalf("got %d %s", res.Code, res.Body.String())
+			}
+			var body struct {
+				Count int           `json:"count"`
+				List  []*AuditEntry `json:"list"`
+			}
+			if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Count != tt.count {
+				t.Errorf("count %d, want %d", body.Count, tt.count)
+			}
+			if tt.ids == nil {
+				if len(body.List) != 12 {
+					t.Errorf("got %d entries, want the default page of 12", len(body.List))
+				}
+				return
+			}
+			ids := make([]string, len(body.List))
+			for i, e := range body.List {
+				ids[i] = e.ID
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.ids, ",") {
+				t.Errorf("got %v, want %v", ids, tt.ids)
+			}
+		})
+	}
+
+	res := serveAudit(t, a, http.MethodGet, "/audit/rules")
+	want := `{"rules":[{"rule":"","total":3,"flagged":0,"false_positives":0},` +
+		`{"rule":"caps","total":5,"flagged":5,"false_positives":1},` +
+		`{"rule":"links","total":4,"flagged":4,"false_positives":1}]}`
+	if res.Body.String() != want {
+		t.Errorf("rules: got %s, want %s", res.Body.String(), want)
+	}
+}
+
+func TestAuditExport(t *testing.T) {
+	ctx := context.Background()
+	a, kv := newTestAuditLog(t)
+	// More than a page of the storage
+	n := auditPageSize + 50
+	recordAudit(a, n, "links", time.Now())
+	waitForAudit(t, kv, n)
+	if err := a.Mark(ctx, auditID(7), &AuditFeedback{FalsePositive: true, Note: "fine, \"really\""}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("jsonl", func(t *testing.T) {
+		res := serveAudit(t, a, http.MethodGet, "/audit/export")
+		if res.Code != http.StatusOK || res.Header().Get("Content-Type") != "application/x-ndjson" {
+			t.Fatalf("got %d %s", res.Code, res.Header().Get("Content-Type"))
+		}
+		lines := strings.Split(strings.TrimSpace(res.Body.String()), "\n")
+		seen := map[string]bool{}
+		for _, line := range lines {
+			entry := &AuditEntry{}
+			if err := json.Unmarshal([]byte(line), entry); err != nil {
+				t.Fatalf("line %q: %v", line, err)
+			}
+			seen[entry.ID] = true
+			if (entry.Feedback != nil) != (entry.ID == auditID(7)) {
+				t.Errorf("entry %s: got feedback %+v", entry.ID, entry.Feedback)
+			}
+		}
+		if len(lines) != n || len(seen) != n {
+			t.Errorf("got %d lines of %d entries, want %d", len(lines), len(seen), n)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		res := serveAudit(t, a, http.MethodGet, "/audit/export?format=csv")
+		if res.Code != http.StatusOK || res.Header().Get("Content-Type") != "text/csv" {
+			t.Fatalf("got %d %s", res.Code, res.Header().Get("Content-Type"))
+		}
+		records, err := csv.NewReader(strings.NewReader(res.Body.String())).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != n+1 || records[0][0] != "id" || len(records[0]) != 10 {
+			t.Fatalf("got %d records, header %v", len(records), records[0])
+		}
+		for _, record := range records[1:] {
+			if record[0] != auditID(7) {
+				continue
+			}
+			if record[8] != "true" || record[9] != `fine, "really"` {
+				t.Errorf("marked entry: got %v", record)
+			}
+			return
+		}
+		t.Errorf("no record of %s", auditID(7))
+	})
+
+	t.Run("not connected", func(t *testing.T) {
+		res := serveAudit(t, NewAuditLog(), http.MethodGet, "/audit/export")
+		if res.Code != http.StatusInternalServerError {
+			t.Errorf("got %d, want %d", res.Code, http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+// VerdictCache keeps review results by key, e.g. the content hash, so edits that don't
+// change the content and resubmissions don't call an external service again. Once
+// maxSize results are kept, the oldest are evicted.
+type VerdictCache struct {
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[string]verdictEntry
+	order   []string
+}
+
+type verdictEntry struct {
+	result    plugin.ReviewResult
+	rule      string
+	expiresAt time.Time
+}
+
+// NewVerdictCache creates a cache, a zero ttl disables caching
+func NewVerdictCache(ttl time.Duration, maxSize int) *VerdictCache {
+	return &VerdictCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]verdictEntry),
+	}
+}
+
+// Get returns the cached result and the rule that produced it
+func (c *VerdictCache) Get(key string) (*plugin.ReviewResult, string, bool) {
+	if c.ttl <= 0 {
+		return nil, "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, "", false
+	}
+	result := entry.result
+	return &result, entry.rule, true
+}
+
+func (c *VerdictCache) Set(key string, result *plugin.ReviewResult, rule string) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = verdictEntry{result: *result, rule: rule, expiresAt: time.Now().Add(c.ttl)}
+
+	// Evict the oldest entries once the cache is full
+	for len(c.order) > c.maxSize {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+func TestVerdictCache(t *testing.T) {
+	result := &plugin.ReviewResult{ReviewStatus: plugin.ReviewStatusNeedReview, Reason: "flagged"}
+
+	t.Run("get what was set", func(t *testing.T) {
+		c := NewVerdictCache(time.Hour, 10)
+		c.Set("key", result, "rule")
+		cached, rule, ok := c.Get("key")
+		if !ok || rule != "rule" || *cached != *result {
+			t.Fatalf("Get = %+v, %q, %v, want the result of rule", cached, rule, ok)
+		}
+		// The caller owns the copy it gets
+		cached.Reason = "changed"
+		if again, _, _ := c.Get("key"); again.Reason != result.Reason {
+			t.Errorf("changing a cached result changed the cache: %+v", again)
+		}
+	})
+
+	t.Run("zero ttl disables", func(t *testing.T) {
+		c := NewVerdictCache(0, 10)
+		c.Set("key", result, "rule")
+		if _, _, ok := c.Get("key"); ok {
+			t.Error("a cache with a zero TTL returned a result")
+		}
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		c := NewVerdictCache(10*time.Millisecond, 10)
+		c.Set("key", result, "rule")
+		time.Sleep(20 * time.Millisecond)
+		if _, _, ok := c.Get("key"); ok {
+			t.Error("Get returned an expired result")
+		}
+	})
+
+	t.Run("evicts the oldest", func(t *testing.T) {
+		const size = 3
+		c := NewVerdictCache(time.Hour, size)
+		for i := 0; i < size+2; i++ {
+			c.Set(fmt.Sprintf("key%d", i), result, "rule")
+		}
+		for i := 0; i < size+2; i++ {
+			_, _, ok := c.Get(fmt.Sprintf("key%d", i))
+			if want := i >= 2; ok != want {
+				t.Errorf("key%d cached: %v, want %v", i, ok, want)
+			}
+		}
+		if len(c.entries) != size || len(c.order) != size {
+			t.Errorf("cache holds %d entries in an order of %d, want %d", len(c.entries), len(c.order), size)
+		}
+	})
+}
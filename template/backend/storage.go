@@ -20,52 +20,40 @@
 package {{package_name}}
 
 import (
-	"embed"
-	"github.com/apache/answer-plugins/{{plugin_name}}/i18n"
-	"github.com/apache/answer-plugins/util"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
 	"github.com/apache/answer/plugin"
 )
 
-//go:embed info.yaml
-var Info embed.FS
-
-type {{plugin_display_name}} struct {
-	Config *{{plugin_display_name}}Config
-}
-
-type {{plugin_display_name}}Config struct {
+//section:config
 	Endpoint        string `json:"endpoint"`
 	BucketName      string `json:"bucket_name"`
 	AccessKeyID     string `json:"access_key_id"`
 	AccessKeySecret string `json:"access_key_secret"`
-}
 
-func init() {
-	plugin.Register(&{{plugin_display_name}}{
-		Config: &{{plugin_display_name}}Config{},
-	})
-}
-
-func (s *{{plugin_display_name}}) Info() plugin.Info {
-	info := &util.Info{}
-	info.GetInfo(Info)
-
-	return plugin.Info{
-		Name:        plugin.MakeTranslator(i18n.InfoName),
-		SlugName:    info.SlugName,
-		Description: plugin.MakeTranslator(i18n.InfoDescription),
-		Author:      info.Author,
-		Version:     info.Version,
-		Link:        info.Link,
+//section:body
+// UploadFile stores the file of the "file" form field, Answer has checked the user may upload
+func (s *{{plugin_display_name}}) UploadFile(ctx *plugin.GinContext, condition plugin.UploadFileCondition) (resp plugin.UploadFileResponse) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		resp.OriginalError = fmt.Errorf("get upload file failed: %w", err)
+		return resp
+	}
+	if s.IsUnsupportedFileType(file.Filename, condition) {
+		resp.OriginalError = fmt.Errorf("file type of %s is not allowed", file.Filename)
+		return resp
+	}
+	if s.ExceedFileSizeLimit(file.Size, condition) {
+		resp.OriginalError = fmt.Errorf("file %s exceeds the size limit", file.Filename)
+		return resp
 	}
-}
 
-func (s *{{plugin_display_name}}) UploadFile(ctx *plugin.GinContext, condition plugin.UploadFileCondition) (resp plugin.UploadFileResponse) {
 	// TODO: Implement file upload logic
 	// This is a Hello World example - implement your storage logic here
-	resp = plugin.UploadFileResponse{
-		FullURL: "https://example.com/hello-world.jpg",
-	}
+	resp.FullURL = "https://example.com/hello-world" + strings.ToLower(filepath.Ext(file.Filename))
 	return resp
 }
 
@@ -75,13 +63,22 @@ func (s *{{plugin_display_name}}) DeleteFile(ctx *plugin.GinContext, filePath st
 	return nil
 }
 
+// IsUnsupportedFileType checks the extension against the ones the site allows for the upload,
+// attachments have their own list
 func (s *{{plugin_display_name}}) IsUnsupportedFileType(filename string, condition plugin.UploadFileCondition) bool {
-	// TODO: Implement file type validation
-	return false
+	allowed := condition.AuthorizedImageExtensions
+	if condition.Source == plugin.UserPostAttachment {
+		allowed = condition.AuthorizedAttachmentExtensions
+	}
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	return ext == "" || !slices.Contains(allowed, ext)
 }
 
+// ExceedFileSizeLimit checks the size against the site's limit for the upload, in megabytes
 func (s *{{plugin_display_name}}) ExceedFileSizeLimit(fileSize int64, condition plugin.UploadFileCondition) bool {
-	// TODO: Implement file size validation
-	return false
+	limit := condition.MaxImageSize
+	if condition.Source == plugin.UserPostAttachment {
+		limit = condition.MaxAttachmentSize
+	}
+	return limit > 0 && fileSize > int64(limit)*1024*1024
 }
-
@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"{{testkit_module}}"
+	"github.com/apache/answer/plugin"
+)
+
+// TestStorageContract runs the storage contract against the plugin
+func TestStorageContract(t *testing.T) {
+	testStorageContract(t, func(t *testing.T) fileChecker {
+		return newPlugin()
+	})
+}
+
+// fileChecker is the part of plugin.Storage the contract covers, deleting
+// needs a bucket and is left to your own tests
+type fileChecker interface {
+	UploadFile(ctx *plugin.GinContext, condition plugin.UploadFileCondition) (resp plugin.UploadFileResponse)
+	IsUnsupportedFileType(filename string, condition plugin.UploadFileCondition) bool
+	ExceedFileSizeLimit(fileSize int64, condition plugin.UploadFileCondition) bool
+}
+
+// testStorageContract checks that a plugin.Storage applies the site's upload
+// settings, which Answer passes in the condition
+func testStorageContract(t *testing.T, newStorage func(t *testing.T) fileChecker) {
+	const megabyte = 1024 * 1024
+	image := plugin.UploadFileCondition{
+		Source:                         plugin.UserPost,
+		MaxImageSize:                   4,
+		MaxAttachmentSize:              8,
+		AuthorizedImageExtensions:      []string{"jpg", "png"},
+		AuthorizedAttachmentExtensions: []string{"pdf", "zip"},
+	}
+	attachment := image
+	attachment.Source = plugin.UserPostAttachment
+
+	t.Run("file types", func(t *testing.T) {
+		s := newStorage(t)
+		cases := []struct {
+			filename    string
+			condition   plugin.UploadFileCondition
+			unsupported bool
+		}{
+			{"photo.jpg", image, false},
+			{"PHOTO.PNG", image, false},
+			{"archive.tar.zip", attachment, false},
+			{"manual.pdf", image, true},
+			{"photo.jpg", attachment, true},
+			{"script.jpg.exe", image, true},
+			{"jpg", image, true},
+			{"README", attachment, true},
+		}
+		for _, c := range cases {
+			if got := s.IsUnsupportedFileType(c.filename, c.condition); got != c.unsupported {
+				t.Errorf("IsUnsupportedFileType(%q, %s) = %v, want %v", c.filename, c.condition.Source, got, c.unsupported)
+			}
+		}
+	})
+
+	t.Run("size limits", func(t *testing.T) {
+		s := newStorage(t)
+		cases := []struct {
+			size      int64
+			condition plugin.UploadFileCondition
+			exceeds   bool
+		}{
+			{4 * megabyte, image, false},
+			{4*megabyte + 1, image, true},
+			{8 * megabyte, attachment, false},
+			{8*megabyte + 1, attachment, true},
+		}
+		for _, c := range cases {
+			if got := s.ExceedFileSizeLimit(c.size, c.condition); got != c.exceeds {
+				t.Errorf("ExceedFileSizeLimit(%d, %s) = %v, want %v", c.size, c.condition.Source, got, c.exceeds)
+			}
+		}
+	})
+	t.Run("upload", func(t *testing.T) {
+		s := newStorage(t)
+		upload := func(files ...testkit.File) plugin.UploadFileResponse {
+			req := testkit.NewRequest(t, http.MethodPost, testkit.AuthUserPrefix+"/file", testkit.WithMultipart(
+				map[string]string{"source": string(image.Source)}, files...))
+			ctx, _ := testkit.NewGinContext(t, req)
+			return s.UploadFile(ctx, image)
+		}
+
+		resp := upload(testkit.File{Field: "file", Name: "photo.jpg", ContentType: "image/jpeg", Content: []byte("jpeg")})
+		if resp.OriginalError != nil {
+			t.Fatalf("UploadFile(photo.jpg): %v", resp.OriginalError)
+		}
+		if u, err := url.Parse(resp.FullURL); err != nil || !u.IsAbs() {
+			t.Errorf("UploadFile(photo.jpg) FullURL = %q, want an absolute URL", resp.FullURL)
+		}
+
+		rejected := map[string]testkit.File{
+			"unsupported type": {Field: "file", Name: "manual.pdf", Content: []byte("pdf")},
+			"too large":        {Field: "file", Name: "photo.png", Content: bytes.Repeat([]byte{0}, 4*megabyte+1)},
+			"other field":      {Field: "image", Name: "photo.jpg", Content: []byte("jpeg")},
+		}
+		for name, file := range rejected {
+			if resp := upload(file); resp.OriginalError == nil || resp.FullURL != "" {
+				t.Errorf("UploadFile(%s) = %+v, want an error and no URL", name, resp)
+			}
+		}
+	})
+}
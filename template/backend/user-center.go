@@ -20,44 +20,39 @@
 package {{package_name}}
 
 import (
-	"embed"
 	"fmt"
-	"github.com/apache/answer-plugins/{{plugin_name}}/i18n"
-	"github.com/apache/answer-plugins/util"
+
 	"github.com/apache/answer/plugin"
 	"github.com/gin-gonic/gin"
 )
 
-//go:embed info.yaml
-var Info embed.FS
-
-type {{plugin_display_name}} struct {
-	Config *{{plugin_display_name}}Config
-}
-
-type {{plugin_display_name}}Config struct {
+//section:config
 	Endpoint string `json:"endpoint"`
 	APIKey   string `json:"api_key"`
-}
 
-func init() {
-	plugin.Register(&{{plugin_display_name}}{
-		Config: &{{plugin_display_name}}Config{},
-	})
+//section:body
+var _ plugin.UserCenter = (*{{plugin_display_name}})(nil)
+
+// Description tells Answer how to show the user center and which parts of
+// Answer's own user system it takes over
+func (uc *{{plugin_display_name}}) Description() plugin.UserCenterDesc {
+	// TODO: Point the redirects at your user center's pages
+	return plugin.UserCenterDesc{
+		Name:              "{{plugin_slug_name}}",
+		DisplayName:       plugin.MakeTranslator(i18n.InfoName),
+		Url:               uc.Config.Endpoint,
+		LoginRedirectURL:  uc.Config.Endpoint + "/login",
+		SignUpRedirectURL: uc.Config.Endpoint + "/signup",
+		// Keep Answer's own login until the user center works, so
+		// administrators aren't locked out
+		EnabledOriginalUserSystem: true,
+	}
 }
 
-func (u *{{plugin_display_name}}) Info() plugin.Info {
-	info := &util.Info{}
-	info.GetInfo(Info)
-
-	return plugin.Info{
-		Name:        plugin.MakeTranslator(i18n.InfoName),
-		SlugName:    info.SlugName,
-		Description: plugin.MakeTranslator(i18n.InfoDescription),
-		Author:      info.Author,
-		Version:     info.Version,
-		Link:        info.Link,
-	}
+// ControlCenterItems returns the links Answer adds to the user's menu
+func (uc *{{plugin_display_name}}) ControlCenterItems() []plugin.ControlCenter {
+	// TODO: Link the pages of your user center users should find from Answer
+	return nil
 }
 
 func (uc *{{plugin_display_name}}) LoginCallback(ctx *plugin.GinContext) (userInfo *plugin.UserCenterBasicUserInfo, err error) {
@@ -99,11 +94,22 @@ func (uc *{{plugin_display_name}}) UserInfo(externalID string) (userInfo *plugin
 	}, nil
 }
 
+// UserList returns the users of externalIDs in the same order, leaving out the unknown ones
 func (uc *{{plugin_display_name}}) UserList(externalIDs []string) (userList []*plugin.UserCenterBasicUserInfo, err error) {
-	// TODO: Implement user list retrieval logic
+	// TODO: Implement user list retrieval logic, with a single request if your user center allows
 	// This is a Hello World example - implement your user center logic here
 	fmt.Printf("UserCenter: Get user list for %d users\n", len(externalIDs))
-	return []*plugin.UserCenterBasicUserInfo{}, nil
+	userList = make([]*plugin.UserCenterBasicUserInfo, 0, len(externalIDs))
+	for _, externalID := range externalIDs {
+		userInfo, err := uc.UserInfo(externalID)
+		if err != nil {
+			return nil, err
+		}
+		if userInfo != nil {
+			userList = append(userList, userInfo)
+		}
+	}
+	return userList, nil
 }
 
 func (uc *{{plugin_display_name}}) UserStatus(externalID string) (userStatus plugin.UserStatus) {
@@ -112,6 +118,22 @@ func (uc *{{plugin_display_name}}) UserStatus(externalID string) (userStatus plu
 	return plugin.UserStatusAvailable
 }
 
+// UserSettings returns where Answer sends the user to edit their profile and
+// account, which the user center owns
+func (uc *{{plugin_display_name}}) UserSettings(externalID string) (userSettings *plugin.SettingInfo, err error) {
+	// TODO: Point the settings at your user center's pages
+	return &plugin.SettingInfo{
+		ProfileSettingRedirectURL: uc.Config.Endpoint + "/profile",
+		AccountSettingRedirectURL: uc.Config.Endpoint + "/account",
+	}, nil
+}
+
+// PersonalBranding returns the links shown on the user's profile, e.g. their site
+func (uc *{{plugin_display_name}}) PersonalBranding(externalID string) (branding []*plugin.PersonalBranding) {
+	// TODO: Implement personal branding, e.g. from the user's profile in the user center
+	return nil
+}
+
 func (uc *{{plugin_display_name}}) AfterLogin(externalID, accessToken string) {
 	// TODO: Implement after login logic
 	// This is a Hello World example - implement your user center logic here
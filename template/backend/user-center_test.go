@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+// TestUserCenterContract runs the user center contract against the plugin.
+// knownIDs are users your user center has, point the plugin at a test
+// directory that holds them.
+func TestUserCenterContract(t *testing.T) {
+	knownIDs := []string{"user-1", "user-2", "user-3"}
+	testUserCenterContract(t, func(t *testing.T) plugin.UserCenter {
+		return newPlugin()
+	}, knownIDs)
+}
+
+// testUserCenterContract checks the behaviour Answer relies on from a
+// plugin.UserCenter that knows the users of knownIDs, at least three.
+func testUserCenterContract(t *testing.T, newDirectory func(t *testing.T) plugin.UserCenter, knownIDs []string) {
+	if len(knownIDs) < 3 {
+		t.Fatal("the user center contract needs at least three known users")
+	}
+	externalIDs := func(users []*plugin.UserCenterBasicUserInfo) []string {
+		ids := make([]string, 0, len(users))
+		for _, user := range users {
+			ids = append(ids, user.ExternalID)
+		}
+		return ids
+	}
+
+	t.Run("user info", func(t *testing.T) {
+		d := newDirectory(t)
+		for _, id := range knownIDs {
+			user, err := d.UserInfo(id)
+			if err != nil {
+				t.Fatalf("UserInfo(%s): %v", id, err)
+			}
+			if user == nil || user.ExternalID != id {
+				t.Errorf("UserInfo(%s) = %+v, want the user with that external ID", id, user)
+			}
+		}
+	})
+
+	t.Run("user list preserves order", func(t *testing.T) {
+		d := newDirectory(t)
+		reversed := slices.Clone(knownIDs)
+		slices.Reverse(reversed)
+		for _, ids := range [][]string{knownIDs, reversed} {
+			users, err := d.UserList(ids)
+			if err != nil {
+				t.Fatalf("UserList(%q): %v", ids, err)
+			}
+			if got := externalIDs(users); !slices.Equal(got, ids) {
+				t.Errorf("UserList(%q) returned %q, want the same users in the same order", ids, got)
+			}
+		}
+	})
+
+	t.Run("user list with unknown users", func(t *testing.T) {
+		d := newDirectory(t)
+		ids := []string{knownIDs[1], "contract-unknown-user", knownIDs[0]}
+		users, err := d.UserList(ids)
+		if err != nil {
+			t.Fatalf("UserList(%q): %v", ids, err)
+		}
+		// Unknown users may be left out, the others keep their order
+		got := externalIDs(users)
+		if want := []string{knownIDs[1], knownIDs[0]}; !slices.Equal(got, ids) && !slices.Equal(got, want) {
+			t.Errorf("UserList(%q) returned %q, want %q or %q", ids, got, ids, want)
+		}
+	})
+
+	t.Run("empty user list", func(t *testing.T) {
+		d := newDirectory(t)
+		users, err := d.UserList(nil)
+		if err != nil || len(users) != 0 {
+			t.Errorf("UserList(nil) = %d users, %v, want none", len(users), err)
+		}
+	})
+
+	t.Run("user status", func(t *testing.T) {
+		d := newDirectory(t)
+		valid := []plugin.UserStatus{plugin.UserStatusAvailable, plugin.UserStatusSuspended, plugin.UserStatusDeleted}
+		for _, id := range knownIDs {
+			if status := d.UserStatus(id); !slices.Contains(valid, status) {
+				t.Errorf("UserStatus(%s) = %d, want one of %v", id, status, valid)
+			}
+		}
+	})
+
+	t.Run("description", func(t *testing.T) {
+		d := newDirectory(t)
+		desc := d.Description()
+		if desc.Name == "" {
+			t.Error("Description().Name is empty, Answer identifies the user center by it")
+		}
+		for _, item := range d.ControlCenterItems() {
+			if item.Name == "" || item.Url == "" {
+				t.Errorf("control center item %+v, want a name and a URL", item)
+			}
+		}
+	})
+
+	t.Run("user settings", func(t *testing.T) {
+		d := newDirectory(t)
+		for _, id := range knownIDs {
+			if _, err := d.UserSettings(id); err != nil {
+				t.Errorf("UserSettings(%s): %v", id, err)
+			}
+			for _, branding := range d.PersonalBranding(id) {
+				if branding == nil || branding.Url == "" {
+					t.Errorf("PersonalBranding(%s) has %+v, want a URL", id, branding)
+				}
+			}
+		}
+	})
+}
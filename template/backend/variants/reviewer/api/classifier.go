@@ -0,0 +1,163 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/apache/answer/plugin"
+)
+
+// Classifier scores content per moderation category, scores are between 0 and 1
+type Classifier interface {
+	Classify(ctx context.Context, content *plugin.ReviewContent) (scores map[string]float64, err error)
+}
+
+// NewClassifier builds the classifier for the configured provider
+func NewClassifier(config *{{plugin_display_name}}Config, client *http.Client) (Classifier, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+	switch config.Provider {
+	case ProviderOpenAI, "":
+		return &OpenAIClassifier{client: client, endpoint: config.Endpoint, apiKey: config.APIKey, model: config.Model}, nil
+	case ProviderGeneric:
+		return &GenericClassifier{client: client, endpoint: config.Endpoint, apiKey: config.APIKey}, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q", config.Provider)
+}
+
+// OpenAIClassifier calls an OpenAI-compatible /v1/moderations endpoint
+type OpenAIClassifier struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	model    string
+}
+
+type openAIModerationRequest struct {
+	Model string `json:"model,omitempty"`
+	Input string `json:"input"`
+}
+
+type openAIModerationResponse struct {
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
+
+func (c *OpenAIClassifier) Classify(ctx context.Context, content *plugin.ReviewContent) (map[string]float64, error) {
+	req := openAIModerationRequest{Model: c.model, Input: joinContent(content)}
+	resp := &openAIModerationResponse{}
+	if err := postJSON(ctx, c.client, c.endpoint, c.apiKey, req, resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, fmt.Errorf("moderation response has no results")
+	}
+
+	// Several results come back for several inputs, keep the highest score per category
+	scores := map[string]float64{}
+	for _, result := range resp.Results {
+		for category, score := range result.CategoryScores {
+			if score > scores[category] {
+				scores[category] = score
+			}
+		}
+	}
+	return scores, nil
+}
+
+// GenericClassifier posts the review content as JSON and expects {"scores": {"category": 0.5}}
+type GenericClassifier struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+}
+
+type genericRequest struct {
+	Title      string   `json:"title"`
+	Content    string   `json:"content"`
+	Tags       []string `json:"tags"`
+	ObjectType string   `json:"object_type"`
+}
+
+type genericResponse struct {
+	Scores map[string]float64 `json:"scores"`
+}
+
+func (c *GenericClassifier) Classify(ctx context.Context, content *plugin.ReviewContent) (map[string]float64, error) {
+	req := genericRequest{
+		Title:      content.Title,
+		Content:    content.Content,
+		Tags:       content.Tags,
+		ObjectType: content.ObjectType,
+	}
+	resp := &genericResponse{}
+	if err := postJSON(ctx, c.client, c.endpoint, c.apiKey, req, resp); err != nil {
+		return nil, err
+	}
+	if resp.Scores == nil {
+		return nil, fmt.Errorf("classification response has no scores")
+	}
+	return resp.Scores, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, endpoint, apiKey string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("moderation API returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func joinContent(content *plugin.ReviewContent) string {
+	parts := []string{content.Title, content.Content}
+	if len(content.Tags) > 0 {
+		parts = append(parts, strings.Join(content.Tags, ", "))
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n\n"))
+}
@@ -0,0 +1,200 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+// newTestReviewer configures the reviewer against a stand-in moderation API
+func newTestReviewer(t *testing.T, handler http.HandlerFunc, config map[string]any) (*{{plugin_display_name}}, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	config["endpoint"] = server.URL
+	if _, ok := config["thresholds"]; !ok {
+		config["thresholds"] = "\"*\": {review: 0.5, reject: 0.9}\nspam: {review: 0.3}\n"
+	}
+	data, _ := json.Marshal(config)
+
+	r := &{{plugin_display_name}}{Config: &{{plugin_display_name}}Config{}}
+	if err := r.ConfigReceiver(data); err != nil {
+		t.Fatalf("ConfigReceiver: %v", err)
+	}
+	return r, &calls
+}
+
+func openAIScores(scores map[string]float64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := openAIModerationRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Input == "" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		resp := map[string]any{
+			"results": []map[string]any{{"flagged": false, "category_scores": scores}},
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestReviewOpenAIThresholds(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores map[string]float64
+		status plugin.ReviewStatus
+	}{
+		{"clean", map[string]float64{"hate": 0.01, "spam": 0.1}, plugin.ReviewStatusApproved},
+		{"category threshold", map[string]float64{"hate": 0.01, "spam": 0.35}, plugin.ReviewStatusNeedReview},
+		{"wildcard review", map[string]float64{"hate": 0.6}, plugin.ReviewStatusNeedReview},
+		{"wildcard reject", map[string]float64{"hate": 0.95}, plugin.ReviewStatusDeleteDirectly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestReviewer(t, openAIScores(tt.scores), map[string]any{
+				"provider": ProviderOpenAI,
+				"api_key":  "test-key",
+			})
+			result := r.Review(&plugin.ReviewContent{Title: "title", Content: tt.name})
+			if result.ReviewStatus != tt.status {
+				t.Fatalf("status = %s, want %s (%s)", result.ReviewStatus, tt.status, result.Reason)
+			}
+			if result.Approved != (tt.status == plugin.ReviewStatusApproved) {
+				t.Fatalf("approved = %v for status %s", result.Approved, result.ReviewStatus)
+			}
+		})
+	}
+}
+
+func TestReviewGenericProvider(t *testing.T) {
+	r, _ := newTestReviewer(t, func(w http.ResponseWriter, r *http.Request) {
+		req := genericRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		score := 0.0
+		if len(req.Tags) == 1 && req.Tags[0] == "casino" {
+			score = 0.99
+		}
+		_ = json.NewEncoder(w).Encode(genericResponse{Scores: map[string]float64{"spam": score}})
+	}, map[string]any{"provider": ProviderGeneric})
+
+	result := r.Review(&plugin.ReviewContent{Title: "hi", Content: "hello", Tags: []string{"casino"}})
+	if result.ReviewStatus != plugin.ReviewStatusNeedReview {
+		t.Fatalf("status = %s, want %s (%s)", result.ReviewStatus, plugin.ReviewStatusNeedReview, result.Reason)
+	}
+}
+
+func TestReviewFailurePolicy(t *testing.T) {
+	tests := []struct {
+		policy   string
+		status   plugin.ReviewStatus
+		approved bool
+	}{
+		{FailOpen, plugin.ReviewStatusApproved, true},
+		{FailClosed, plugin.ReviewStatusNeedReview, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			// The stand-in API hangs until the test is over
+			release := make(chan struct{})
+			defer close(release)
+			slow := func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}
+			r, _ := newTestReviewer(t, slow, map[string]any{
+				"provider":        ProviderGeneric,
+				"timeout_seconds": "0.05",
+				"failure_policy":  tt.policy,
+			})
+			start := time.Now()
+			result := r.Review(&plugin.ReviewContent{Content: "slow"})
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("timeout not enforced, took %s", elapsed)
+			}
+			if result.ReviewStatus != tt.status || result.Approved != tt.approved {
+				t.Fatalf("got %s/%v, want %s/%v (%s)", result.ReviewStatus, result.Approved, tt.status, tt.approved, result.Reason)
+			}
+		})
+	}
+}
+
+func TestReviewCachesVerdicts(t *testing.T) {
+	r, calls := newTestReviewer(t, openAIScores(map[string]float64{"hate": 0.6}), map[string]any{
+		"provider": ProviderOpenAI,
+		"api_key":  "test-key",
+	})
+
+	content := &plugin.ReviewContent{Title: "same", Content: "content"}
+	first := r.Review(content)
+	second := r.Review(&plugin.ReviewContent{Title: "same", Content: "content"})
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("API called %d times, want 1", got)
+	}
+	if first.ReviewStatus != second.ReviewStatus || first.Reason != second.Reason {
+		t.Fatalf("cached verdict differs: %+v vs %+v", first, second)
+	}
+
+	r.Review(&plugin.ReviewContent{Title: "same", Content: "edited"})
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("API called %d times after content change, want 2", got)
+	}
+}
+
+func TestReviewDoesNotCacheFailures(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	r, calls := newTestReviewer(t, func(w http.ResponseWriter, req *http.Request) {
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(genericResponse{Scores: map[string]float64{"spam": 0}})
+	}, map[string]any{"provider": ProviderGeneric, "failure_policy": FailClosed})
+
+	content := &plugin.ReviewContent{Content: "retry me"}
+	if result := r.Review(content); result.ReviewStatus != plugin.ReviewStatusNeedReview {
+		t.Fatalf("status = %s, want %s", result.ReviewStatus, plugin.ReviewStatusNeedReview)
+	}
+	fail.Store(false)
+	if result := r.Review(content); !result.Approved {
+		t.Fatalf("expected approval once the API recovers, got %s", result.Reason)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("API called %d times, want 2", got)
+	}
+}
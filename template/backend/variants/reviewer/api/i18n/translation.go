@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package i18n
+
+const (
+	InfoName                       = "plugin.{{info_slug_name}}.backend.info.name"
+	InfoDescription                = "plugin.{{info_slug_name}}.backend.info.description"
+	ConfigProviderTitle            = "plugin.{{info_slug_name}}.backend.config.provider.title"
+	ConfigProviderOpenAI           = "plugin.{{info_slug_name}}.backend.config.provider.options.openai"
+	ConfigProviderGeneric          = "plugin.{{info_slug_name}}.backend.config.provider.options.generic"
+	ConfigEndpointTitle            = "plugin.{{info_slug_name}}.backend.config.endpoint.title"
+	ConfigEndpointDescription      = "plugin.{{info_slug_name}}.backend.config.endpoint.description"
+	ConfigAPIKeyTitle              = "plugin.{{info_slug_name}}.backend.config.api_key.title"
+	ConfigModelTitle               = "plugin.{{info_slug_name}}.backend.config.model.title"
+	ConfigModelDescription         = "plugin.{{info_slug_name}}.backend.config.model.description"
+	ConfigThresholdsTitle          = "plugin.{{info_slug_name}}.backend.config.thresholds.title"
+	ConfigThresholdsDescription    = "plugin.{{info_slug_name}}.backend.config.thresholds.description"
+	ConfigTimeoutTitle             = "plugin.{{info_slug_name}}.backend.config.timeout_seconds.title"
+	ConfigFailurePolicyTitle       = "plugin.{{info_slug_name}}.backend.config.failure_policy.title"
+	ConfigFailurePolicyDescription = "plugin.{{info_slug_name}}.backend.config.failure_policy.description"
+	ConfigFailurePolicyOpen        = "plugin.{{info_slug_name}}.backend.config.failure_policy.options.open"
+	ConfigFailurePolicyClosed      = "plugin.{{info_slug_name}}.backend.config.failure_policy.options.closed"
+	ConfigCacheTTLTitle            = "plugin.{{info_slug_name}}.backend.config.cache_ttl_minutes.title"
+)
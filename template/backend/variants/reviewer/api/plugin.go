@@ -0,0 +1,341 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+//section:fields
+
+	mu         sync.RWMutex
+	classifier Classifier
+	thresholds Thresholds
+	cache      *VerdictCache
+	audit      *AuditLog
+
+//section:config
+	Provider   string `json:"provider"`
+	Endpoint   string `json:"endpoint"`
+	APIKey     string `json:"api_key"`
+	Model      string `json:"model"`
+	Thresholds string `json:"thresholds"`
+	// TimeoutSeconds bounds each classification call
+	TimeoutSeconds json.Number `json:"timeout_seconds"`
+	// FailurePolicy decides what happens when the API errors or times out
+	FailurePolicy string `json:"failure_policy"`
+	// CacheTTLMinutes is how long verdicts are cached by content hash, 0 disables the cache
+	CacheTTLMinutes json.Number `json:"cache_ttl_minutes"`
+}
+
+// Thresholds maps a category to its score limits, "*" applies to categories without an entry
+type Thresholds map[string]Threshold
+
+// Threshold sends content to the review queue at Review and rejects it at Reject, zero disables a limit
+type Threshold struct {
+	Review float64 `yaml:"review"`
+	Reject float64 `yaml:"reject"`
+
+//section:setup
+	thresholds, err := ParseThresholds(defaultThresholds)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default thresholds: %v", err))
+	}
+
+//section:defaults
+			Provider:      ProviderOpenAI,
+			Model:         "omni-moderation-latest",
+			Thresholds:    defaultThresholds,
+			FailurePolicy: FailOpen,
+
+//section:init
+		thresholds: thresholds,
+		cache:      NewVerdictCache(defaultCacheTTL, defaultCacheSize),
+		audit:      NewAuditLog(),
+
+//section:body
+const (
+	ProviderOpenAI  = "openai"
+	ProviderGeneric = "generic"
+
+	FailOpen   = "open"
+	FailClosed = "closed"
+
+	// RuleAPIFailure is the audit rule of results decided by the failure policy
+	RuleAPIFailure = "api-failure"
+
+	defaultTimeout    = 5 * time.Second
+	defaultCacheTTL   = 24 * time.Hour
+	defaultCacheSize  = 10000
+	defaultThresholds = "\"*\":\n  review: 0.5\n  reject: 0.9\n"
+)
+
+func (r *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:     "provider",
+			Type:     plugin.ConfigTypeSelect,
+			Title:    plugin.MakeTranslator(i18n.ConfigProviderTitle),
+			Required: true,
+			Value:    r.Config.Provider,
+			Options: []plugin.ConfigFieldOption{
+				{Value: ProviderOpenAI, Label: plugin.MakeTranslator(i18n.ConfigProviderOpenAI)},
+				{Value: ProviderGeneric, Label: plugin.MakeTranslator(i18n.ConfigProviderGeneric)},
+			},
+		},
+		{
+			Name:        "endpoint",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigEndpointTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigEndpointDescription),
+			Required:    true,
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeUrl},
+			Value:       r.Config.Endpoint,
+		},
+		{
+			Name:      "api_key",
+			Type:      plugin.ConfigTypeInput,
+			Title:     plugin.MakeTranslator(i18n.ConfigAPIKeyTitle),
+			UIOptions: plugin.ConfigFieldUIOptions{InputType: plugin.InputTypePassword},
+			Value:     r.Config.APIKey,
+		},
+		{
+			Name:        "model",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigModelTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigModelDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeText},
+			Value:       r.Config.Model,
+		},
+		{
+			Name:        "thresholds",
+			Type:        plugin.ConfigTypeTextarea,
+			Title:       plugin.MakeTranslator(i18n.ConfigThresholdsTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigThresholdsDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{Rows: "8"},
+			Value:       r.Config.Thresholds,
+		},
+		{
+			Name:      "timeout_seconds",
+			Type:      plugin.ConfigTypeInput,
+			Title:     plugin.MakeTranslator(i18n.ConfigTimeoutTitle),
+			UIOptions: plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeNumber},
+			Value:     r.Config.TimeoutSeconds.String(),
+		},
+		{
+			Name:        "failure_policy",
+			Type:        plugin.ConfigTypeSelect,
+			Title:       plugin.MakeTranslator(i18n.ConfigFailurePolicyTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigFailurePolicyDescription),
+			Value:       r.Config.FailurePolicy,
+			Options: []plugin.ConfigFieldOption{
+				{Value: FailOpen, Label: plugin.MakeTranslator(i18n.ConfigFailurePolicyOpen)},
+				{Value: FailClosed, Label: plugin.MakeTranslator(i18n.ConfigFailurePolicyClosed)},
+			},
+		},
+		{
+			Name:      "cache_ttl_minutes",
+			Type:      plugin.ConfigTypeInput,
+			Title:     plugin.MakeTranslator(i18n.ConfigCacheTTLTitle),
+			UIOptions: plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeNumber},
+			Value:     r.Config.CacheTTLMinutes.String(),
+		},
+	}
+}
+
+func (r *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+
+	thresholds, err := ParseThresholds(c.Thresholds)
+	if err != nil {
+		return err
+	}
+	classifier, err := NewClassifier(c, &http.Client{})
+	if err != nil {
+		return err
+	}
+	cacheTTL := defaultCacheTTL
+	if c.CacheTTLMinutes != "" {
+		minutes, err := c.CacheTTLMinutes.Int64()
+		if err != nil || minutes < 0 {
+			return fmt.Errorf("invalid cache_ttl_minutes %q", c.CacheTTLMinutes)
+		}
+		cacheTTL = time.Duration(minutes) * time.Minute
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Config = c
+	r.classifier = classifier
+	r.thresholds = thresholds
+	r.cache = NewVerdictCache(cacheTTL, defaultCacheSize)
+	return nil
+}
+
+// Review asks the classification API for category scores and applies the thresholds.
+// Every decision is recorded in the audit log.
+func (r *{{plugin_display_name}}) Review(content *plugin.ReviewContent) (result *plugin.ReviewResult) {
+	start := time.Now()
+	result, rule := r.review(content)
+	r.audit.Record(content, result, rule, start)
+	return result
+}
+
+// review returns the result and the categories that decided it
+func (r *{{plugin_display_name}}) review(content *plugin.ReviewContent) (result *plugin.ReviewResult, rule string) {
+	r.mu.RLock()
+	classifier, thresholds, cache, config := r.classifier, r.thresholds, r.cache, r.Config
+	r.mu.RUnlock()
+
+	if classifier == nil {
+		return r.onFailure(config, fmt.Errorf("moderation API is not configured")), RuleAPIFailure
+	}
+
+	key := ContentHash(content)
+	if cached, rule, ok := cache.Get(key); ok {
+		return cached, rule
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout(config))
+	defer cancel()
+	scores, err := classifier.Classify(ctx, content)
+	if err != nil {
+		return r.onFailure(config, err), RuleAPIFailure
+	}
+
+	result, rule = thresholds.Apply(scores)
+	cache.Set(key, result, rule)
+	return result, rule
+}
+
+// onFailure applies the failure policy, failures are never cached
+func (r *{{plugin_display_name}}) onFailure(config *{{plugin_display_name}}Config, err error) *plugin.ReviewResult {
+	if config.FailurePolicy == FailClosed {
+		return &plugin.ReviewResult{
+			ReviewStatus: plugin.ReviewStatusNeedReview,
+			Reason:       fmt.Sprintf("moderation API failed, sent to review (fail-closed): %v", err),
+		}
+	}
+	return &plugin.ReviewResult{
+		Approved:     true,
+		ReviewStatus: plugin.ReviewStatusApproved,
+		Reason:       fmt.Sprintf("moderation API failed, approved (fail-open): %v", err),
+	}
+}
+
+func (r *{{plugin_display_name}}) timeout(config *{{plugin_display_name}}Config) time.Duration {
+	seconds, err := config.TimeoutSeconds.Float64()
+	if err != nil || seconds <= 0 {
+		return defaultTimeout
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// ParseThresholds parses the YAML thresholds config
+func ParseThresholds(data string) (Thresholds, error) {
+	thresholds := Thresholds{}
+	if err := yaml.Unmarshal([]byte(data), &thresholds); err != nil {
+		return nil, fmt.Errorf("parse thresholds: %w", err)
+	}
+	for category, t := range thresholds {
+		if t.Review < 0 || t.Review > 1 || t.Reject < 0 || t.Reject > 1 {
+			return nil, fmt.Errorf("threshold %q: scores must be between 0 and 1", category)
+		}
+	}
+	return thresholds, nil
+}
+
+// Apply turns category scores into a review result, the most severe category wins.
+// The rule lists the categories over their threshold.
+func (t Thresholds) Apply(scores map[string]float64) (result *plugin.ReviewResult, rule string) {
+	categories := make([]string, 0, len(scores))
+	for category := range scores {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var rejected, reviewed, rejectedCategories, reviewedCategories []string
+	for _, category := range categories {
+		threshold, ok := t[category]
+		if !ok {
+			threshold, ok = t["*"]
+		}
+		if !ok {
+			continue
+		}
+		score := scores[category]
+		hit := fmt.Sprintf("%s=%.2f", category, score)
+		switch {
+		case threshold.Reject > 0 && score >= threshold.Reject:
+			rejected = append(rejected, hit)
+			rejectedCategories = append(rejectedCategories, category)
+		case threshold.Review > 0 && score >= threshold.Review:
+			reviewed = append(reviewed, hit)
+			reviewedCategories = append(reviewedCategories, category)
+		}
+	}
+
+	switch {
+	case len(rejected) > 0:
+		return &plugin.ReviewResult{
+			ReviewStatus: plugin.ReviewStatusDeleteDirectly,
+			Reason:       "rejected by moderation API: " + strings.Join(rejected, ", "),
+		}, strings.Join(rejectedCategories, ",")
+	case len(reviewed) > 0:
+		return &plugin.ReviewResult{
+			ReviewStatus: plugin.ReviewStatusNeedReview,
+			Reason:       "flagged by moderation API: " + strings.Join(reviewed, ", "),
+		}, strings.Join(reviewedCategories, ",")
+	}
+	return &plugin.ReviewResult{
+		Approved:     true,
+		ReviewStatus: plugin.ReviewStatusApproved,
+		Reason:       "all categories below thresholds",
+	}, ""
+}
+
+// SetOperator receives the KV storage the audit log is written to
+func (r *{{plugin_display_name}}) SetOperator(operator *plugin.KVOperator) {
+	r.audit.SetStore(operator)
+}
+
+func (r *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {}
+
+func (r *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {}
+
+// RegisterAuthAdminRouter exposes the audit log under /{{plugin_slug_name}}/audit,
+// false positives marked there show which thresholds need tuning
+func (r *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {
+	r.audit.RegisterRoutes(router.Group("/{{plugin_slug_name}}/audit"))
+}
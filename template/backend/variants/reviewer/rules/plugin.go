@@ -0,0 +1,132 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+//section:fields
+
+	mu     sync.RWMutex
+	engine *RuleEngine
+	audit  *AuditLog
+
+//section:config
+	Rules string `json:"rules"`
+
+//section:setup
+	engine, err := ParseRules([]byte(defaultRules))
+	if err != nil {
+		panic(fmt.Sprintf("invalid default rules: %v", err))
+	}
+
+//section:defaults
+			Rules: defaultRules,
+
+//section:init
+		engine: engine,
+		audit:  NewAuditLog(),
+
+//section:body
+//go:embed rules.yaml
+var defaultRules string
+
+// ConfigFields exposes the rule set as a YAML textarea in the admin plugin settings
+func (r *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:        "rules",
+			Type:        plugin.ConfigTypeTextarea,
+			Title:       plugin.MakeTranslator(i18n.ConfigRulesTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigRulesDescription),
+			Required:    true,
+			UIOptions: plugin.ConfigFieldUIOptions{
+				Rows: "20",
+			},
+			Value: r.Config.Rules,
+		},
+	}
+}
+
+// ConfigReceiver validates the submitted rules and swaps the engine only when they compile
+func (r *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+
+	engine, err := ParseRules([]byte(c.Rules))
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Config = c
+	r.engine = engine
+	return nil
+}
+
+// Review runs the content through the rule engine, the first matching rule decides.
+// Every decision is recorded in the audit log.
+func (r *{{plugin_display_name}}) Review(content *plugin.ReviewContent) (result *plugin.ReviewResult) {
+	start := time.Now()
+	r.mu.RLock()
+	engine := r.engine
+	r.mu.RUnlock()
+
+	decision := engine.Evaluate(content)
+	result = &plugin.ReviewResult{
+		Reason: decision.Explain(),
+	}
+	switch decision.Action {
+	case ActionApprove:
+		result.Approved = true
+		result.ReviewStatus = plugin.ReviewStatusApproved
+	case ActionReject:
+		result.ReviewStatus = plugin.ReviewStatusDeleteDirectly
+	default:
+		result.ReviewStatus = plugin.ReviewStatusNeedReview
+	}
+	r.audit.Record(content, result, decision.Rule, start)
+	return result
+}
+
+// SetOperator receives the KV storage the audit log is written to
+func (r *{{plugin_display_name}}) SetOperator(operator *plugin.KVOperator) {
+	r.audit.SetStore(operator)
+}
+
+func (r *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {}
+
+func (r *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {}
+
+// RegisterAuthAdminRouter exposes the audit log under /{{plugin_slug_name}}/audit,
+// false positives marked there show which rules need tuning
+func (r *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {
+	r.audit.RegisterRoutes(router.Group("/{{plugin_slug_name}}/audit"))
+}
@@ -0,0 +1,251 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/apache/answer/plugin"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleAction is what happens to the content when a rule fires
+type RuleAction string
+
+const (
+	ActionApprove RuleAction = "approve"
+	ActionReject  RuleAction = "reject"
+	ActionReview  RuleAction = "review"
+)
+
+// Fields a rule can inspect
+const (
+	FieldTitle   = "title"
+	FieldContent = "content"
+	FieldTags    = "tags"
+)
+
+var linkRegexp = regexp.MustCompile(`(?i)\bhttps?://[^\s<>()\[\]"']+`)
+
+// RuleSet is the YAML document stored in the plugin config
+type RuleSet struct {
+	// DefaultAction applies when no rule matches, approve if empty
+	DefaultAction RuleAction `yaml:"default_action"`
+	Rules         []*Rule    `yaml:"rules"`
+}
+
+// Rule matches when every condition it declares matches.
+// Declaring no condition at all is an error.
+type Rule struct {
+	Name   string     `yaml:"name"`
+	Action RuleAction `yaml:"action"`
+	Reason string     `yaml:"reason"`
+	// Fields limits keywords and regexes to some fields, all fields if empty
+	Fields []string `yaml:"fields"`
+
+	// Keywords match case-insensitively, any keyword is enough
+	Keywords []string `yaml:"keywords"`
+	// Regexes use Go RE2 syntax, any regex is enough
+	Regexes []string `yaml:"regexes"`
+	// MaxLinks matches content with more links than allowed
+	MaxLinks *int `yaml:"max_links"`
+	// NewAccount matches authors that have not earned trust yet
+	NewAccount *NewAccountCondition `yaml:"new_account"`
+
+	regexes []*regexp.Regexp
+}
+
+// NewAccountCondition matches authors whose rank and approved posts are both at or below the limits
+type NewAccountCondition struct {
+	MaxRank          int   `yaml:"max_rank"`
+	MaxApprovedPosts int64 `yaml:"max_approved_posts"`
+}
+
+// Decision explains the outcome of a review
+type Decision struct {
+	Action RuleAction
+	// Rule is the name of the rule that fired, empty for the default action
+	Rule   string
+	Reason string
+	// Evidence describes what matched, e.g. the keyword found
+	Evidence []string
+}
+
+// Explain renders the decision as the reason shown in the review queue
+func (d Decision) Explain() string {
+	if d.Rule == "" {
+		return fmt.Sprintf("no rule matched, default action: %s", d.Action)
+	}
+	msg := fmt.Sprintf("rule %q fired (%s)", d.Rule, d.Action)
+	if d.Reason != "" {
+		msg += ": " + d.Reason
+	}
+	if len(d.Evidence) > 0 {
+		msg += " [" + strings.Join(d.Evidence, "; ") + "]"
+	}
+	return msg
+}
+
+// RuleEngine evaluates a compiled rule set
+type RuleEngine struct {
+	defaultAction RuleAction
+	rules         []*Rule
+}
+
+// ParseRules parses and validates a YAML rule set
+func ParseRules(data []byte) (*RuleEngine, error) {
+	set := &RuleSet{}
+	if err := yaml.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("parse rules: %w", err)
+	}
+
+	if set.DefaultAction == "" {
+		set.DefaultAction = ActionApprove
+	}
+	if !validAction(set.DefaultAction) {
+		return nil, fmt.Errorf("invalid default_action %q", set.DefaultAction)
+	}
+
+	names := make(map[string]bool, len(set.Rules))
+	for i, rule := range set.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule #%d: name is required", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("rule %q: duplicate name", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if !validAction(rule.Action) {
+			return nil, fmt.Errorf("rule %q: invalid action %q", rule.Name, rule.Action)
+		}
+		for _, field := range rule.Fields {
+			if field != FieldTitle && field != FieldContent && field != FieldTags {
+				return nil, fmt.Errorf("rule %q: unknown field %q", rule.Name, field)
+			}
+		}
+		if len(rule.Keywords) == 0 && len(rule.Regexes) == 0 && rule.MaxLinks == nil && rule.NewAccount == nil {
+			return nil, fmt.Errorf("rule %q: at least one condition is required", rule.Name)
+		}
+		for _, expr := range rule.Regexes {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid regex %q: %w", rule.Name, expr, err)
+			}
+			rule.regexes = append(rule.regexes, re)
+		}
+	}
+
+	return &RuleEngine{defaultAction: set.DefaultAction, rules: set.Rules}, nil
+}
+
+// Evaluate returns the decision of the first matching rule, or the default action
+func (e *RuleEngine) Evaluate(content *plugin.ReviewContent) Decision {
+	for _, rule := range e.rules {
+		if evidence, ok := rule.match(content); ok {
+			return Decision{
+				Action:   rule.Action,
+				Rule:     rule.Name,
+				Reason:   rule.Reason,
+				Evidence: evidence,
+			}
+		}
+	}
+	return Decision{Action: e.defaultAction}
+}
+
+func (rule *Rule) match(content *plugin.ReviewContent) (evidence []string, ok bool) {
+	text := rule.text(content)
+
+	if len(rule.Keywords) > 0 {
+		lower := strings.ToLower(text)
+		found := ""
+		for _, keyword := range rule.Keywords {
+			if keyword != "" && strings.Contains(lower, strings.ToLower(keyword)) {
+				found = keyword
+				break
+			}
+		}
+		if found == "" {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("keyword %q", found))
+	}
+
+	if len(rule.regexes) > 0 {
+		found := ""
+		for _, re := range rule.regexes {
+			if m := re.FindString(text); m != "" {
+				found = m
+				break
+			}
+		}
+		if found == "" {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("pattern matched %q", found))
+	}
+
+	if rule.MaxLinks != nil {
+		links := len(linkRegexp.FindAllString(content.Title+"\n"+content.Content, -1))
+		if links <= *rule.MaxLinks {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("%d links (max %d)", links, *rule.MaxLinks))
+	}
+
+	if rule.NewAccount != nil {
+		author := content.Author
+		approved := author.ApprovedQuestionAmount + author.ApprovedAnswerAmount
+		if author.Rank > rule.NewAccount.MaxRank || approved > rule.NewAccount.MaxApprovedPosts {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("new account (rank %d, %d approved posts)", author.Rank, approved))
+	}
+
+	return evidence, true
+}
+
+// text joins the fields the rule inspects
+func (rule *Rule) text(content *plugin.ReviewContent) string {
+	fields := rule.Fields
+	if len(fields) == 0 {
+		fields = []string{FieldTitle, FieldContent, FieldTags}
+	}
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		switch field {
+		case FieldTitle:
+			parts = append(parts, content.Title)
+		case FieldContent:
+			parts = append(parts, content.Content)
+		case FieldTags:
+			parts = append(parts, strings.Join(content.Tags, " "))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func validAction(action RuleAction) bool {
+	return action == ActionApprove || action == ActionReject || action == ActionReview
+}
@@ -0,0 +1,247 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+func TestParseRulesErrors(t *testing.T) {
+	tests := []struct {
+		name, yaml, want string
+	}{
+		{"invalid YAML", "rules: [", "parse rules"},
+		{"wrong shape", "rules: {name: a}", "parse rules"},
+		{"invalid default action", "default_action: ban", `invalid default_action "ban"`},
+		{"missing name", "rules:\n  - action: reject\n    keywords: [a]", "rule #1: name is required"},
+		{"duplicate name", "rules:\n  - {name: a, action: reject, keywords: [a]}\n  - {name: a, action: review, keywords: [b]}", `rule "a": duplicate name`},
+		{"missing action", "rules:\n  - {name: a, keywords: [a]}", `rule "a": invalid action ""`},
+		{"invalid action", "rules:\n  - {name: a, action: delete, keywords: [a]}", `rule "a": invalid action "delete"`},
+		{"unknown field", "rules:\n  - {name: a, action: reject, fields: [body], keywords: [a]}", `rule "a": unknown field "body"`},
+		{"no condition", "rules:\n  - {name: a, action: reject, fields: [title]}", `rule "a": at least one condition is required`},
+		{"invalid regex", "rules:\n  - {name: a, action: reject, regexes: ['(']}", `rule "a": invalid regex "("`},
+		{"lookahead regex", "rules:\n  - {name: a, action: reject, regexes: ['a(?=b)']}", `rule "a": invalid regex`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseRules = %v, want an error with %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRulesDefaults(t *testing.T) {
+	engine, err := ParseRules([]byte(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := engine.Evaluate(&plugin.ReviewContent{Content: "anything"}); d.Action != ActionApprove || d.Rule != "" {
+		t.Errorf("empty rule set: got %+v, want the approve default", d)
+	}
+
+	if _, err := ParseRules([]byte(defaultRules)); err != nil {
+		t.Errorf("rules.yaml: %v", err)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	engine, err := ParseRules([]byte(`
+default_action: review
+rules:
+  - name: trusted
+    action: approve
+    keywords: [release notes]
+    fields: [title]
+  - name: banned
+    action: reject
+    reason: Contains banned words
+    keywords: [Casino Bonus, ""]
+  - name: phone
+    action: review
+    fields: [content]
+    regexes: ['\+?\d[\d\s-]{9,}\d', 'call me']
+  - name: new-account-links
+    action: review
+    max_links: 1
+    new_account: {max_rank: 1, max_approved_posts: 0}
+  - name: link-farm
+    action: reject
+    max_links: 3
+  - name: tagged-spam
+    action: reject
+    fields: [tags]
+    keywords: [spam]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newcomer := plugin.ReviewContentAuthor{Rank: 1}
+	regular := plugin.ReviewContentAuthor{Rank: 1, ApprovedAnswerAmount: 1}
+	tests := []struct {
+		name     string
+		content  *plugin.ReviewContent
+		action   RuleAction
+		rule     string
+		evidence []string
+	}{
+		{
+			name:    "no rule matches",
+			content: &plugin.ReviewContent{Title: "How do I upgrade?", Content: "Which version should I pick?"},
+			action:  ActionReview,
+		},
+		{
+			name:     "keyword ignores case",
+			content:  &plugin.ReviewContent{Content: "Get your CASINO bonus now"},
+			action:   ActionReject,
+			rule:     "banned",
+			evidence: []string{`keyword "Casino Bonus"`},
+		},
+		{
+			name:     "first matching rule wins",
+			content:  &plugin.ReviewContent{Title: "Release notes", Content: "casino bonus"},
+			action:   ActionApprove,
+			rule:     "trusted",
+			evidence: []string{`keyword "release notes"`},
+		},
+		{
+			name:    "keyword outside the rule's fields",
+			content: &plugin.ReviewContent{Title: "x", Content: "see the release notes"},
+			action:  ActionReview,
+		},
+		{
+			name:     "regex",
+			content:  &plugin.ReviewContent{Content: "Reach me at +1 555-123-4567"},
+			action:   ActionReview,
+			rule:     "phone",
+			evidence: []string{`pattern matched "+1 555-123-4567"`},
+		},
+		{
+			name:     "any regex is enough",
+			content:  &plugin.ReviewContent{Content: "just call me"},
+			action:   ActionReview,
+			rule:     "phone",
+			evidence: []string{`pattern matched "call me"`},
+		},
+		{
+			name:    "regex outside the rule's fields",
+			content: &plugin.ReviewContent{Title: "Call 555-123-4567 today", Content: "x"},
+			action:  ActionReview,
+		},
+		{
+			name:     "links above a new account's limit",
+			content:  &plugin.ReviewContent{Content: "https://a.example and https://b.example", Author: newcomer},
+			action:   ActionReview,
+			rule:     "new-account-links",
+			evidence: []string{"2 links (max 1)", "new account (rank 1, 0 approved posts)"},
+		},
+		{
+			name:    "links at a new account's limit",
+			content: &plugin.ReviewContent{Content: "https://a.example", Author: newcomer},
+			action:  ActionReview,
+		},
+		{
+			name:    "trusted author below the link farm threshold",
+			content: &plugin.ReviewContent{Content: "https://a.example https://b.example https://c.example", Author: regular},
+			action:  ActionReview,
+		},
+		{
+			name:     "trusted author above the link farm threshold",
+			content:  &plugin.ReviewContent{Title: "http://t.example", Content: "https://a.example https://b.example https://c.example", Author: regular},
+			action:   ActionReject,
+			rule:     "link-farm",
+			evidence: []string{"4 links (max 3)"},
+		},
+		{
+			name:     "tags",
+			content:  &plugin.ReviewContent{Title: "x", Tags: []string{"go", "spam"}},
+			action:   ActionReject,
+			rule:     "tagged-spam",
+			evidence: []string{`keyword "spam"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := engine.Evaluate(tt.content)
+			if d.Action != tt.action || d.Rule != tt.rule || !reflect.DeepEqual(d.Evidence, tt.evidence) {
+				t.Errorf("Evaluate = %s by %q %v, want %s by %q %v", d.Action, d.Rule, d.Evidence, tt.action, tt.rule, tt.evidence)
+			}
+		})
+	}
+}
+
+func TestDecisionExplain(t *testing.T) {
+	tests := []struct {
+		decision Decision
+		want     string
+	}{
+		{Decision{Action: ActionApprove}, "no rule matched, default action: approve"},
+		{Decision{Action: ActionReview, Rule: "phone"}, `rule "phone" fired (review)`},
+		{
+			Decision{Action: ActionReject, Rule: "banned", Reason: "Contains banned words", Evidence: []string{`keyword "casino"`, "2 links (max 1)"}},
+			`rule "banned" fired (reject): Contains banned words [keyword "casino"; 2 links (max 1)]`,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.decision.Explain(); got != tt.want {
+			t.Errorf("Explain() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestReviewActions(t *testing.T) {
+	r := newPlugin()
+	tests := []struct {
+		action   RuleAction
+		status   plugin.ReviewStatus
+		approved bool
+	}{
+		{ActionApprove, plugin.ReviewStatusApproved, true},
+		{ActionReject, plugin.ReviewStatusDeleteDirectly, false},
+		{ActionReview, plugin.ReviewStatusNeedReview, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			config := `{"rules": "rules:\n  - {name: all, action: ` + string(tt.action) + `, regexes: ['.']}"}`
+			if err := r.ConfigReceiver([]byte(config)); err != nil {
+				t.Fatal(err)
+			}
+			result := r.Review(&plugin.ReviewContent{ObjectType: "answer", Content: "text"})
+			if result.ReviewStatus != tt.status || result.Approved != tt.approved {
+				t.Errorf("Review = %q approved %v, want %q approved %v", result.ReviewStatus, result.Approved, tt.status, tt.approved)
+			}
+			if want := `rule "all" fired (` + string(tt.action) + `)`; !strings.HasPrefix(result.Reason, want) {
+				t.Errorf("Review reason %q, want it to start with %q", result.Reason, want)
+			}
+		})
+	}
+
+	if err := r.ConfigReceiver([]byte(`{"rules": "rules: ["}`)); err == nil {
+		t.Error("ConfigReceiver with invalid rules: got no error")
+	}
+	if result := r.Review(&plugin.ReviewContent{Content: "text"}); result.ReviewStatus != plugin.ReviewStatusNeedReview {
+		t.Errorf("after invalid rules: got %q, want the previous rules kept", result.ReviewStatus)
+	}
+}
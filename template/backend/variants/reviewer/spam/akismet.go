@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/apache/answer/plugin"
+)
+
+// AkismetClient calls an Akismet-compatible comment-check endpoint
+type AkismetClient struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	blogURL  string
+}
+
+// CheckSpam reports whether the API considers the content spam
+func (c *AkismetClient) CheckSpam(ctx context.Context, content *plugin.ReviewContent) (spam bool, err error) {
+	form := url.Values{}
+	form.Set("api_key", c.apiKey)
+	form.Set("blog", c.blogURL)
+	form.Set("user_ip", content.IP)
+	form.Set("user_agent", content.UserAgent)
+	form.Set("comment_type", commentType(content.ObjectType))
+	form.Set("comment_content", strings.TrimSpace(content.Title+"\n\n"+content.Content))
+	if content.Author.Role > 1 {
+		form.Set("user_role", "administrator")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("akismet returned %s", resp.Status)
+	}
+	switch strings.TrimSpace(string(body)) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	// "invalid" or an unexpected body, Akismet explains why in a debug header
+	return false, fmt.Errorf("akismet: unexpected response %q %s", body, resp.Header.Get("X-akismet-debug-help"))
+}
+
+// commentType maps Answer objects to Akismet comment types
+func commentType(objectType string) string {
+	switch objectType {
+	case "question":
+		return "forum-post"
+	case "answer":
+		return "reply"
+	}
+	return "comment"
+}
@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+// newAkismetStub serves comment-check, replying with reply to the requests it accepts
+func newAkismetStub(t *testing.T, status int, reply string) (*AkismetClient, *http.Request) {
+	t.Helper()
+	received := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*received = *r
+		if reply == "invalid" {
+			w.Header().Set("X-akismet-debug-help", "empty api_key")
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+	return &AkismetClient{
+		client:   server.Client(),
+		endpoint: server.URL,
+		apiKey:   "test-key",
+		blogURL:  "https://answer.example.com",
+	}, received
+}
+
+func TestAkismetCheckSpam(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		reply   string
+		spam    bool
+		wantErr string
+	}{
+		{"spam", http.StatusOK, "true", true, ""},
+		{"ham", http.StatusOK, "false\n", false, ""},
+		{"invalid key", http.StatusOK, "invalid", false, "empty api_key"},
+		{"server error", http.StatusInternalServerError, "", false, "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newAkismetStub(t, tt.status, tt.reply)
+			spam, err := client.CheckSpam(context.Background(), &plugin.ReviewContent{ObjectType: "question", Content: "buy now"})
+			if spam != tt.spam {
+				t.Errorf("spam = %v, want %v", spam, tt.spam)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("CheckSpam: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("CheckSpam error = %v, want one with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAkismetRequest(t *testing.T) {
+	client, received := newAkismetStub(t, http.StatusOK, "false")
+	content := &plugin.ReviewContent{
+		ObjectType: "answer",
+		Content:    "Try restarting the service.",
+		Author:     plugin.ReviewContentAuthor{Role: 2},
+		IP:         "203.0.113.5",
+		UserAgent:  "test-agent",
+	}
+	if _, err := client.CheckSpam(context.Background(), content); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"api_key":         "test-key",
+		"blog":            "https://answer.example.com",
+		"user_ip":         "203.0.113.5",
+		"user_agent":      "test-agent",
+		"comment_type":    "reply",
+		"comment_content": "Try restarting the service.",
+		"user_role":       "administrator",
+	}
+	for key, value := range want {
+		if got := received.PostForm.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestAkismetTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	client := &AkismetClient{client: server.Client(), endpoint: server.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.CheckSpam(ctx, &plugin.ReviewContent{Content: "x"}); err == nil {
+		t.Error("CheckSpam of a hanging endpoint returned no error")
+	}
+}
+
+func TestReviewBlocklistsBeforeAkismet(t *testing.T) {
+	client, received := newAkismetStub(t, http.StatusOK, "true")
+	r := newPlugin()
+	r.akismet = client
+
+	result := r.Review(&plugin.ReviewContent{ObjectType: "question", Content: "mail me at x@mailinator.com", IP: "198.51.100.1"})
+	if result.Approved || received.Method != "" {
+		t.Errorf("Review = %+v, Akismet called: %v, want the blocklist to decide first", result, received.Method != "")
+	}
+
+	result = r.Review(&plugin.ReviewContent{ObjectType: "question", Content: "buy now", IP: "198.51.100.1"})
+	if result.Approved || result.ReviewStatus != plugin.ReviewStatusNeedReview {
+		t.Errorf("Review of Akismet spam = %+v, want it held for review", result)
+	}
+}
+
+func TestReviewCachesAkismetVerdicts(t *testing.T) {
+	client, received := newAkismetStub(t, http.StatusOK, "true")
+	r := newPlugin()
+	r.akismet = client
+
+	content := &plugin.ReviewContent{ObjectType: "answer", Content: "buy now", IP: "198.51.100.1"}
+	first := r.Review(content)
+	*received = http.Request{}
+	second := r.Review(&plugin.ReviewContent{ObjectType: "answer", Content: "buy now", IP: "198.51.100.1"})
+	if received.Method != "" {
+		t.Error("Akismet was asked again about the same content")
+	}
+	if first.ReviewStatus != second.ReviewStatus || first.Reason != second.Reason {
+		t.Errorf("cached verdict differs: %+v vs %+v", first, second)
+	}
+
+	r.Review(&plugin.ReviewContent{ObjectType: "answer", Content: "buy now", IP: "198.51.100.2"})
+	if received.Method == "" {
+		t.Error("Akismet wasn't asked about the same content from another IP")
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"bufio"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Blocklist kinds, also used as the admin route parameter and KV key
+const (
+	ListEmailDomains  = "email-domains"
+	ListIPRanges      = "ip-ranges"
+	ListStopForumSpam = "stopforumspam"
+)
+
+var (
+	listKinds   = []string{ListEmailDomains, ListIPRanges, ListStopForumSpam}
+	emailRegexp = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@([a-z0-9.\-]+\.[a-z]{2,})`)
+)
+
+// Blocklist is an immutable set of blocked email domains and IP ranges
+type Blocklist struct {
+	domains map[string]bool
+	// ips and ranges remember the list each entry came from
+	ips     map[netip.Addr]string
+	ranges  []listedPrefix
+	sources map[string]string
+}
+
+type listedPrefix struct {
+	prefix netip.Prefix
+	source string
+}
+
+// NewBlocklist builds a blocklist from the raw list texts keyed by kind
+func NewBlocklist(sources map[string]string) (*Blocklist, error) {
+	b := &Blocklist{
+		domains: map[string]bool{},
+		ips:     map[netip.Addr]string{},
+		sources: map[string]string{},
+	}
+	for _, kind := range listKinds {
+		text, ok := sources[kind]
+		if !ok {
+			continue
+		}
+		b.sources[kind] = text
+
+		var err error
+		switch kind {
+		case ListEmailDomains:
+			err = b.addDomains(text)
+		case ListIPRanges, ListStopForumSpam:
+			err = b.addPrefixes(kind, text)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kind, err)
+		}
+	}
+	return b, nil
+}
+
+// ValidListKind reports whether kind names a blocklist
+func ValidListKind(kind string) bool {
+	for _, k := range listKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// With returns a copy of the blocklist with one list replaced
+func (b *Blocklist) With(kind, text string) (*Blocklist, error) {
+	sources := make(map[string]string, len(b.sources)+1)
+	for k, v := range b.sources {
+		sources[k] = v
+	}
+	sources[kind] = text
+	return NewBlocklist(sources)
+}
+
+// Source returns the raw text of a list
+func (b *Blocklist) Source(kind string) string {
+	return b.sources[kind]
+}
+
+// Stats counts the entries per list
+func (b *Blocklist) Stats() map[string]int {
+	stats := map[string]int{ListEmailDomains: len(b.domains)}
+	for _, source := range b.ips {
+		stats[source]++
+	}
+	for _, r := range b.ranges {
+		stats[r.source]++
+	}
+	return stats
+}
+
+// MatchIP returns the list that blocks the IP, if any
+func (b *Blocklist) MatchIP(ip string) (source string, ok bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return "", false
+	}
+	addr = addr.Unmap()
+	if source, ok := b.ips[addr]; ok {
+		return source, true
+	}
+	for _, r := range b.ranges {
+		if r.prefix.Contains(addr) {
+			return r.source, true
+		}
+	}
+	return "", false
+}
+
+// MatchPostedEmails returns the email addresses written in text, a post's title or
+// content, that use a blocked domain. Subdomains of a blocked domain are blocked too.
+func (b *Blocklist) MatchPostedEmails(text string) (emails []string) {
+	for _, m := range emailRegexp.FindAllStringSubmatch(text, -1) {
+		domain := strings.ToLower(m[1])
+		for domain != "" {
+			if b.domains[domain] {
+				emails = append(emails, m[0])
+				break
+			}
+			_, parent, found := strings.Cut(domain, ".")
+			if !found {
+				break
+			}
+			domain = parent
+		}
+	}
+	return emails
+}
+
+func (b *Blocklist) addDomains(text string) error {
+	return eachEntry(text, func(entry string) error {
+		domain := strings.ToLower(strings.TrimPrefix(entry, "@"))
+		if strings.ContainsAny(domain, " /@") || !strings.Contains(domain, ".") {
+			return fmt.Errorf("invalid domain %q", entry)
+		}
+		b.domains[domain] = true
+		return nil
+	})
+}
+
+// addPrefixes accepts one IP or CIDR per line. StopForumSpam CSV dumps are supported
+// by taking the first column ("ip,frequency,last seen").
+func (b *Blocklist) addPrefixes(source, text string) error {
+	err := eachEntry(text, func(entry string) error {
+		entry, _, _ = strings.Cut(entry, ",")
+		entry = strings.Trim(strings.TrimSpace(entry), `"`)
+
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return fmt.Errorf("invalid CIDR %q", entry)
+			}
+			b.ranges = append(b.ranges, listedPrefix{prefix: prefix.Masked(), source: source})
+			return nil
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return fmt.Errorf("invalid IP %q", entry)
+		}
+		if _, ok := b.ips[addr.Unmap()]; !ok {
+			b.ips[addr.Unmap()] = source
+		}
+		return nil
+	})
+	// Check wider ranges first so the reported source is stable
+	sort.SliceStable(b.ranges, func(i, j int) bool {
+		return b.ranges[i].prefix.Bits() < b.ranges[j].prefix.Bits()
+	})
+	return err
+}
+
+// eachEntry calls fn for every non-empty line, "#" starts a comment
+func eachEntry(text string, fn func(entry string) error) error {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		entry, _, _ := strings.Cut(scanner.Text(), "#")
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if err := fn(entry); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
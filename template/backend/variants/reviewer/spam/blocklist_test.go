@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchPostedEmails(t *testing.T) {
+	b, err := NewBlocklist(map[string]string{
+		ListEmailDomains: "# disposable\nmailinator.com\n@tempmail.dev\nTrash.Example\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"no address", "How do I configure SMTP?", nil},
+		{"allowed domain", "Write to alice@example.com", nil},
+		{"blocked domain", "Contact bob@mailinator.com for the file", []string{"bob@mailinator.com"}},
+		{"listed with @", "carol@tempmail.dev", []string{"carol@tempmail.dev"}},
+		{"case insensitive", "DAVE@MAILINATOR.COM and eve@trash.example", []string{"DAVE@MAILINATOR.COM", "eve@trash.example"}},
+		{"subdomain", "frank@eu.mailinator.com", []string{"frank@eu.mailinator.com"}},
+		{"lookalike domain", "grace@notmailinator.com", nil},
+		{"parent of a listed domain", "heidi@example", nil},
+		{"mailto link", "[mail me](mailto:ivan@mailinator.com)", []string{"ivan@mailinator.com"}},
+		{"URL credentials", "https://user@mailinator.com/path", []string{"user@mailinator.com"}},
+		{"URL on a blocked domain", "https://mailinator.com/inbox", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.MatchPostedEmails(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchPostedEmails(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchIP(t *testing.T) {
+	b, err := NewBlocklist(map[string]string{
+		ListIPRanges:      "203.0.113.0/24\n2001:db8::/32\n198.51.100.7 # single address\n",
+		ListStopForumSpam: "\"192.0.2.1\",12,2024-01-01\n192.0.2.2,3,2024-01-02\n10.0.0.0/8\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ip     string
+		source string
+		ok     bool
+	}{
+		{"203.0.113.42", ListIPRanges, true},
+		{"203.0.114.1", "", false},
+		{"198.51.100.7", ListIPRanges, true},
+		{"198.51.100.8", "", false},
+		{"::ffff:203.0.113.9", ListIPRanges, true},
+		{"2001:db8:1::1", ListIPRanges, true},
+		{"192.0.2.1", ListStopForumSpam, true},
+		{" 192.0.2.2 ", ListStopForumSpam, true},
+		{"10.1.2.3", ListStopForumSpam, true},
+		{"not an ip", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		source, ok := b.MatchIP(tt.ip)
+		if source != tt.source || ok != tt.ok {
+			t.Errorf("MatchIP(%q) = %q, %v, want %q, %v", tt.ip, source, ok, tt.source, tt.ok)
+		}
+	}
+}
+
+func TestNewBlocklistErrors(t *testing.T) {
+	tests := []struct {
+		kind, text, want string
+	}{
+		{ListEmailDomains, "example.com\nno-dot\n", "line 2"},
+		{ListEmailDomains, "has space.com", "invalid domain"},
+		{ListIPRanges, "203.0.113.0/33", "invalid CIDR"},
+		{ListStopForumSpam, "# header\n300.1.1.1,1,2024-01-01", "line 2: invalid IP"},
+	}
+	for _, tt := range tests {
+		_, err := NewBlocklist(map[string]string{tt.kind: tt.text})
+		if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.HasPrefix(err.Error(), tt.kind) {
+			t.Errorf("NewBlocklist(%s: %q) = %v, want an error with %q", tt.kind, tt.text, err, tt.want)
+		}
+	}
+}
+
+func TestBlocklistWith(t *testing.T) {
+	b, err := NewBlocklist(map[string]string{ListEmailDomains: "mailinator.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := b.With(ListIPRanges, "203.0.113.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.MatchIP("203.0.113.1"); ok {
+		t.Error("With changed the original blocklist")
+	}
+	if _, ok := updated.MatchIP("203.0.113.1"); !ok {
+		t.Error("With didn't add the list")
+	}
+	if len(updated.MatchPostedEmails("a@mailinator.com")) != 1 {
+		t.Error("With dropped the other lists")
+	}
+	if stats := updated.Stats(); stats[ListEmailDomains] != 1 || stats[ListIPRanges] != 1 {
+		t.Errorf("Stats = %v", stats)
+	}
+}
@@ -0,0 +1,35 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package i18n
+
+const (
+	InfoName                         = "plugin.{{info_slug_name}}.backend.info.name"
+	InfoDescription                  = "plugin.{{info_slug_name}}.backend.info.description"
+	ConfigActionReject               = "plugin.{{info_slug_name}}.backend.config.action.reject"
+	ConfigActionReview               = "plugin.{{info_slug_name}}.backend.config.action.review"
+	ConfigBlocklistActionTitle       = "plugin.{{info_slug_name}}.backend.config.blocklist_action.title"
+	ConfigBlocklistActionDescription = "plugin.{{info_slug_name}}.backend.config.blocklist_action.description"
+	ConfigAkismetEndpointTitle       = "plugin.{{info_slug_name}}.backend.config.akismet_endpoint.title"
+	ConfigAkismetEndpointDescription = "plugin.{{info_slug_name}}.backend.config.akismet_endpoint.description"
+	ConfigAkismetAPIKeyTitle         = "plugin.{{info_slug_name}}.backend.config.akismet_api_key.title"
+	ConfigAkismetAPIKeyDescription   = "plugin.{{info_slug_name}}.backend.config.akismet_api_key.description"
+	ConfigSiteURLTitle               = "plugin.{{info_slug_name}}.backend.config.site_url.title"
+	ConfigAkismetActionTitle         = "plugin.{{info_slug_name}}.backend.config.akismet_action.title"
+)
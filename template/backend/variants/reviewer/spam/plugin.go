@@ -0,0 +1,323 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/log"
+)
+
+//section:fields
+
+	mu        sync.RWMutex
+	blocklist *Blocklist
+	akismet   *AkismetClient
+	cache     *VerdictCache
+	operator  *plugin.KVOperator
+	audit     *AuditLog
+
+//section:config
+	// BlocklistAction applies to listed IPs and disposable email domains
+	BlocklistAction string `json:"blocklist_action"`
+	// AkismetEndpoint enables the Akismet-compatible check when set
+	AkismetEndpoint string `json:"akismet_endpoint"`
+	AkismetAPIKey   string `json:"akismet_api_key"`
+	SiteURL         string `json:"site_url"`
+	AkismetAction   string `json:"akismet_action"`
+
+//section:setup
+	blocklist, err := NewBlocklist(map[string]string{ListEmailDomains: defaultDisposableDomains})
+	if err != nil {
+		panic(fmt.Sprintf("invalid default blocklist: %v", err))
+	}
+
+//section:defaults
+			BlocklistAction: ActionReject,
+			AkismetEndpoint: "https://rest.akismet.com/1.1/comment-check",
+			AkismetAction:   ActionReview,
+
+//section:init
+		blocklist: blocklist,
+		cache:     NewVerdictCache(akismetCacheTTL, akismetCacheSize),
+		audit:     NewAuditLog(),
+
+//section:body
+//go:embed disposable_domains.txt
+var defaultDisposableDomains string
+
+const (
+	ActionReject = "reject"
+	ActionReview = "review"
+
+	// Audit rules of decisions that are not made by a blocklist
+	RuleAkismet        = "akismet"
+	RuleAkismetFailure = "akismet-failure"
+
+	blocklistGroup    = "blocklists"
+	akismetTimeout    = 3 * time.Second
+	akismetCacheTTL   = time.Hour
+	akismetCacheSize  = 10000
+	maxBlocklistBytes = 32 << 20
+)
+
+func (r *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	actions := []plugin.ConfigFieldOption{
+		{Value: ActionReject, Label: plugin.MakeTranslator(i18n.ConfigActionReject)},
+		{Value: ActionReview, Label: plugin.MakeTranslator(i18n.ConfigActionReview)},
+	}
+	return []plugin.ConfigField{
+		{
+			Name:        "blocklist_action",
+			Type:        plugin.ConfigTypeSelect,
+			Title:       plugin.MakeTranslator(i18n.ConfigBlocklistActionTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigBlocklistActionDescription),
+			Required:    true,
+			Value:       r.Config.BlocklistAction,
+			Options:     actions,
+		},
+		{
+			Name:        "akismet_endpoint",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigAkismetEndpointTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigAkismetEndpointDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeUrl},
+			Value:       r.Config.AkismetEndpoint,
+		},
+		{
+			Name:        "akismet_api_key",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigAkismetAPIKeyTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigAkismetAPIKeyDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypePassword},
+			Value:       r.Config.AkismetAPIKey,
+		},
+		{
+			Name:      "site_url",
+			Type:      plugin.ConfigTypeInput,
+			Title:     plugin.MakeTranslator(i18n.ConfigSiteURLTitle),
+			UIOptions: plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeUrl},
+			Value:     r.Config.SiteURL,
+		},
+		{
+			Name:    "akismet_action",
+			Type:    plugin.ConfigTypeSelect,
+			Title:   plugin.MakeTranslator(i18n.ConfigAkismetActionTitle),
+			Value:   r.Config.AkismetAction,
+			Options: actions,
+		},
+	}
+}
+
+func (r *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+	for _, action := range []string{c.BlocklistAction, c.AkismetAction} {
+		if action != "" && action != ActionReject && action != ActionReview {
+			return fmt.Errorf("invalid action %q", action)
+		}
+	}
+
+	var akismet *AkismetClient
+	if c.AkismetEndpoint != "" && c.AkismetAPIKey != "" {
+		akismet = &AkismetClient{
+			client:   &http.Client{},
+			endpoint: c.AkismetEndpoint,
+			apiKey:   c.AkismetAPIKey,
+			blogURL:  c.SiteURL,
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Config = c
+	r.akismet = akismet
+	// Cached verdicts came from the previous settings
+	r.cache = NewVerdictCache(akismetCacheTTL, akismetCacheSize)
+	return nil
+}
+
+// SetOperator receives the KV storage, blocklists uploaded from the admin route are kept there
+func (r *{{plugin_display_name}}) SetOperator(operator *plugin.KVOperator) {
+	r.audit.SetStore(operator)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operator = operator
+
+	ctx := context.Background()
+	for _, kind := range listKinds {
+		text, err := operator.Get(ctx, plugin.KVParams{Group: blocklistGroup, Key: kind})
+		if err != nil {
+			if !errors.Is(err, plugin.ErrKVKeyNotFound) {
+				log.Errorf("{{plugin_slug_name}}: load blocklist %s: %v", kind, err)
+			}
+			continue
+		}
+		blocklist, err := r.blocklist.With(kind, text)
+		if err != nil {
+			log.Warnf("{{plugin_slug_name}}: stored blocklist %s is invalid: %v", kind, err)
+			continue
+		}
+		r.blocklist = blocklist
+	}
+}
+
+// Review checks the author's IP and the email addresses posted in the title and content
+// against the blocklists, then asks Akismet if it is configured. Answer doesn't pass the
+// author's own email address. Every decision is recorded in the audit log.
+func (r *{{plugin_display_name}}) Review(content *plugin.ReviewContent) (result *plugin.ReviewResult) {
+	start := time.Now()
+	result, rule := r.review(content)
+	r.audit.Record(content, result, rule, start)
+	return result
+}
+
+// review returns the result and the blocklist or check that decided it
+func (r *{{plugin_display_name}}) review(content *plugin.ReviewContent) (result *plugin.ReviewResult, rule string) {
+	r.mu.RLock()
+	blocklist, akismet, cache, config := r.blocklist, r.akismet, r.cache, r.Config
+	r.mu.RUnlock()
+
+	if source, ok := blocklist.MatchIP(content.IP); ok {
+		return flagged(config.BlocklistAction, fmt.Sprintf("IP %s is listed in the %s blocklist", content.IP, source)), source
+	}
+	if emails := blocklist.MatchPostedEmails(content.Title + "\n" + content.Content); len(emails) > 0 {
+		return flagged(config.BlocklistAction, "the post contains a disposable email address: "+strings.Join(emails, ", ")), ListEmailDomains
+	}
+
+	if akismet != nil {
+		// Akismet weighs the author's IP and user agent too, failures are never cached
+		key := ContentHash(content) + "\x00" + content.IP + "\x00" + content.UserAgent
+		if cached, rule, ok := cache.Get(key); ok {
+			return cached, rule
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), akismetTimeout)
+		defer cancel()
+		spam, err := akismet.CheckSpam(ctx, content)
+		if err != nil {
+			return approve(fmt.Sprintf("akismet check failed, approved: %v", err)), RuleAkismetFailure
+		}
+		result, rule = approve("not listed, akismet: not spam"), ""
+		if spam {
+			result, rule = flagged(config.AkismetAction, "akismet flagged the content as spam"), RuleAkismet
+		}
+		cache.Set(key, result, rule)
+		return result, rule
+	}
+	return approve("not listed"), ""
+}
+
+func flagged(action, reason string) *plugin.ReviewResult {
+	if action == ActionReview {
+		return &plugin.ReviewResult{ReviewStatus: plugin.ReviewStatusNeedReview, Reason: reason}
+	}
+	return &plugin.ReviewResult{ReviewStatus: plugin.ReviewStatusDeleteDirectly, Reason: reason}
+}
+
+func approve(reason string) *plugin.ReviewResult {
+	return &plugin.ReviewResult{Approved: true, ReviewStatus: plugin.ReviewStatusApproved, Reason: reason}
+}
+
+func (r *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {}
+
+func (r *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {}
+
+// RegisterAuthAdminRouter exposes the blocklists and the audit log to administrators:
+//
+//	GET /{{plugin_slug_name}}/blocklists        entry counts per list
+//	GET /{{plugin_slug_name}}/blocklists/:kind  raw list, one entry per line
+//	PUT /{{plugin_slug_name}}/blocklists/:kind  replace the list, ?mode=append adds to it
+//	    /{{plugin_slug_name}}/audit             see AuditLog.RegisterRoutes
+func (r *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {
+	r.audit.RegisterRoutes(router.Group("/{{plugin_slug_name}}/audit"))
+
+	group := router.Group("/{{plugin_slug_name}}/blocklists")
+	group.GET("", r.listBlocklists)
+	group.GET("/:kind", r.getBlocklist)
+	group.PUT("/:kind", r.updateBlocklist)
+}
+
+func (r *{{plugin_display_name}}) listBlocklists(ctx *gin.Context) {
+	r.mu.RLock()
+	stats := r.blocklist.Stats()
+	r.mu.RUnlock()
+	ctx.JSON(http.StatusOK, gin.H{"entries": stats})
+}
+
+func (r *{{plugin_display_name}}) getBlocklist(ctx *gin.Context) {
+	kind := ctx.Param("kind")
+	if !ValidListKind(kind) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "unknown blocklist " + kind})
+		return
+	}
+	r.mu.RLock()
+	text := r.blocklist.Source(kind)
+	r.mu.RUnlock()
+	ctx.String(http.StatusOK, text)
+}
+
+func (r *{{plugin_display_name}}) updateBlocklist(ctx *gin.Context) {
+	kind := ctx.Param("kind")
+	if !ValidListKind(kind) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "unknown blocklist " + kind})
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxBlocklistBytes))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	text := string(body)
+	if ctx.Query("mode") == "append" {
+		text = strings.TrimRight(r.blocklist.Source(kind), "\n") + "\n" + text
+	}
+	blocklist, err := r.blocklist.With(kind, text)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if r.operator != nil {
+		err = r.operator.Set(ctx.Request.Context(), plugin.KVParams{Group: blocklistGroup, Key: kind, Value: text})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	r.blocklist = blocklist
+	ctx.JSON(http.StatusOK, gin.H{"entries": blocklist.Stats()})
+}
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package {{package_name}}
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/answer/plugin"
+)
+
+//section:config
+	Endpoint        string `json:"endpoint"`
+	BucketName      string `json:"bucket_name"`
+	AccessKeyID     string `json:"access_key_id"`
+	AccessKeySecret string `json:"access_key_secret"`
+
+//section:body
+// UploadFile stores the file of the "file" form field, source tells what it's for, e.g. an avatar or a post.
+// Answer before v1.4.2 checks the file type and size itself.
+func (s *{{plugin_display_name}}) UploadFile(ctx *plugin.GinContext, source plugin.UploadSource) (resp plugin.UploadFileResponse) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		resp.OriginalError = fmt.Errorf("get upload file failed: %w", err)
+		return resp
+	}
+
+	// TODO: Implement file upload logic
+	// This is a Hello World example - implement your storage logic here
+	resp.FullURL = "https://example.com/hello-world/" + string(source) + strings.ToLower(filepath.Ext(file.Filename))
+	return resp
+}
+
+func (s *{{plugin_display_name}}) DeleteFile(ctx *plugin.GinContext, filePath string) (err error) {
+	// TODO: Implement file deletion logic
+	// This is a Hello World example - implement your storage logic here
+	return nil
+}
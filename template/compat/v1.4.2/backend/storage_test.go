@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"{{testkit_module}}"
+	"github.com/apache/answer/plugin"
+)
+
+// TestStorageContract runs the storage contract against the plugin
+func TestStorageContract(t *testing.T) {
+	testStorageContract(t, func(t *testing.T) fileUploader {
+		return newPlugin()
+	})
+}
+
+// fileUploader is the part of plugin.Storage the contract covers, deleting
+// needs a bucket and is left to your own tests
+type fileUploader interface {
+	UploadFile(ctx *plugin.GinContext, source plugin.UploadSource) (resp plugin.UploadFileResponse)
+}
+
+// testStorageContract checks that a plugin.Storage stores the file of the
+// "file" form field and returns its URL
+func testStorageContract(t *testing.T, newStorage func(t *testing.T) fileUploader) {
+	upload := func(s fileUploader, files ...testkit.File) plugin.UploadFileResponse {
+		req := testkit.NewRequest(t, http.MethodPost, testkit.AuthUserPrefix+"/file", testkit.WithMultipart(
+			map[string]string{"source": string(plugin.UserPost)}, files...))
+		ctx, _ := testkit.NewGinContext(t, req)
+		return s.UploadFile(ctx, plugin.UserPost)
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		resp := upload(newStorage(t), testkit.File{Field: "file", Name: "photo.jpg", ContentType: "image/jpeg", Content: []byte("jpeg")})
+		if resp.OriginalError != nil {
+			t.Fatalf("UploadFile(photo.jpg): %v", resp.OriginalError)
+		}
+		if u, err := url.Parse(resp.FullURL); err != nil || !u.IsAbs() {
+			t.Errorf("UploadFile(photo.jpg) FullURL = %q, want an absolute URL", resp.FullURL)
+		}
+	})
+
+	t.Run("other field", func(t *testing.T) {
+		resp := upload(newStorage(t), testkit.File{Field: "image", Name: "photo.jpg", Content: []byte("jpeg")})
+		if resp.OriginalError == nil || resp.FullURL != "" {
+			t.Errorf("UploadFile without a file field = %+v, want an error and no URL", resp)
+		}
+	})
+}
@@ -1,29 +1,70 @@
-package answer
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
 import (
-  "embed"
-  "github.com/apache/answer/plugin"
+	"embed"
 
-  "github.com/apache/answer-plugins/util"
+	"{{module_path}}/{{plugin_name}}/i18n"
+	"github.com/apache/answer-plugins/util"
+	"github.com/apache/answer/plugin"
 )
 
-//go:embed  info.yaml
+//go:embed info.yaml
 var Info embed.FS
 
 type {{plugin_display_name}} struct {
+	Config *{{plugin_display_name}}Config
+	{{slot:fields}}
+}
+
+type {{plugin_display_name}}Config struct {
+	{{slot:config}}
 }
+
 func init() {
-	plugin.Register(&{{plugin_display_name}}{})
+	plugin.Register(newPlugin())
 }
-func ({{plugin_display_name}}) Info() plugin.Info {
-  info := &util.Info{}
+
+// newPlugin returns the plugin with its default configuration, the tests start from it too
+func newPlugin() *{{plugin_display_name}} {
+	{{slot:setup}}
+	return &{{plugin_display_name}}{
+		Config: &{{plugin_display_name}}Config{
+			{{slot:defaults}}
+		},
+		{{slot:init}}
+	}
+}
+
+func ({{slot:receiver}} *{{plugin_display_name}}) Info() plugin.Info {
+	info := &util.Info{}
 	info.GetInfo(Info)
 
-  return plugin.Info{
-    Name:        plugin.MakeTranslator("{{plugin_display_name}}"),
-    SlugName:    info.SlugName,
-    Description: plugin.MakeTranslator(""),
-    Author:      info.Author,
-    Version:     info.Version,
-    Link:        info.Link,
-  }
+	return plugin.Info{
+		Name:        plugin.MakeTranslator(i18n.InfoName),
+		SlugName:    info.SlugName,
+		Description: plugin.MakeTranslator(i18n.InfoDescription),
+		Author:      info.Author,
+		Version:     info.Version,
+		Link:        info.Link,
+	}
 }
+
+{{slot:body}}
@@ -0,0 +1,276 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package devserver runs the plugins linked into a binary without Answer and
+// serves a small page to try them out: upload a file to a storage, log in
+// through a connector against a fake identity provider, search, review a
+// post, and so on. The cmd/answer-dev of a generated plugin calls Main.
+package devserver
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed page.html
+var pageFS embed.FS
+
+// Options set up the harness
+type Options struct {
+	// BaseURL is where the harness is reachable, connectors are sent back to it
+	BaseURL string
+	// Configs are applied to the plugins by slug name when the harness starts,
+	// as Answer applies the saved settings
+	Configs map[string]json.RawMessage
+	// Plugins are the plugins to exercise, the registered ones by default
+	Plugins []plugin.Base
+	// Log receives a line per request, nothing is logged if it is nil
+	Log io.Writer
+}
+
+// Server is the harness, it serves the page, the exercises, the routes of the
+// plugins and the fake identity provider
+type Server struct {
+	engine  *gin.Engine
+	baseURL string
+	page    *template.Template
+	plugins []*pluginPage
+	// mu runs one exercise at a time, the plugins and the page aren't shared
+	mu sync.Mutex
+}
+
+// pluginPage is the part of the page about one plugin
+type pluginPage struct {
+	Slug      string
+	Version   string
+	Types     []string
+	Notes     []string
+	Exercises []*exercise
+}
+
+// exercise is something to try with a plugin, a form on the page. GET
+// exercises are links, e.g. the login of a connector.
+type exercise struct {
+	Title  string
+	Method string
+	Path   string
+	Fields []*field
+	// Hidden exercises are only reached through another one, e.g. the
+	// callback of a login
+	Hidden bool
+	run    func(ctx *gin.Context) (any, error)
+}
+
+// Multipart tells whether the form uploads a file
+func (e *exercise) Multipart() bool {
+	for _, f := range e.Fields {
+		if f.Type == "file" {
+			return true
+		}
+	}
+	return false
+}
+
+type field struct {
+	Name  string
+	Label string
+	// Type is text, textarea, file or select
+	Type    string
+	Value   string
+	Options []string
+}
+
+// withImage is the output of an exercise that comes with an image to show,
+// e.g. a captcha
+type withImage struct {
+	Image  string
+	Output any
+}
+
+// result is the outcome of the last exercise, shown above the plugins
+type result struct {
+	Title  string
+	Output string
+	Err    string
+	Image  template.URL
+}
+
+type pageData struct {
+	Result  *result
+	Plugins []*pluginPage
+	IdP     string
+}
+
+// New boots the plugins and returns the harness serving them
+func New(opts Options) (*Server, error) {
+	plugins := opts.Plugins
+	if len(plugins) == 0 {
+		_ = plugin.CallBase(func(p plugin.Base) error {
+			plugins = append(plugins, p)
+			return nil
+		})
+	}
+	if len(plugins) == 0 {
+		return nil, errors.New("no plugin is registered, import the package of the plugin")
+	}
+	page, err := template.ParseFS(pageFS, "page.html")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		engine:  gin.New(),
+		baseURL: strings.TrimSuffix(opts.BaseURL, "/"),
+		page:    page,
+	}
+	if opts.Log != nil {
+		s.engine.Use(gin.LoggerWithWriter(opts.Log))
+	}
+	s.engine.Use(gin.Recovery())
+	s.engine.GET("/", func(ctx *gin.Context) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.render(ctx, nil)
+	})
+	newIdentityProvider().register(s.engine.Group(idpPrefix))
+
+	for _, p := range plugins {
+		slug := p.Info().SlugName
+		if config, ok := opts.Configs[slug]; ok {
+			c, ok := p.(plugin.Config)
+			if !ok {
+				return nil, fmt.Errorf("%s has no settings", slug)
+			}
+			if err := c.ConfigReceiver(config); err != nil {
+				return nil, fmt.Errorf("configure %s: %w", slug, err)
+			}
+		}
+		s.plugins = append(s.plugins, s.mount(p))
+	}
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.engine.ServeHTTP(w, r)
+}
+
+// mount registers the exercises of the interfaces p implements and its routes
+func (s *Server) mount(p plugin.Base) *pluginPage {
+	info := p.Info()
+	page := &pluginPage{Slug: info.SlugName, Version: info.Version}
+	for _, kind := range kinds {
+		if !kind.implemented(p) {
+			continue
+		}
+		page.Types = append(page.Types, kind.name)
+		exercises, notes := kind.exercises(s, p)
+		page.Exercises = append(page.Exercises, exercises...)
+		page.Notes = append(page.Notes, notes...)
+	}
+
+	for i, e := range page.Exercises {
+		if e.Method == "" {
+			e.Method = http.MethodPost
+		}
+		if e.Path == "" {
+			e.Path = fmt.Sprintf("/dev/%s/%d", page.Slug, i)
+		}
+		s.engine.Handle(e.Method, e.Path, s.handle(page.Slug, e))
+	}
+	return page
+}
+
+// handle runs an exercise and shows its result on the page, unless the
+// exercise answered itself, e.g. with a redirect
+func (s *Server) handle(slug string, e *exercise) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		output, err := e.run(ctx)
+		if ctx.Writer.Written() {
+			return
+		}
+		r := &result{Title: slug + ": " + e.Title}
+		if err != nil {
+			r.Err = err.Error()
+		}
+		if img, ok := output.(withImage); ok {
+			if strings.HasPrefix(img.Image, "data:image/") {
+				r.Image = template.URL(img.Image)
+			}
+			output = img.Output
+		}
+		if output != nil {
+			data, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				data = []byte(fmt.Sprintf("%+v", output))
+			}
+			r.Output = string(data)
+		}
+		s.render(ctx, r)
+	}
+}
+
+func (s *Server) render(ctx *gin.Context, r *result) {
+	ctx.Header("Content-Type", "text/html; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	data := pageData{Result: r, Plugins: s.plugins, IdP: s.baseURL + idpPrefix}
+	if err := s.page.Execute(ctx.Writer, data); err != nil {
+		log.Printf("answer-dev: render page: %v", err)
+	}
+}
+
+// Main runs the harness for the plugins the binary imports
+func Main() {
+	addr := flag.String("addr", "localhost:8765", "address to listen on")
+	configFile := flag.String("config", "", "JSON file with the settings of each plugin by slug name")
+	flag.Parse()
+
+	opts := Options{BaseURL: "http://" + *addr, Log: os.Stderr}
+	if *configFile != "" {
+		data, err := os.ReadFile(*configFile)
+		if err == nil {
+			err = json.Unmarshal(data, &opts.Configs)
+		}
+		if err != nil {
+			log.Fatalf("answer-dev: read settings: %v", err)
+		}
+	}
+	gin.SetMode(gin.ReleaseMode)
+	s, err := New(opts)
+	if err != nil {
+		log.Fatalf("answer-dev: %v", err)
+	}
+	log.Printf("answer-dev: trying %d plugin(s) on %s", len(s.plugins), opts.BaseURL)
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
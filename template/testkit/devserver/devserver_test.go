@@ -0,0 +1,259 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package devserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+
+	"{{testkit_module}}"
+)
+
+// fakePlugin is an OAuth connector of the fake identity provider, a storage
+// and a reviewer
+type fakePlugin struct {
+	IdP string `json:"idp"`
+}
+
+func (p *fakePlugin) Info() plugin.Info {
+	return plugin.Info{SlugName: "fake", Version: "0.0.1"}
+}
+
+func (p *fakePlugin) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{{Name: "idp", Type: plugin.ConfigTypeInput, Value: p.IdP}}
+}
+
+func (p *fakePlugin) ConfigReceiver(config []byte) error {
+	return json.Unmarshal(config, p)
+}
+
+func (p *fakePlugin) ConnectorLogoSVG() string         { return "<svg/>" }
+func (p *fakePlugin) ConnectorName() plugin.Translator { return plugin.MakeTranslator("fake") }
+func (p *fakePlugin) ConnectorSlugName() string        { return "fake" }
+
+func (p *fakePlugin) ConnectorSender(ctx *plugin.GinContext, receiverURL string) string {
+	return p.IdP + "/authorize?" + url.Values{"client_id": {"answer"}, "redirect_uri": {receiverURL}, "login": {"alice"}}.Encode()
+}
+
+func (p *fakePlugin) ConnectorReceiver(ctx *plugin.GinContext, receiverURL string) (plugin.ExternalLoginUserInfo, error) {
+	resp, err := http.PostForm(p.IdP+"/token", url.Values{"code": {ctx.Query("code")}, "redirect_uri": {receiverURL}})
+	if err != nil {
+		return plugin.ExternalLoginUserInfo{}, err
+	}
+	defer resp.Body.Close()
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		return plugin.ExternalLoginUserInfo{}, fmt.Errorf("no token: %s, %v", resp.Status, err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, p.IdP+"/userinfo", nil)
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return plugin.ExternalLoginUserInfo{}, err
+	}
+	defer resp.Body.Close()
+	user := map[string]any{}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return plugin.ExternalLoginUserInfo{}, err
+	}
+	return plugin.ExternalLoginUserInfo{ExternalID: fmt.Sprint(user["sub"]), Username: fmt.Sprint(user["login"])}, nil
+}
+
+func (p *fakePlugin) UploadFile(ctx *plugin.GinContext, condition plugin.UploadFileCondition) plugin.UploadFileResponse {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return plugin.UploadFileResponse{OriginalError: err}
+	}
+	return plugin.UploadFileResponse{FullURL: "https://cdn.example.com/" + string(condition.Source) + "/" + file.Filename}
+}
+
+func (p *fakePlugin) Review(content *plugin.ReviewContent) *plugin.ReviewResult {
+	if strings.Contains(content.Content, "casino") {
+		return &plugin.ReviewResult{ReviewStatus: plugin.ReviewStatusNeedReview, Reason: "spam"}
+	}
+	return &plugin.ReviewResult{Approved: true, ReviewStatus: plugin.ReviewStatusApproved}
+}
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	var s *Server
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	config, _ := json.Marshal(map[string]string{"idp": ts.URL + idpPrefix})
+	s, err := New(Options{
+		BaseURL: ts.URL,
+		Configs: map[string]json.RawMessage{"fake": config},
+		Plugins: []plugin.Base{&fakePlugin{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, ts
+}
+
+func exercisePath(t *testing.T, s *Server, title string) string {
+	t.Helper()
+	for _, e := range s.plugins[0].Exercises {
+		if e.Title == title {
+			return e.Path
+		}
+	}
+	t.Fatalf("no exercise %q", title)
+	return ""
+}
+
+func get(t *testing.T, target string) string {
+	t.Helper()
+	resp, err := http.Get(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %s: %s", resp.Status, body)
+	}
+	return string(body)
+}
+
+func TestPage(t *testing.T) {
+	s, ts := newTestServer(t)
+	if got := s.plugins[0].Types; strings.Join(got, ",") != "config,connector,storage,reviewer" {
+		t.Errorf("types = %v", got)
+	}
+	page := get(t, ts.URL)
+	for _, want := range []string{"Save the settings", "Log in", "Upload a file", "Review a post", ts.URL + idpPrefix} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page has no %q", want)
+		}
+	}
+	if strings.Contains(page, "Login callback") {
+		t.Error("the page links the callback of the login")
+	}
+}
+
+func TestConnectorLogin(t *testing.T) {
+	s, ts := newTestServer(t)
+	page := get(t, ts.URL+exercisePath(t, s, "Log in"))
+	if !strings.Contains(page, "&#34;ExternalID&#34;: &#34;1001&#34;") || !strings.Contains(page, "alice") {
+		t.Errorf("the login didn't end with alice:\n%s", page)
+	}
+}
+
+func TestConnectorLoginRejectsUnknownCode(t *testing.T) {
+	_, ts := newTestServer(t)
+	page := get(t, ts.URL+testkit.UnAuthPrefix+"/connector/redirect/fake?code=forged")
+	if !strings.Contains(page, "no token") {
+		t.Errorf("a forged code logged in:\n%s", page)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	s, _ := newTestServer(t)
+	req := testkit.NewRequest(t, http.MethodPost, exercisePath(t, s, "Upload a file"), testkit.WithMultipart(
+		map[string]string{"source": string(plugin.UserPostAttachment)},
+		testkit.File{Field: "file", Name: "manual.pdf", Content: []byte("%PDF")},
+	))
+	page := testkit.Serve(s, req).Body.String()
+	if !strings.Contains(page, "https://cdn.example.com/user_post_attachment/manual.pdf") {
+		t.Errorf("upload result missing:\n%s", page)
+	}
+}
+
+func TestReviewAndSettings(t *testing.T) {
+	s, _ := newTestServer(t)
+	review := func(content string) string {
+		req := testkit.NewRequest(t, http.MethodPost, exercisePath(t, s, "Review a post"),
+			testkit.WithForm(url.Values{"title": {"Hi"}, "content": {content}}))
+		return testkit.Serve(s, req).Body.String()
+	}
+	if page := review("Visit my casino"); !strings.Contains(page, "need_review") {
+		t.Errorf("spam approved:\n%s", page)
+	}
+
+	req := testkit.NewRequest(t, http.MethodPost, exercisePath(t, s, "Save the settings"),
+		testkit.WithForm(url.Values{"config": {`{"idp": 1}`}}))
+	if page := testkit.Serve(s, req).Body.String(); !strings.Contains(page, `class="error"`) {
+		t.Errorf("invalid settings saved:\n%s", page)
+	}
+}
+
+func TestNewWithoutPlugins(t *testing.T) {
+	if _, err := New(Options{}); err == nil {
+		t.Error("New() without a registered plugin: want an error")
+	}
+}
+
+func TestIdentityProvider(t *testing.T) {
+	engine := gin.New()
+	newIdentityProvider().register(engine.Group(idpPrefix))
+
+	authorize := testkit.NewRequest(t, http.MethodGet, idpPrefix+"/authorize?"+url.Values{
+		"redirect_uri": {"https://answer.example.com/callback"}, "state": {"s1"}, "login": {"bob"},
+	}.Encode())
+	rec := testkit.Serve(engine, authorize)
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil || location.Query().Get("state") != "s1" || location.Query().Get("code") == "" {
+		t.Fatalf("authorize redirected to %q", rec.Header().Get("Location"))
+	}
+
+	exchange := func() *httptest.ResponseRecorder {
+		return testkit.Serve(engine, testkit.NewRequest(t, http.MethodPost, idpPrefix+"/token",
+			testkit.WithForm(url.Values{"code": {location.Query().Get("code")}})))
+	}
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	testkit.DecodeJSON(t, exchange(), &token)
+	if rec := exchange(); rec.Code != http.StatusBadRequest {
+		t.Errorf("code used twice: status %d", rec.Code)
+	}
+
+	user := map[string]any{}
+	rec = testkit.Serve(engine, testkit.NewRequest(t, http.MethodGet, idpPrefix+"/userinfo",
+		testkit.WithHeader("Authorization", "Bearer "+token.AccessToken)))
+	testkit.DecodeJSON(t, rec, &user)
+	if user["login"] != "bob" {
+		t.Errorf("userinfo = %v", user)
+	}
+
+	rec = testkit.Serve(engine, testkit.NewRequest(t, http.MethodGet, idpPrefix+"/userinfo"))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("userinfo without a token: status %d", rec.Code)
+	}
+}
@@ -0,0 +1,471 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+
+	"{{testkit_module}}"
+)
+
+// kind is a plugin interface the harness knows how to exercise
+type kind struct {
+	name        string
+	implemented func(p plugin.Base) bool
+	exercises   func(s *Server, p plugin.Base) ([]*exercise, []string)
+}
+
+func implements[T any](p plugin.Base) bool {
+	_, ok := p.(T)
+	return ok
+}
+
+// The parts of the interfaces the harness drives, as the contract tests of
+// the templates do
+type (
+	searcher interface {
+		SearchContents(ctx context.Context, cond *plugin.SearchBasicCond) (results []plugin.SearchResult, total int64, err error)
+		UpdateContent(ctx context.Context, content *plugin.SearchContent) error
+	}
+	userCenter interface {
+		LoginCallback(ctx *plugin.GinContext) (userInfo *plugin.UserCenterBasicUserInfo, err error)
+		UserInfo(externalID string) (userInfo *plugin.UserCenterBasicUserInfo, err error)
+		UserStatus(externalID string) (userStatus plugin.UserStatus)
+	}
+)
+
+var kinds = []kind{
+	{"config", implements[plugin.Config], configExercises},
+	{"user config", implements[plugin.UserConfig], userConfigExercises},
+	{"cache", implements[plugin.Cache], cacheExercises},
+	{"connector", implements[plugin.Connector], connectorExercises},
+	{"storage", implements[plugin.Storage], storageExercises},
+	{"search", implements[searcher], searchExercises},
+	{"user center", implements[userCenter], userCenterExercises},
+	{"notification", implements[plugin.Notification], notificationExercises},
+	{"reviewer", implements[plugin.Reviewer], reviewerExercises},
+	{"filter", implements[plugin.Filter], filterExercises},
+	{"importer", implements[plugin.Importer], importerExercises},
+	{"captcha", implements[plugin.Captcha], captchaExercises},
+	{"embed", implements[plugin.Embed], embedExercises},
+	{"kv storage", implements[plugin.KVStorage], kvStorageExercises},
+	{"agent", implements[plugin.Agent], agentExercises},
+}
+
+// settings renders the values of fields the way Answer sends them to the plugin
+func settings(fields []plugin.ConfigField) string {
+	values := map[string]any{}
+	for _, f := range fields {
+		if f.Type != plugin.ConfigTypeLegend {
+			values[f.Name] = f.Value
+		}
+	}
+	data, _ := json.MarshalIndent(values, "", "  ")
+	return string(data)
+}
+
+func configExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	c := p.(plugin.Config)
+	config := &field{Name: "config", Label: "Settings, as Answer sends them", Type: "textarea", Value: settings(c.ConfigFields())}
+	return []*exercise{{
+		Title:  "Save the settings",
+		Fields: []*field{config},
+		run: func(ctx *gin.Context) (any, error) {
+			value := ctx.PostForm("config")
+			if err := c.ConfigReceiver([]byte(value)); err != nil {
+				return nil, err
+			}
+			config.Value = value
+			return json.RawMessage(value), nil
+		},
+	}}, nil
+}
+
+func userConfigExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	c := p.(plugin.UserConfig)
+	return []*exercise{{
+		Title: "Save the settings of a user",
+		Fields: []*field{
+			{Name: "user_id", Label: "User ID", Type: "text", Value: "1"},
+			{Name: "config", Label: "Settings, as Answer sends them", Type: "textarea", Value: settings(c.UserConfigFields())},
+		},
+		run: func(ctx *gin.Context) (any, error) {
+			value := ctx.PostForm("config")
+			if err := c.UserConfigReceiver(ctx.PostForm("user_id"), []byte(value)); err != nil {
+				return nil, err
+			}
+			return json.RawMessage(value), nil
+		},
+	}}, nil
+}
+
+func cacheExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	c := p.(plugin.Cache)
+	return []*exercise{
+		{
+			Title: "Set and read a key",
+			Fields: []*field{
+				{Name: "key", Label: "Key", Type: "text", Value: "answer-dev"},
+				{Name: "value", Label: "Value", Type: "text", Value: "hello"},
+				{Name: "ttl", Label: "TTL in seconds", Type: "text", Value: "60"},
+			},
+			run: func(ctx *gin.Context) (any, error) {
+				key := ctx.PostForm("key")
+				ttl, err := strconv.Atoi(ctx.PostForm("ttl"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid TTL: %w", err)
+				}
+				if err := c.SetString(ctx.Request.Context(), key, ctx.PostForm("value"), time.Duration(ttl)*time.Second); err != nil {
+					return nil, err
+				}
+				value, exist, err := c.GetString(ctx.Request.Context(), key)
+				return gin.H{"value": value, "exist": exist}, err
+			},
+		},
+		{
+			Title: "Increase a counter",
+			Fields: []*field{
+				{Name: "key", Label: "Key", Type: "text", Value: "answer-dev:views"},
+				{Name: "by", Label: "By", Type: "text", Value: "1"},
+			},
+			run: func(ctx *gin.Context) (any, error) {
+				by, err := strconv.ParseInt(ctx.PostForm("by"), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid amount: %w", err)
+				}
+				value, err := c.Increase(ctx.Request.Context(), ctx.PostForm("key"), by)
+				return gin.H{"value": value}, err
+			},
+		},
+	}, nil
+}
+
+// connectorExercises sends the login to the connector and takes the callback
+// on the paths Answer uses
+func connectorExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	c := p.(plugin.Connector)
+	base := testkit.UnAuthPrefix + "/connector"
+	receiverPath := base + "/redirect/" + c.ConnectorSlugName()
+	receiverURL := s.baseURL + receiverPath
+	notes := []string{
+		"The login redirects to the URL the connector returns. To log in against the fake identity provider, set the connector up with " +
+			s.baseURL + idpPrefix + "/authorize, /token and /userinfo, any client ID and secret will do.",
+		"The connector is sent back to " + receiverURL,
+	}
+	return []*exercise{
+		{
+			Title:  "Log in",
+			Method: http.MethodGet,
+			Path:   base + "/login/" + c.ConnectorSlugName(),
+			run: func(ctx *gin.Context) (any, error) {
+				redirectURL := c.ConnectorSender(ctx, receiverURL)
+				if ctx.Writer.Written() {
+					return nil, nil
+				}
+				if redirectURL == "" {
+					return nil, errors.New("ConnectorSender returned no URL to redirect to")
+				}
+				ctx.Redirect(http.StatusFound, redirectURL)
+				return nil, nil
+			},
+		},
+		{
+			Title:  "Login callback",
+			Method: http.MethodGet,
+			Path:   receiverPath,
+			Hidden: true,
+			run: func(ctx *gin.Context) (any, error) {
+				userInfo, err := c.ConnectorReceiver(ctx, receiverURL)
+				if err != nil {
+					return nil, err
+				}
+				if userInfo.ExternalID == "" {
+					return userInfo, errors.New("the user has no ExternalID, Answer rejects the login")
+				}
+				return userInfo, nil
+			},
+		},
+	}, notes
+}
+
+// uploadCondition is what Answer passes to a storage with its default site settings
+func uploadCondition(source plugin.UploadSource) plugin.UploadFileCondition {
+	return plugin.UploadFileCondition{
+		Source:                         source,
+		MaxImageSize:                   4,
+		MaxAttachmentSize:              8,
+		MaxImageMegapixel:              40,
+		AuthorizedImageExtensions:      []string{"jpg", "jpeg", "png", "gif", "webp"},
+		AuthorizedAttachmentExtensions: []string{"pdf", "zip", "txt", "md"},
+	}
+}
+
+func storageExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	st := p.(plugin.Storage)
+	sources := []string{string(plugin.UserPost), string(plugin.UserPostAttachment), string(plugin.UserAvatar), string(plugin.AdminBranding)}
+	return []*exercise{{
+		Title: "Upload a file",
+		Fields: []*field{
+			{Name: "file", Label: "File", Type: "file"},
+			{Name: "source", Label: "Source", Type: "select", Value: sources[0], Options: sources},
+		},
+		run: func(ctx *gin.Context) (any, error) {
+			condition := uploadCondition(plugin.UploadSource(ctx.PostForm("source")))
+			resp := st.UploadFile(ctx, condition)
+			if resp.OriginalError != nil {
+				return nil, resp.OriginalError
+			}
+			return gin.H{"full_url": resp.FullURL, "condition": condition}, nil
+		},
+	}}, nil
+}
+
+// samplePosts are indexed by the search exercise
+var samplePosts = []*plugin.SearchContent{
+	{ObjectID: "10010000000000001", Type: "question", Title: "How do I write an Answer plugin?", Content: "I want to store uploads in my own bucket.", Tags: []string{"plugin", "storage"}, UserID: "1", Answers: 1, Views: 42, Score: 3, Created: 1700000000, Active: 1700000300},
+	{ObjectID: "10020000000000001", Type: "answer", Content: "Implement plugin.Storage and register the plugin in init.", QuestionID: "10010000000000001", UserID: "2", Score: 5, Created: 1700000300, Active: 1700000300, HasAccepted: true},
+	{ObjectID: "10010000000000002", Type: "question", Title: "Search results are empty", Content: "The search plugin finds nothing after the import.", Tags: []string{"search"}, UserID: "2", Views: 7, Created: 1700000600, Active: 1700000600},
+	{ObjectID: "10010000000000003", Type: "question", Title: "Which cache should I use?", Content: "Redis or the in-memory cache of the plugin?", Tags: []string{"cache"}, UserID: "3", Views: 12, Score: 1, Created: 1700000900, Active: 1700000900},
+}
+
+func searchExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	search := p.(searcher)
+	return []*exercise{
+		{
+			Title: "Index the sample posts",
+			run: func(ctx *gin.Context) (any, error) {
+				for _, content := range samplePosts {
+					if err := search.UpdateContent(ctx.Request.Context(), content); err != nil {
+						return nil, fmt.Errorf("index %s: %w", content.ObjectID, err)
+					}
+				}
+				return samplePosts, nil
+			},
+		},
+		{
+			Title: "Search",
+			Fields: []*field{
+				{Name: "words", Label: "Words", Type: "text", Value: "plugin"},
+				{Name: "page", Label: "Page", Type: "text", Value: "1"},
+			},
+			run: func(ctx *gin.Context) (any, error) {
+				page, err := strconv.Atoi(ctx.PostForm("page"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid page: %w", err)
+				}
+				cond := &plugin.SearchBasicCond{Words: strings.Fields(ctx.PostForm("words")), Page: page, PageSize: 10}
+				results, total, err := search.SearchContents(ctx.Request.Context(), cond)
+				return gin.H{"total": total, "results": results}, err
+			},
+		},
+	}, nil
+}
+
+func userCenterExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	uc := p.(userCenter)
+	return []*exercise{
+		{
+			Title:  "Log in",
+			Method: http.MethodGet,
+			Path:   testkit.UnAuthPrefix + "/user-center/login/callback",
+			run: func(ctx *gin.Context) (any, error) {
+				return uc.LoginCallback(ctx)
+			},
+		},
+		{
+			Title:  "Look up a user",
+			Fields: []*field{{Name: "external_id", Label: "External ID", Type: "text", Value: "user-1"}},
+			run: func(ctx *gin.Context) (any, error) {
+				externalID := ctx.PostForm("external_id")
+				userInfo, err := uc.UserInfo(externalID)
+				return gin.H{"user": userInfo, "status": uc.UserStatus(externalID)}, err
+			},
+		},
+	}, nil
+}
+
+func notificationExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	n := p.(plugin.Notification)
+	types := []string{
+		"notification.action.answer_the_question",
+		"notification.action.comment_question",
+		"notification.action.accept_answer",
+		"notification.action.new_question",
+	}
+	return []*exercise{{
+		Title: "Send a notification",
+		Fields: []*field{
+			{Name: "type", Label: "Type", Type: "select", Value: types[0], Options: types},
+			{Name: "receiver", Label: "Receiver external ID", Type: "text", Value: "user-1"},
+			{Name: "title", Label: "Question title", Type: "text", Value: samplePosts[0].Title},
+		},
+		run: func(ctx *gin.Context) (any, error) {
+			question := s.baseURL + "/questions/" + samplePosts[0].ObjectID
+			msg := plugin.NotificationMessage{
+				Type:                   plugin.NotificationType(ctx.PostForm("type")),
+				ReceiverUserID:         "1",
+				ReceiverLang:           "en_US",
+				ReceiverExternalID:     ctx.PostForm("receiver"),
+				TriggerUserDisplayName: "Alice",
+				TriggerUserUrl:         s.baseURL + "/users/alice",
+				QuestionTitle:          ctx.PostForm("title"),
+				QuestionUrl:            question,
+				AnswerUrl:              question + "/" + samplePosts[1].ObjectID,
+				QuestionTags:           strings.Join(samplePosts[0].Tags, ","),
+			}
+			n.Notify(msg)
+			return msg, nil
+		},
+	}}, nil
+}
+
+func reviewerExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	r := p.(plugin.Reviewer)
+	return []*exercise{{
+		Title: "Review a post",
+		Fields: []*field{
+			{Name: "title", Label: "Title", Type: "text", Value: samplePosts[0].Title},
+			{Name: "content", Label: "Content", Type: "textarea", Value: samplePosts[0].Content},
+			{Name: "tags", Label: "Tags", Type: "text", Value: strings.Join(samplePosts[0].Tags, ",")},
+		},
+		run: func(ctx *gin.Context) (any, error) {
+			content := &plugin.ReviewContent{
+				ObjectType: "question",
+				Title:      ctx.PostForm("title"),
+				Content:    ctx.PostForm("content"),
+				Tags:       strings.FieldsFunc(ctx.PostForm("tags"), func(r rune) bool { return r == ',' }),
+				Author:     plugin.ReviewContentAuthor{Rank: 1, Role: 1},
+				IP:         ctx.ClientIP(),
+				UserAgent:  ctx.Request.UserAgent(),
+			}
+			return r.Review(content), nil
+		},
+	}}, nil
+}
+
+func filterExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	f := p.(plugin.Filter)
+	return []*exercise{{
+		Title: "Filter a text",
+		Fields: []*field{
+			{Name: "text", Label: "Text", Type: "textarea", Value: samplePosts[0].Content},
+		},
+		run: func(ctx *gin.Context) (any, error) {
+			if err := f.FilterText(ctx.PostForm("text")); err != nil {
+				return gin.H{"pass": false, "reason": err.Error()}, nil
+			}
+			return gin.H{"pass": true}, nil
+		},
+	}}, nil
+}
+
+// importedQuestions records the questions an importer adds
+type importedQuestions struct {
+	mu        sync.Mutex
+	questions []plugin.QuestionImporterInfo
+}
+
+func (q *importedQuestions) AddQuestion(ctx context.Context, questionInfo plugin.QuestionImporterInfo) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.questions = append(q.questions, questionInfo)
+	return nil
+}
+
+func importerExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	imported := &importedQuestions{}
+	p.(plugin.Importer).RegisterImporterFunc(context.Background(), imported)
+	return []*exercise{{
+		Title: "Show the imported questions",
+		run: func(ctx *gin.Context) (any, error) {
+			imported.mu.Lock()
+			defer imported.mu.Unlock()
+			return gin.H{"count": len(imported.questions), "questions": imported.questions}, nil
+		},
+	}}, []string{"Questions the importer adds are kept in memory, start the import the way the plugin offers it"}
+}
+
+func captchaExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	c := p.(plugin.Captcha)
+	code := &field{Name: "code", Label: "Code Answer keeps", Type: "text"}
+	return []*exercise{
+		{
+			Title: "Create a captcha",
+			run: func(ctx *gin.Context) (any, error) {
+				image, value := c.Create()
+				code.Value = value
+				return withImage{Image: image, Output: gin.H{"code": value, "config": json.RawMessage(c.GetConfig())}}, nil
+			},
+		},
+		{
+			Title:  "Verify an answer",
+			Fields: []*field{code, {Name: "input", Label: "Answer", Type: "text"}},
+			run: func(ctx *gin.Context) (any, error) {
+				return gin.H{"pass": c.Verify(ctx.PostForm("code"), ctx.PostForm("input"))}, nil
+			},
+		},
+	}, nil
+}
+
+func embedExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	e := p.(plugin.Embed)
+	return []*exercise{{
+		Title: "List the embed settings",
+		run: func(ctx *gin.Context) (any, error) {
+			return e.GetEmbedConfigs(ctx)
+		},
+	}}, nil
+}
+
+func kvStorageExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	return nil, []string{"The KV storage needs Answer's database, SetOperator isn't called. Test the code using it with testkit.NewKV."}
+}
+
+// agentExercises mounts the routes of the plugin on Answer's paths, without
+// Answer's authentication
+func agentExercises(s *Server, p plugin.Base) ([]*exercise, []string) {
+	agent := p.(plugin.Agent)
+	before := map[string]bool{}
+	for _, route := range s.engine.Routes() {
+		before[route.Method+" "+route.Path] = true
+	}
+	agent.RegisterUnAuthRouter(s.engine.Group(testkit.UnAuthPrefix))
+	agent.RegisterAuthUserRouter(s.engine.Group(testkit.AuthUserPrefix))
+	agent.RegisterAuthAdminRouter(s.engine.Group(testkit.AdminPrefix))
+
+	var notes []string
+	for _, route := range s.engine.Routes() {
+		if !before[route.Method+" "+route.Path] {
+			notes = append(notes, "Route "+route.Method+" "+s.baseURL+route.Path)
+		}
+	}
+	return nil, notes
+}
@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package devserver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idpPrefix is where the fake identity provider is served
+const idpPrefix = "/idp"
+
+// idpUser is a user of the fake identity provider. Its user info carries the
+// fields of the common providers, so connectors find the ones they read.
+type idpUser struct {
+	ID    string
+	Login string
+	Name  string
+	Email string
+}
+
+func (u *idpUser) userInfo() gin.H {
+	return gin.H{
+		"sub":                u.ID,
+		"id":                 u.ID,
+		"login":              u.Login,
+		"preferred_username": u.Login,
+		"name":               u.Name,
+		"email":              u.Email,
+		"email_verified":     true,
+		"picture":            "",
+		"avatar_url":         "",
+	}
+}
+
+var idpUsers = []*idpUser{
+	{ID: "1001", Login: "alice", Name: "Alice Liddell", Email: "alice@example.com"},
+	{ID: "1002", Login: "bob", Name: "Bob Builder", Email: "bob@example.com"},
+}
+
+// identityProvider is a fake OAuth 2.0 provider with the authorization code
+// flow. Codes and tokens are single use and kept in memory.
+type identityProvider struct {
+	mu     sync.Mutex
+	codes  map[string]*idpUser
+	tokens map[string]*idpUser
+}
+
+func newIdentityProvider() *identityProvider {
+	return &identityProvider{codes: map[string]*idpUser{}, tokens: map[string]*idpUser{}}
+}
+
+var authorizePage = template.Must(template.New("authorize").Parse(`<!DOCTYPE html>
+<html><head><title>Fake identity provider</title></head>
+<body style="font-family: sans-serif; max-width: 30em; margin: 4em auto">
+<h1>Sign in</h1>
+<p>{{.ClientID}} asks who you are.</p>
+{{range .Users}}<form method="post">
+<input type="hidden" name="redirect_uri" value="{{$.RedirectURI}}">
+<input type="hidden" name="state" value="{{$.State}}">
+<input type="hidden" name="login" value="{{.Login}}">
+<p><button type="submit">Continue as {{.Name}} ({{.Email}})</button></p>
+</form>{{end}}
+</body></html>
+`))
+
+func (idp *identityProvider) register(r *gin.RouterGroup) {
+	r.GET("/authorize", idp.authorize)
+	r.POST("/authorize", idp.authorize)
+	r.POST("/token", idp.token)
+	r.GET("/userinfo", idp.userInfo)
+}
+
+// authorize asks which user signs in, unless the login parameter says it,
+// and sends the user back with a code
+func (idp *identityProvider) authorize(ctx *gin.Context) {
+	redirectURI, err := url.Parse(ctx.Request.FormValue("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		ctx.String(http.StatusBadRequest, "redirect_uri must be an absolute URL")
+		return
+	}
+	login := ctx.Request.FormValue("login")
+	if login == "" {
+		ctx.Header("Content-Type", "text/html; charset=utf-8")
+		_ = authorizePage.Execute(ctx.Writer, gin.H{
+			"ClientID":    ctx.Query("client_id"),
+			"RedirectURI": redirectURI.String(),
+			"State":       ctx.Query("state"),
+			"Users":       idpUsers,
+		})
+		return
+	}
+
+	query := redirectURI.Query()
+	if state := ctx.Request.FormValue("state"); state != "" {
+		query.Set("state", state)
+	}
+	user := findIdPUser(login)
+	if user == nil {
+		query.Set("error", "access_denied")
+	} else {
+		code := randomToken()
+		idp.mu.Lock()
+		idp.codes[code] = user
+		idp.mu.Unlock()
+		query.Set("code", code)
+	}
+	redirectURI.RawQuery = query.Encode()
+	ctx.Redirect(http.StatusFound, redirectURI.String())
+}
+
+// token exchanges a code for an access token, any client is accepted
+func (idp *identityProvider) token(ctx *gin.Context) {
+	code := ctx.PostForm("code")
+	idp.mu.Lock()
+	user := idp.codes[code]
+	delete(idp.codes, code)
+	token := ""
+	if user != nil {
+		token = randomToken()
+		idp.tokens[token] = user
+	}
+	idp.mu.Unlock()
+
+	if user == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"access_token": token, "token_type": "Bearer", "expires_in": 3600})
+}
+
+func (idp *identityProvider) userInfo(ctx *gin.Context) {
+	token := strings.TrimSpace(strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer"))
+	if token == "" {
+		token = ctx.Query("access_token")
+	}
+	idp.mu.Lock()
+	user := idp.tokens[token]
+	idp.mu.Unlock()
+
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		return
+	}
+	ctx.JSON(http.StatusOK, user.userInfo())
+}
+
+func findIdPUser(login string) *idpUser {
+	for _, user := range idpUsers {
+		if user.Login == login {
+			return user
+		}
+	}
+	return nil
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
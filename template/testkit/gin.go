@@ -0,0 +1,215 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package testkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+// The paths Answer mounts the plugin routers on
+const (
+	UnAuthPrefix   = "/answer/api/v1"
+	AuthUserPrefix = "/answer/api/v1"
+	AdminPrefix    = "/answer/admin/api"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// RequestOption sets up a request built by NewRequest
+type RequestOption func(t testing.TB, req *http.Request)
+
+// NewRequest returns a request as a client would send it to Answer, target is
+// a path with an optional query, e.g. "/answer/api/v1/connector/login/github?code=1"
+func NewRequest(t testing.TB, method, target string, opts ...RequestOption) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	for _, opt := range opts {
+		opt(t, req)
+	}
+	return req
+}
+
+func setBody(req *http.Request, contentType string, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", contentType)
+}
+
+// WithJSON sends v as the JSON body
+func WithJSON(v any) RequestOption {
+	return func(t testing.TB, req *http.Request) {
+		t.Helper()
+		body, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("testkit: encode JSON body: %v", err)
+		}
+		setBody(req, "application/json", body)
+	}
+}
+
+// WithBody sends body as it is, e.g. to test how a handler rejects a malformed one
+func WithBody(contentType string, body []byte) RequestOption {
+	return func(t testing.TB, req *http.Request) {
+		setBody(req, contentType, body)
+	}
+}
+
+// WithForm sends values as a URL-encoded form
+func WithForm(values url.Values) RequestOption {
+	return func(t testing.TB, req *http.Request) {
+		setBody(req, "application/x-www-form-urlencoded", []byte(values.Encode()))
+	}
+}
+
+// WithQuery adds values to the query of the target
+func WithQuery(values url.Values) RequestOption {
+	return func(t testing.TB, req *http.Request) {
+		query := req.URL.Query()
+		for key, vs := range values {
+			for _, v := range vs {
+				query.Add(key, v)
+			}
+		}
+		req.URL.RawQuery = query.Encode()
+		req.RequestURI = req.URL.RequestURI()
+	}
+}
+
+// WithHeader sets a request header
+func WithHeader(key, value string) RequestOption {
+	return func(t testing.TB, req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
+// WithCookie adds a cookie, e.g. the state cookie an OAuth callback checks
+func WithCookie(cookie *http.Cookie) RequestOption {
+	return func(t testing.TB, req *http.Request) {
+		req.AddCookie(cookie)
+	}
+}
+
+// File is a file of a multipart upload
+type File struct {
+	// Field is the form field, Answer uploads under "file"
+	Field string
+	Name  string
+	// ContentType defaults to application/octet-stream
+	ContentType string
+	Content     []byte
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// WithMultipart sends fields and files as a multipart form, the way Answer's
+// editor uploads images and attachments
+func WithMultipart(fields map[string]string, files ...File) RequestOption {
+	return func(t testing.TB, req *http.Request) {
+		t.Helper()
+		body := &bytes.Buffer{}
+		w := multipart.NewWriter(body)
+		for name, value := range fields {
+			if err := w.WriteField(name, value); err != nil {
+				t.Fatalf("testkit: write form field %s: %v", name, err)
+			}
+		}
+		for _, file := range files {
+			contentType := file.ContentType
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+				quoteEscaper.Replace(file.Field), quoteEscaper.Replace(file.Name)))
+			header.Set("Content-Type", contentType)
+			part, err := w.CreatePart(header)
+			if err == nil {
+				_, err = part.Write(file.Content)
+			}
+			if err != nil {
+				t.Fatalf("testkit: write file %s: %v", file.Name, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("testkit: close multipart body: %v", err)
+		}
+		setBody(req, w.FormDataContentType(), body.Bytes())
+	}
+}
+
+// NewGinContext returns the context Answer would pass to a plugin handling req,
+// with the given route parameters, and the recorder of the response the plugin
+// writes. Gin sends the status with the body, call ctx.Writer.WriteHeaderNow()
+// before checking a response without one, e.g. a redirect.
+func NewGinContext(t testing.TB, req *http.Request, params ...gin.Param) (*plugin.GinContext, *httptest.ResponseRecorder) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = req
+	ctx.Params = params
+	return ctx, rec
+}
+
+// Agent is a plugin that registers routes, e.g. a plugin.Agent
+type Agent interface {
+	RegisterUnAuthRouter(r *gin.RouterGroup)
+	RegisterAuthUserRouter(r *gin.RouterGroup)
+	RegisterAuthAdminRouter(r *gin.RouterGroup)
+}
+
+// NewRouter mounts the routes of agent on the paths Answer uses, without
+// Answer's authentication
+func NewRouter(agent Agent) *gin.Engine {
+	engine := gin.New()
+	agent.RegisterUnAuthRouter(engine.Group(UnAuthPrefix))
+	agent.RegisterAuthUserRouter(engine.Group(AuthUserPrefix))
+	agent.RegisterAuthAdminRouter(engine.Group(AdminPrefix))
+	return engine
+}
+
+// Serve sends req to handler and returns the response
+func Serve(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// DecodeJSON decodes the JSON body of a response into v
+func DecodeJSON(t testing.TB, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("testkit: decode response %q: %v", rec.Body.String(), err)
+	}
+}
@@ -0,0 +1,8 @@
+module {{testkit_module}}
+
+go 1.23.0
+
+require (
+	github.com/apache/answer {{answer_version}}
+	github.com/gin-gonic/gin v1.9.1
+)
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package testkit
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/apache/answer/plugin"
+)
+
+// KV is an in-memory KV storage with the methods of Answer's *plugin.KVOperator,
+// to test code written against an interface of them, e.g. the KVStore of the
+// KV repository. It checks the parameters and returns the errors Answer does.
+// Transactions are serialized and work on a copy of the data that replaces it
+// on commit.
+type KV struct {
+	mu   sync.Mutex
+	txMu sync.Mutex
+	data map[string]map[string]string
+	inTx bool
+}
+
+// NewKV returns an empty KV storage
+func NewKV() *KV {
+	return &KV{data: map[string]map[string]string{}}
+}
+
+func (kv *KV) Get(ctx context.Context, params plugin.KVParams) (string, error) {
+	if params.Key == "" {
+		return "", plugin.ErrKVKeyEmpty
+	}
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	value, ok := kv.data[params.Group][params.Key]
+	if !ok {
+		return "", plugin.ErrKVKeyNotFound
+	}
+	return value, nil
+}
+
+func (kv *KV) Set(ctx context.Context, params plugin.KVParams) error {
+	if params.Key == "" {
+		return plugin.ErrKVKeyEmpty
+	}
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if kv.data[params.Group] == nil {
+		kv.data[params.Group] = map[string]string{}
+	}
+	kv.data[params.Group][params.Key] = params.Value
+	return nil
+}
+
+// Del deletes a key, or the whole group when no key is given
+func (kv *KV) Del(ctx context.Context, params plugin.KVParams) error {
+	if params.Key == "" && params.Group == "" {
+		return plugin.ErrKVKeyAndGroupEmpty
+	}
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if params.Key == "" {
+		delete(kv.data, params.Group)
+		return nil
+	}
+	delete(kv.data[params.Group], params.Key)
+	return nil
+}
+
+// GetByGroup returns a page of the group, 10 keys unless PageSize says otherwise.
+// It pages through the keys in reverse order, code must not rely on an order.
+func (kv *KV) GetByGroup(ctx context.Context, params plugin.KVParams) (map[string]string, error) {
+	if params.Group == "" {
+		return nil, plugin.ErrKVGroupEmpty
+	}
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	keys := make([]string, 0, len(kv.data[params.Group]))
+	for key := range kv.data[params.Group] {
+		keys = append(keys, key)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+
+	values := map[string]string{}
+	for i := (page - 1) * pageSize; i < len(keys) && len(values) < pageSize; i++ {
+		values[keys[i]] = kv.data[params.Group][keys[i]]
+	}
+	return values, nil
+}
+
+// Tx runs fn in a transaction, its changes are kept only if fn returns no error.
+// Nested transactions join the outer one.
+func (kv *KV) Tx(ctx context.Context, fn func(ctx context.Context, tx *KV) error) error {
+	if kv.inTx {
+		return fn(ctx, kv)
+	}
+	kv.txMu.Lock()
+	defer kv.txMu.Unlock()
+
+	tx := &KV{data: kv.Data(), inTx: true}
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	kv.data = tx.data
+	return nil
+}
+
+// Data returns a copy of the stored values by group and key
+func (kv *KV) Data() map[string]map[string]string {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	data := make(map[string]map[string]string, len(kv.data))
+	for group, values := range kv.data {
+		data[group] = make(map[string]string, len(values))
+		for key, value := range values {
+			data[group][key] = value
+		}
+	}
+	return data
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package testkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+func TestKV(t *testing.T) {
+	ctx := context.Background()
+	kv := NewKV()
+	for i := 0; i < 15; i++ {
+		if err := kv.Set(ctx, plugin.KVParams{Group: "g", Key: fmt.Sprintf("k%02d", i), Value: "v"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := kv.Get(ctx, plugin.KVParams{Group: "g", Key: "missing"}); !errors.Is(err, plugin.ErrKVKeyNotFound) {
+		t.Errorf("Get of a missing key: %v, want ErrKVKeyNotFound", err)
+	}
+	if err := kv.Set(ctx, plugin.KVParams{Group: "g"}); !errors.Is(err, plugin.ErrKVKeyEmpty) {
+		t.Errorf("Set without a key: %v, want ErrKVKeyEmpty", err)
+	}
+
+	first, _ := kv.GetByGroup(ctx, plugin.KVParams{Group: "g", Page: 1})
+	second, _ := kv.GetByGroup(ctx, plugin.KVParams{Group: "g", Page: 2})
+	if len(first) != 10 || len(second) != 5 {
+		t.Errorf("pages of %d and %d keys, want 10 and 5", len(first), len(second))
+	}
+
+	failed := errors.New("rollback")
+	err := kv.Tx(ctx, func(ctx context.Context, tx *KV) error {
+		if err := tx.Del(ctx, plugin.KVParams{Group: "g"}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) || len(kv.Data()["g"]) != 15 {
+		t.Errorf("failed transaction: %v, %d keys left, want the group kept", err, len(kv.Data()["g"]))
+	}
+	err = kv.Tx(ctx, func(ctx context.Context, tx *KV) error {
+		return tx.Del(ctx, plugin.KVParams{Group: "g"})
+	})
+	if err != nil || len(kv.Data()["g"]) != 0 {
+		t.Errorf("transaction: %v, %d keys left, want the group deleted", err, len(kv.Data()["g"]))
+	}
+}
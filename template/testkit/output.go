@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package testkit
+
+import (
+	"log"
+	"os"
+	"testing"
+)
+
+// Output is what the code under test printed or logged
+type Output struct {
+	file *os.File
+}
+
+// CaptureOutput redirects os.Stdout, os.Stderr and the standard logger to
+// Output until the test ends. Tests that capture output must not run in parallel.
+func CaptureOutput(t testing.TB) *Output {
+	t.Helper()
+	file, err := os.CreateTemp(t.TempDir(), "output")
+	if err != nil {
+		t.Fatalf("testkit: capture output: %v", err)
+	}
+	stdout, stderr, logOutput, logFlags := os.Stdout, os.Stderr, log.Writer(), log.Flags()
+	os.Stdout, os.Stderr = file, file
+	log.SetOutput(file)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		log.SetOutput(logOutput)
+		log.SetFlags(logFlags)
+		file.Close()
+	})
+	return &Output{file: file}
+}
+
+// String returns everything captured so far
+func (o *Output) String() string {
+	content, err := os.ReadFile(o.file.Name())
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package testkit helps testing Answer plugins without an Answer instance: it
+// builds the *plugin.GinContext Answer passes to connectors, storages and user
+// centers from httptest requests, mounts a plugin's routes like Answer does,
+// keeps KV storage in memory, translates without Answer's translation files
+// and captures what the code under test logs.
+package testkit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+)
+
+// Translate renders tr for an English-speaking user. Answer's translation files
+// are not loaded in tests, so the result is the translation key, e.g. the
+// i18n.InfoName the translator was made from.
+func Translate(t testing.TB, tr plugin.Translator) string {
+	t.Helper()
+	ctx, _ := NewGinContext(t, NewRequest(t, http.MethodGet, "/", WithHeader("Accept-Language", "en_US")))
+	return tr.Translate(ctx)
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package testkit
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGinContextMultipart(t *testing.T) {
+	req := NewRequest(t, http.MethodPost, "/answer/api/v1/file",
+		WithMultipart(map[string]string{"source": "post"}, File{Field: "file", Name: "cat.png", ContentType: "image/png", Content: []byte("png")}),
+		WithCookie(&http.Cookie{Name: "session", Value: "s1"}),
+	)
+	ctx, _ := NewGinContext(t, req)
+
+	if source := ctx.PostForm("source"); source != "post" {
+		t.Errorf("form field source = %q, want post", source)
+	}
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Filename != "cat.png" || header.Size != 3 || header.Header.Get("Content-Type") != "image/png" {
+		t.Errorf("file = %s, %d bytes, %s", header.Filename, header.Size, header.Header.Get("Content-Type"))
+	}
+	file, err := header.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if content, _ := io.ReadAll(file); string(content) != "png" {
+		t.Errorf("file content = %q", content)
+	}
+	if session, err := ctx.Cookie("session"); err != nil || session != "s1" {
+		t.Errorf("cookie session = %q, %v", session, err)
+	}
+}
+
+func TestGinContextQueryParamsAndJSON(t *testing.T) {
+	req := NewRequest(t, http.MethodPut, "/cursors/github?page=2",
+		WithQuery(url.Values{"state": {"xyz"}}),
+		WithJSON(map[string]string{"cursor": "c1"}),
+	)
+	ctx, rec := NewGinContext(t, req, gin.Param{Key: "source", Value: "github"})
+
+	body := struct {
+		Cursor string `json:"cursor"`
+	}{}
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.Cursor != "c1" {
+		t.Errorf("body = %+v, %v", body, err)
+	}
+	if ctx.Param("source") != "github" || ctx.Query("page") != "2" || ctx.Query("state") != "xyz" {
+		t.Errorf("param %q, query page %q, state %q", ctx.Param("source"), ctx.Query("page"), ctx.Query("state"))
+	}
+
+	ctx.Redirect(http.StatusFound, "https://idp.example.com/authorize")
+	ctx.Writer.WriteHeaderNow()
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://idp.example.com/authorize" {
+		t.Errorf("response = %d to %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+type agent struct{}
+
+func (agent) RegisterUnAuthRouter(r *gin.RouterGroup) {
+	r.GET("/hello", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"router": "public"}) })
+}
+
+func (agent) RegisterAuthUserRouter(r *gin.RouterGroup) {}
+
+func (agent) RegisterAuthAdminRouter(r *gin.RouterGroup) {
+	r.GET("/hello", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"router": "admin"}) })
+}
+
+func TestRouter(t *testing.T) {
+	router := NewRouter(agent{})
+	for prefix, want := range map[string]string{UnAuthPrefix: "public", AdminPrefix: "admin"} {
+		rec := Serve(router, NewRequest(t, http.MethodGet, prefix+"/hello"))
+		body := map[string]string{}
+		DecodeJSON(t, rec, &body)
+		if rec.Code != http.StatusOK || body["router"] != want {
+			t.Errorf("GET %s/hello = %d %v, want the %s router", prefix, rec.Code, body, want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := Translate(t, plugin.MakeTranslator("plugin.example.info.name")); got != "plugin.example.info.name" {
+		t.Errorf("Translate = %q, want the key", got)
+	}
+}
+
+func TestCaptureOutput(t *testing.T) {
+	output := CaptureOutput(t)
+	fmt.Println("printed")
+	log.Printf("logged")
+	if got := output.String(); got != "printed\nlogged\n" {
+		t.Errorf("output = %q", got)
+	}
+}
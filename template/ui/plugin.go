@@ -21,9 +21,10 @@ package {{package_name}}
 
 import (
 	"embed"
-	"github.com/apache/answer-plugins/{{plugin_name}}/i18n"
 	"github.com/apache/answer-plugins/util"
 	"github.com/apache/answer/plugin"
+
+	"{{module_path}}/{{plugin_name}}/i18n"
 )
 
 //go:embed info.yaml
@@ -49,4 +50,3 @@ func (d {{plugin_display_name}}Plugin) Info() plugin.Info {
 		Link:        info.Link,
 	}
 }
-
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// verifyTimeout bounds each check, Answer calls Verify without a context
+const verifyTimeout = 5 * time.Second
+
+// Verifier checks the code a user entered against a captcha. captcha is what Create
+// returned for Answer to keep, code is the user's input. An error means the check
+// itself failed, e.g. the captcha service couldn't be reached.
+type Verifier interface {
+	Verify(ctx context.Context, captcha, code string) (bool, error)
+}
+
+// verify runs a check for Answer's Verify. Errors are logged and fail closed, a captcha
+// that can't be checked doesn't let anyone through.
+func verify(v Verifier, captcha, code string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+
+	pass, err := v.Verify(ctx, captcha, code)
+	if err != nil {
+		log.Printf("{{plugin_slug_name}}: captcha check failed: %v", err)
+		return false
+	}
+	return pass
+}
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import { FC, useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+
+export interface EmbedProps {
+  url: string;
+}
+
+interface EmbedMetadata {
+  type: string;
+  provider: string;
+  url: string;
+  title?: string;
+  author_name?: string;
+  thumbnail_url?: string;
+  html?: string;
+  width?: number;
+  height?: number;
+}
+
+const RESOLVE_API = '/answer/api/v1/{{plugin_slug_name}}/resolve';
+
+const Component: FC<EmbedProps> = ({ url }) => {
+  const { t } = useTranslation('plugin', {
+    keyPrefix: '{{plugin_slug_name}}.frontend',
+  });
+  const [metadata, setMetadata] = useState<EmbedMetadata | null>(null);
+  const [failed, setFailed] = useState(false);
+
+  useEffect(() => {
+    const controller = new AbortController();
+    setMetadata(null);
+    setFailed(false);
+
+    fetch(`${RESOLVE_API}?url=${encodeURIComponent(url)}`, {
+      signal: controller.signal,
+    })
+      .then((resp) => (resp.ok ? resp.json() : Promise.reject(resp.status)))
+      .then((data: EmbedMetadata) => setMetadata(data))
+      .catch(() => {
+        if (!controller.signal.aborted) {
+          setFailed(true);
+        }
+      });
+
+    return () => controller.abort();
+  }, [url]);
+
+  const link = (
+    <a href={url} target="_blank" rel="noopener noreferrer nofollow">
+      {metadata?.title || (failed ? t('unavailable') : t('open_link'))}
+    </a>
+  );
+
+  if (failed) {
+    return link;
+  }
+  if (!metadata) {
+    return <div className="text-secondary small">{t('loading')}</div>;
+  }
+
+  // Provider markup is untrusted: render it in an iframe without same-origin access
+  if (metadata.html) {
+    return (
+      <iframe
+        title={metadata.title || metadata.provider}
+        srcDoc={metadata.html}
+        sandbox="allow-scripts allow-popups allow-presentation"
+        className="border-0 w-100"
+        style={{ height: metadata.height || 360, maxWidth: metadata.width }}
+      />
+    );
+  }
+
+  return (
+    <div className="card">
+      {metadata.thumbnail_url && (
+        <img className="card-img-top" src={metadata.thumbnail_url} alt="" />
+      )}
+      <div className="card-body">
+        {link}
+        {metadata.author_name && (
+          <div className="text-secondary small">{metadata.author_name}</div>
+        )}
+      </div>
+    </div>
+  );
+};
+
+export default Component;
@@ -17,22 +17,11 @@
  * under the License.
  */
 
-import { FC } from 'react';
-import { useTranslation } from 'react-i18next';
+import en_US from './en_US.yaml';
+import zh_CN from './zh_CN.yaml';
 
-const Component: FC = () => {
-  const { t } = useTranslation('plugin', {
-    keyPrefix: '{{plugin_slug_name}}.frontend',
-  });
-
-  return (
-    <div>
-      <h2>{t('hello_world')}</h2>
-      <p>This is a Hello World Captcha Plugin!</p>
-      <p>Implement your captcha logic here.</p>
-    </div>
-  );
+export default {
+  en_US,
+  zh_CN,
 };
 
-export default Component;
-
@@ -0,0 +1,34 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package i18n
+
+const (
+	InfoName                  = "plugin.{{info_slug_name}}.backend.info.name"
+	InfoDescription           = "plugin.{{info_slug_name}}.backend.info.description"
+	ConfigProviderLabel       = "plugin.{{info_slug_name}}.backend.config.provider.label"
+	ConfigProviderYouTube     = "plugin.{{info_slug_name}}.backend.config.youtube.title"
+	ConfigProviderVimeo       = "plugin.{{info_slug_name}}.backend.config.vimeo.title"
+	ConfigProviderFigma       = "plugin.{{info_slug_name}}.backend.config.figma.title"
+	ConfigProviderGist        = "plugin.{{info_slug_name}}.backend.config.gist.title"
+	ConfigProviderCodePen     = "plugin.{{info_slug_name}}.backend.config.codepen.title"
+	ConfigProviderTwitter     = "plugin.{{info_slug_name}}.backend.config.twitter.title"
+	ConfigCacheTTLTitle       = "plugin.{{info_slug_name}}.backend.config.cache_ttl_minutes.title"
+	ConfigCacheTTLDescription = "plugin.{{info_slug_name}}.backend.config.cache_ttl_minutes.description"
+)
@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+//section:fields
+
+	mu       sync.RWMutex
+	resolver *Resolver
+
+//section:config
+	// Providers enables providers by name, each has a switch in the plugin settings
+	Providers map[string]bool `json:"providers"`
+	// CacheTTLMinutes is how long resolved metadata is cached
+	CacheTTLMinutes json.Number `json:"cache_ttl_minutes"`
+
+//section:defaults
+			Providers:       defaultProviders(),
+			CacheTTLMinutes: "60",
+
+//section:init
+		resolver: NewResolver(defaultProviders(), defaultCacheTTL),
+
+//section:body
+var _ plugin.Embed = (*{{plugin_display_name}})(nil)
+
+const (
+	defaultCacheTTL = time.Hour
+	resolveTimeout  = 5 * time.Second
+)
+
+// defaultProviders enables every built-in provider
+func defaultProviders() map[string]bool {
+	enabled := make(map[string]bool, len(Providers))
+	for _, p := range Providers {
+		enabled[p.Name] = true
+	}
+	return enabled
+}
+
+func (e *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	fields := make([]plugin.ConfigField, 0, len(Providers)+1)
+	for _, p := range Providers {
+		fields = append(fields, plugin.ConfigField{
+			Name:  p.Name,
+			Type:  plugin.ConfigTypeSwitch,
+			Title: plugin.MakeTranslator(p.TitleKey),
+			UIOptions: plugin.ConfigFieldUIOptions{
+				Label: plugin.MakeTranslator(i18n.ConfigProviderLabel),
+			},
+			Value: e.Config.Providers[p.Name],
+		})
+	}
+	fields = append(fields, plugin.ConfigField{
+		Name:        "cache_ttl_minutes",
+		Type:        plugin.ConfigTypeInput,
+		Title:       plugin.MakeTranslator(i18n.ConfigCacheTTLTitle),
+		Description: plugin.MakeTranslator(i18n.ConfigCacheTTLDescription),
+		UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeNumber},
+		Value:       e.Config.CacheTTLMinutes.String(),
+	})
+	return fields
+}
+
+// ConfigReceiver reads one switch per provider, as Answer sends them, or the
+// providers of a saved config. Unknown providers are ignored.
+func (e *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+	switches := map[string]any{}
+	if err := json.Unmarshal(config, &switches); err != nil {
+		return err
+	}
+	saved := c.Providers
+	c.Providers = map[string]bool{}
+	for _, p := range Providers {
+		enabled, ok := switches[p.Name].(bool)
+		if !ok {
+			enabled = saved[p.Name]
+		}
+		c.Providers[p.Name] = enabled
+	}
+
+	ttl := defaultCacheTTL
+	if c.CacheTTLMinutes != "" {
+		minutes, err := c.CacheTTLMinutes.Float64()
+		if err != nil || minutes < 0 {
+			return fmt.Errorf("invalid cache TTL %q", c.CacheTTLMinutes)
+		}
+		ttl = time.Duration(minutes * float64(time.Minute))
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Config = c
+	e.resolver = NewResolver(c.Providers, ttl)
+	return nil
+}
+
+// GetEmbedConfigs tells Answer which platforms are enabled
+func (e *{{plugin_display_name}}) GetEmbedConfigs(ctx *gin.Context) (embedConfigs []*plugin.EmbedConfig, err error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, p := range Providers {
+		embedConfigs = append(embedConfigs, &plugin.EmbedConfig{
+			Platform: p.Name,
+			Enable:   e.Config.Providers[p.Name],
+		})
+	}
+	return embedConfigs, nil
+}
+
+// RegisterUnAuthRouter exposes the resolver to the UI component:
+//
+//	GET /{{plugin_slug_name}}/resolve?url=...
+func (e *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {
+	router.GET("/{{plugin_slug_name}}/resolve", e.resolve)
+}
+
+func (e *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {}
+
+func (e *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {}
+
+func (e *{{plugin_display_name}}) resolve(ctx *gin.Context) {
+	e.mu.RLock()
+	resolver := e.resolver
+	e.mu.RUnlock()
+
+	c, cancel := context.WithTimeout(ctx.Request.Context(), resolveTimeout)
+	defer cancel()
+	metadata, err := resolver.Resolve(c, ctx.Query("url"))
+	switch {
+	case errors.Is(err, ErrNotAllowed):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusOK, metadata)
+	}
+}
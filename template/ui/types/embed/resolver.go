@@ -0,0 +1,281 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"{{module_path}}/{{plugin_name}}/i18n"
+)
+
+const (
+	maxCacheEntries  = 1000
+	maxOEmbedPayload = 1 << 20
+	// oEmbedTimeout bounds a provider request, whatever the caller's context allows
+	oEmbedTimeout = 5 * time.Second
+)
+
+// ErrNotAllowed is returned for URLs that no enabled provider handles
+var ErrNotAllowed = errors.New("no enabled provider handles the URL")
+
+// Provider resolves the URLs of one platform
+type Provider struct {
+	Name     string
+	TitleKey string
+	// Patterns match the URLs the provider handles
+	Patterns []*regexp.Regexp
+	// OEmbedEndpoint is queried with ?url=...&format=json
+	OEmbedEndpoint string
+	// Build creates the metadata locally for platforms without an oEmbed endpoint,
+	// it receives the submatches of the pattern that matched
+	Build func(rawURL string, match []string) *Metadata
+}
+
+// Providers are the built-in providers, each can be switched off in the plugin settings.
+// Only these endpoints are ever requested, never the URL being embedded.
+var Providers = []*Provider{
+	{
+		Name:     "youtube",
+		TitleKey: i18n.ConfigProviderYouTube,
+		Patterns: compile(
+			`^https://(www\.|m\.)?youtube\.com/(watch\?|shorts/)`,
+			`^https://youtu\.be/[\w-]+`,
+		),
+		OEmbedEndpoint: "https://www.youtube.com/oembed",
+	},
+	{
+		Name:           "vimeo",
+		TitleKey:       i18n.ConfigProviderVimeo,
+		Patterns:       compile(`^https://(www\.)?vimeo\.com/\d+`),
+		OEmbedEndpoint: "https://vimeo.com/api/oembed.json",
+	},
+	{
+		Name:           "figma",
+		TitleKey:       i18n.ConfigProviderFigma,
+		Patterns:       compile(`^https://(www\.)?figma\.com/(file|design|proto|board)/[\w-]+`),
+		OEmbedEndpoint: "https://www.figma.com/api/oembed",
+	},
+	{
+		Name:     "gist",
+		TitleKey: i18n.ConfigProviderGist,
+		Patterns: compile(`^https://gist\.github\.com/([\w-]+)/([0-9a-f]+)`),
+		Build: func(rawURL string, match []string) *Metadata {
+			return &Metadata{
+				Type:       "rich",
+				Provider:   "gist",
+				URL:        rawURL,
+				Title:      fmt.Sprintf("Gist %s", match[2]),
+				AuthorName: match[1],
+				HTML: fmt.Sprintf(`<script src="https://gist.github.com/%s/%s.js"></script>`,
+					match[1], match[2]),
+			}
+		},
+	},
+	{
+		Name:           "codepen",
+		TitleKey:       i18n.ConfigProviderCodePen,
+		Patterns:       compile(`^https://codepen\.io/[\w-]+/pen/\w+`),
+		OEmbedEndpoint: "https://codepen.io/api/oembed",
+	},
+	{
+		Name:           "twitter",
+		TitleKey:       i18n.ConfigProviderTwitter,
+		Patterns:       compile(`^https://(www\.)?(twitter|x)\.com/\w+/status/\d+`),
+		OEmbedEndpoint: "https://publish.twitter.com/oembed",
+	},
+}
+
+func compile(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		compiled[i] = regexp.MustCompile(p)
+	}
+	return compiled
+}
+
+// Metadata describes how to embed a URL, following the oEmbed response format
+type Metadata struct {
+	Type         string `json:"type"`
+	Provider     string `json:"provider"`
+	URL          string `json:"url"`
+	Title        string `json:"title,omitempty"`
+	AuthorName   string `json:"author_name,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	// HTML is third-party markup, the UI renders it in a sandboxed iframe
+	HTML   string `json:"html,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// oEmbedResponse allows width and height to be numbers, strings or null
+type oEmbedResponse struct {
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	HTML         string `json:"html"`
+	Width        any    `json:"width"`
+	Height       any    `json:"height"`
+}
+
+// Resolver turns URLs into embed metadata using the enabled providers
+type Resolver struct {
+	client    *http.Client
+	providers []*Provider
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedMetadata
+	order []string
+}
+
+type cachedMetadata struct {
+	metadata  *Metadata
+	expiresAt time.Time
+}
+
+// NewResolver creates a resolver for the enabled providers, a zero ttl disables caching
+func NewResolver(enabled map[string]bool, ttl time.Duration) *Resolver {
+	r := &Resolver{
+		client: &http.Client{Timeout: oEmbedTimeout},
+		ttl:    ttl,
+		cache:  make(map[string]cachedMetadata),
+	}
+	for _, p := range Providers {
+		if enabled[p.Name] {
+			r.providers = append(r.providers, p)
+		}
+	}
+	return r
+}
+
+// Resolve returns the embed metadata of a URL
+func (r *Resolver) Resolve(ctx context.Context, rawURL string) (*Metadata, error) {
+	if u, err := url.Parse(rawURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, ErrNotAllowed
+	}
+	provider, match := r.match(rawURL)
+	if provider == nil {
+		return nil, ErrNotAllowed
+	}
+	if metadata, ok := r.cached(rawURL); ok {
+		return metadata, nil
+	}
+
+	var metadata *Metadata
+	if provider.Build != nil {
+		metadata = provider.Build(rawURL, match)
+	} else {
+		var err error
+		if metadata, err = r.fetchOEmbed(ctx, provider, rawURL); err != nil {
+			return nil, err
+		}
+	}
+	r.store(rawURL, metadata)
+	return metadata, nil
+}
+
+func (r *Resolver) match(rawURL string) (*Provider, []string) {
+	for _, p := range r.providers {
+		for _, pattern := range p.Patterns {
+			if match := pattern.FindStringSubmatch(rawURL); match != nil {
+				return p, match
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) fetchOEmbed(ctx context.Context, provider *Provider, rawURL string) (*Metadata, error) {
+	query := url.Values{"url": {rawURL}, "format": {"json"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.OEmbedEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s oEmbed returned %s", provider.Name, resp.Status)
+	}
+
+	data := oEmbedResponse{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOEmbedPayload)).Decode(&data); err != nil {
+		return nil, fmt.Errorf("%s oEmbed: %w", provider.Name, err)
+	}
+	return &Metadata{
+		Type:         data.Type,
+		Provider:     provider.Name,
+		URL:          rawURL,
+		Title:        data.Title,
+		AuthorName:   data.AuthorName,
+		ThumbnailURL: data.ThumbnailURL,
+		HTML:         data.HTML,
+		Width:        dimension(data.Width),
+		Height:       dimension(data.Height),
+	}, nil
+}
+
+// dimension keeps pixel sizes, providers that answer "100%" leave sizing to the UI
+func dimension(v any) int {
+	if n, ok := v.(float64); ok {
+		return int(n)
+	}
+	return 0
+}
+
+func (r *Resolver) cached(rawURL string) (*Metadata, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.cache[rawURL]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.metadata, true
+}
+
+func (r *Resolver) store(rawURL string, metadata *Metadata) {
+	if r.ttl <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.cache[rawURL]; !ok {
+		r.order = append(r.order, rawURL)
+	}
+	r.cache[rawURL] = cachedMetadata{metadata: metadata, expiresAt: time.Now().Add(r.ttl)}
+
+	// Evict the oldest entries once the cache is full
+	for len(r.order) > maxCacheEntries {
+		delete(r.cache, r.order[0])
+		r.order = r.order[1:]
+	}
+}
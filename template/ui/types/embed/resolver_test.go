@@ -0,0 +1,237 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newStubResolver resolves https://video.test/<id> URLs through a stand-in oEmbed endpoint
+func newStubResolver(t *testing.T, ttl time.Duration) (*Resolver, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("format") != "json" {
+			http.Error(w, "format must be json", http.StatusNotImplemented)
+			return
+		}
+		if r.URL.Query().Get("url") == "https://video.test/0404" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"type": "video", "title": "Video", "html": "<iframe></iframe>", "width": 640, "height": "100%%"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	r := NewResolver(nil, ttl)
+	r.client = server.Client()
+	r.providers = []*Provider{{
+		Name:           "stub",
+		Patterns:       compile(`^https://video\.test/\d+$`),
+		OEmbedEndpoint: server.URL,
+	}}
+	return r, &calls
+}
+
+// TestConfigRoundTrip checks a saved config gives the same providers back,
+// composite plugins restore the previous config that way
+func TestConfigRoundTrip(t *testing.T) {
+	p := newPlugin()
+	if err := p.ConfigReceiver([]byte(`{"youtube": true, "vimeo": false, "cache_ttl_minutes": "5"}`)); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := json.Marshal(p.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := newPlugin()
+	if err := restored.ConfigReceiver(saved); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(restored.Config.Providers, p.Config.Providers) {
+		t.Errorf("providers = %v after a round trip, want %v", restored.Config.Providers, p.Config.Providers)
+	}
+
+	if err := restored.ConfigReceiver([]byte(`{"providers": {"youtube": true, "unknown": true}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := restored.Config.Providers["unknown"]; ok {
+		t.Error("a saved config enabled a provider the allowlist doesn't have")
+	}
+}
+
+func TestProviderPatterns(t *testing.T) {
+	r := NewResolver(defaultProviders(), 0)
+	tests := []struct {
+		url      string
+		provider string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "youtube"},
+		{"https://m.youtube.com/shorts/abc123", "youtube"},
+		{"https://youtu.be/dQw4w9WgXcQ", "youtube"},
+		{"https://vimeo.com/76979871", "vimeo"},
+		{"https://www.figma.com/design/AbC123/Mockups", "figma"},
+		{"https://gist.github.com/octocat/6cad326836d38bd3a7ae", "gist"},
+		{"https://codepen.io/team/pen/abcDEF", "codepen"},
+		{"https://x.com/answerdev/status/1234567890", "twitter"},
+		{"https://twitter.com/answerdev/status/1234567890", "twitter"},
+		{"http://www.youtube.com/watch?v=dQw4w9WgXcQ", ""},
+		{"https://youtube.com.evil.test/watch?v=x", ""},
+		{"https://evil.test/?u=https://vimeo.com/76979871", ""},
+		{"https://vimeo.com/channels", ""},
+		{"https://gist.github.com/octocat", ""},
+	}
+	for _, tt := range tests {
+		provider, _ := r.match(tt.url)
+		got := ""
+		if provider != nil {
+			got = provider.Name
+		}
+		if got != tt.provider {
+			t.Errorf("match(%q) = %q, want %q", tt.url, got, tt.provider)
+		}
+	}
+}
+
+func TestResolveAllowlist(t *testing.T) {
+	r := NewResolver(map[string]bool{"gist": true}, time.Hour)
+	for _, rawURL := range []string{
+		"https://vimeo.com/76979871",
+		"http://gist.github.com/octocat/6cad326836d38bd3a7ae",
+		"not a url",
+		"",
+	} {
+		if _, err := r.Resolve(context.Background(), rawURL); !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("Resolve(%q) = %v, want ErrNotAllowed", rawURL, err)
+		}
+	}
+
+	metadata, err := r.Resolve(context.Background(), "https://gist.github.com/octocat/6cad326836d38bd3a7ae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Provider != "gist" || metadata.AuthorName != "octocat" {
+		t.Errorf("gist metadata = %+v", metadata)
+	}
+}
+
+func TestResolveOEmbed(t *testing.T) {
+	r, calls := newStubResolver(t, time.Hour)
+
+	metadata, err := r.Resolve(context.Background(), "https://video.test/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Type != "video" || metadata.Provider != "stub" || metadata.Width != 640 || metadata.Height != 0 {
+		t.Errorf("metadata = %+v, want a 640 wide video with the height left to the UI", metadata)
+	}
+
+	if _, err := r.Resolve(context.Background(), "https://video.test/1"); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 1 {
+		t.Errorf("endpoint called %d times, want the second resolve cached", *calls)
+	}
+
+	if _, err := r.Resolve(context.Background(), "https://video.test/0404"); err == nil || errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Resolve of a URL the provider doesn't know = %v, want the provider's error", err)
+	}
+}
+
+func TestResolveCacheExpiry(t *testing.T) {
+	r, calls := newStubResolver(t, time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err := r.Resolve(context.Background(), "https://video.test/1"); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if *calls != 2 {
+		t.Errorf("endpoint called %d times, want the expired entry fetched again", *calls)
+	}
+
+	r, calls = newStubResolver(t, 0)
+	for i := 0; i < 2; i++ {
+		if _, err := r.Resolve(context.Background(), "https://video.test/1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if *calls != 2 {
+		t.Errorf("endpoint called %d times with caching disabled, want 2", *calls)
+	}
+}
+
+func TestResolveCacheEviction(t *testing.T) {
+	r, calls := newStubResolver(t, time.Hour)
+	for i := 0; i <= maxCacheEntries; i++ {
+		if _, err := r.Resolve(context.Background(), fmt.Sprintf("https://video.test/%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(r.cache) != maxCacheEntries || len(r.order) != maxCacheEntries {
+		t.Fatalf("cache holds %d entries in an order of %d, want %d", len(r.cache), len(r.order), maxCacheEntries)
+	}
+
+	fetched := *calls
+	if _, err := r.Resolve(context.Background(), fmt.Sprintf("https://video.test/%d", maxCacheEntries)); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != fetched {
+		t.Error("the newest entry was evicted")
+	}
+	if _, err := r.Resolve(context.Background(), "https://video.test/0"); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != fetched+1 {
+		t.Error("the oldest entry was kept past the cache size")
+	}
+}
+
+func TestResolveTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	r := NewResolver(nil, time.Hour)
+	r.client.Transport = server.Client().Transport
+	r.client.Timeout = 50 * time.Millisecond
+	r.providers = []*Provider{{Name: "slow", Patterns: compile(`^https://slow\.test/`), OEmbedEndpoint: server.URL}}
+
+	start := time.Now()
+	if _, err := r.Resolve(context.Background(), "https://slow.test/1"); err == nil {
+		t.Error("Resolve of a hanging provider returned no error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Resolve waited %s for a hanging provider", elapsed)
+	}
+}
@@ -16,23 +16,75 @@
  * specific language governing permissions and limitations
  * under the License.
  */
-
-import { FC } from 'react';
+import { FC, FormEvent, useEffect, useState } from 'react';
 import { useTranslation } from 'react-i18next';
 
+import { Note, createNote, listNotes } from './api';
+
 const Component: FC = () => {
   const { t } = useTranslation('plugin', {
     keyPrefix: '{{plugin_slug_name}}.frontend',
   });
+  const [notes, setNotes] = useState<Note[]>([]);
+  const [text, setText] = useState('');
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    const controller = new AbortController();
+    listNotes({ limit: 20 }, { signal: controller.signal })
+      .then((result) => setNotes(result.notes))
+      .catch((err) => {
+        if (!controller.signal.aborted) {
+          setError(err.message);
+        }
+      });
+
+    return () => controller.abort();
+  }, []);
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    createNote({ text })
+      .then((note) => {
+        setNotes([note, ...notes]);
+        setText('');
+        setError('');
+      })
+      .catch((err) => setError(err.message));
+  };
 
   return (
-    <div>
-      <h1>{t('hello_world')}</h1>
-      <p>Route: {{route_path}}</p>
-      <p>This is a Hello World Route Plugin!</p>
+    <div className="py-4">
+      <h3 className="mb-3">{t('title')}</h3>
+      <form className="d-flex gap-2 mb-3" onSubmit={handleSubmit}>
+        <input
+          className="form-control"
+          placeholder={t('placeholder')}
+          value={text}
+          maxLength={500}
+          onChange={(e) => setText(e.target.value)}
+        />
+        <button type="submit" className="btn btn-primary text-nowrap">
+          {t('add')}
+        </button>
+      </form>
+      {error && <div className="alert alert-danger">{error}</div>}
+      {notes.length === 0 ? (
+        <p className="text-secondary">{t('empty')}</p>
+      ) : (
+        <ul className="list-group">
+          {notes.map((note) => (
+            <li key={note.id} className="list-group-item">
+              {note.text}
+              <small className="d-block text-secondary">
+                {new Date(note.created_at).toLocaleString()}
+              </small>
+            </li>
+          ))}
+        </ul>
+      )}
     </div>
   );
 };
 
 export default Component;
-
@@ -0,0 +1,131 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiPrefix scopes the page's endpoints to the plugin, Answer mounts the routers under
+// /answer/api/v1 (users) and /answer/admin/api (administrators)
+const apiPrefix = "/{{plugin_slug_name}}"
+
+// Access is who may call an endpoint, it picks the router the endpoint is registered on
+type Access int
+
+const (
+	// AccessPublic endpoints are open to anonymous visitors
+	AccessPublic Access = iota
+	// AccessUser endpoints need a logged-in user
+	AccessUser
+	// AccessAdmin endpoints need an administrator
+	AccessAdmin
+)
+
+// Endpoint is an API route of the page. The request and response types are kept so
+// the TypeScript client in api.ts can be generated from them, see client_test.go.
+type Endpoint struct {
+	// Name is the client function, e.g. "listNotes"
+	Name   string
+	Method string
+	// Path is below apiPrefix, e.g. "/notes"
+	Path   string
+	Access Access
+	// Request is the type bound from the query string for GET and DELETE, from the
+	// JSON body otherwise. Response is the type of the JSON response.
+	Request  reflect.Type
+	Response reflect.Type
+	handler  func(p *{{plugin_display_name}}) gin.HandlerFunc
+}
+
+// HandlerFunc serves a typed request. Return an *APIError to pick the status code.
+type HandlerFunc[Req, Resp any] func(p *{{plugin_display_name}}, ctx *gin.Context, req *Req) (*Resp, error)
+
+// NewEndpoint declares an endpoint served by fn
+func NewEndpoint[Req, Resp any](name, method, path string, access Access, fn HandlerFunc[Req, Resp]) Endpoint {
+	return Endpoint{
+		Name:     name,
+		Method:   method,
+		Path:     path,
+		Access:   access,
+		Request:  reflect.TypeFor[Req](),
+		Response: reflect.TypeFor[Resp](),
+		handler: func(p *{{plugin_display_name}}) gin.HandlerFunc {
+			return func(ctx *gin.Context) {
+				req := new(Req)
+				if err := bind(ctx, req); err != nil {
+					ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				resp, err := fn(p, ctx, req)
+				if err != nil {
+					status := http.StatusInternalServerError
+					if apiErr := (*APIError)(nil); errors.As(err, &apiErr) {
+						status = apiErr.Status
+					}
+					ctx.JSON(status, gin.H{"error": err.Error()})
+					return
+				}
+				ctx.JSON(http.StatusOK, resp)
+			}
+		},
+	}
+}
+
+// NoRequest is the request type of endpoints without parameters
+type NoRequest struct{}
+
+// APIError is an error with the HTTP status to answer with
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+func bind(ctx *gin.Context, req any) error {
+	if _, ok := req.(*NoRequest); ok {
+		return nil
+	}
+	if queryMethod(ctx.Request.Method) {
+		return ctx.ShouldBindQuery(req)
+	}
+	return ctx.ShouldBindJSON(req)
+}
+
+// queryMethod reports whether requests with method carry their parameters in the query
+// string rather than a JSON body
+func queryMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodDelete
+}
+
+// registerEndpoints mounts the endpoints with the given access on router
+func registerEndpoints(p *{{plugin_display_name}}, router *gin.RouterGroup, access Access) {
+	for _, e := range endpoints {
+		if e.Access == access {
+			router.Handle(e.Method, apiPrefix+e.Path, e.handler(p))
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"{{testkit_module}}"
+)
+
+// newTestRouter mounts the endpoints the way Answer does, without the auth middleware
+func newTestRouter() (*{{plugin_display_name}}, *gin.Engine) {
+	r := &{{plugin_display_name}}{Config: &{{plugin_display_name}}Config{}, notes: NewNoteStore()}
+	return r, testkit.NewRouter(r)
+}
+
+func serve(t *testing.T, engine *gin.Engine, method, url, body string, resp any) int {
+	t.Helper()
+	req := testkit.NewRequest(t, method, url, testkit.WithBody("application/json", []byte(body)))
+	rec := testkit.Serve(engine, req)
+	if resp != nil && rec.Code == http.StatusOK {
+		testkit.DecodeJSON(t, rec, resp)
+	}
+	return rec.Code
+}
+
+func TestNotesEndpoints(t *testing.T) {
+	_, engine := newTestRouter()
+	notes := basePaths[AccessPublic] + apiPrefix + "/notes"
+
+	for _, text := range []string{"first", "second", "third"} {
+		note := &Note{}
+		if code := serve(t, engine, http.MethodPost, notes, `{"text": " `+text+` "}`, note); code != http.StatusOK {
+			t.Fatalf("create %s: status %d", text, code)
+		}
+		if note.Text != text || note.ID == 0 {
+			t.Fatalf("created %+v", note)
+		}
+	}
+
+	list := &ListNotesResponse{}
+	if code := serve(t, engine, http.MethodGet, notes+"?limit=2", "", list); code != http.StatusOK {
+		t.Fatalf("list: status %d", code)
+	}
+	if list.Total != 3 || len(list.Notes) != 2 || list.Notes[0].Text != "third" {
+		t.Fatalf("list = %+v", list)
+	}
+
+	admin := basePaths[AccessAdmin] + apiPrefix + "/notes"
+	if code := serve(t, engine, http.MethodDelete, admin+"?id=1", "", nil); code != http.StatusOK {
+		t.Fatalf("delete: status %d", code)
+	}
+	if code := serve(t, engine, http.MethodDelete, admin+"?id=1", "", nil); code != http.StatusNotFound {
+		t.Fatalf("delete twice: status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestNotesEndpointsRejectInvalidRequests(t *testing.T) {
+	_, engine := newTestRouter()
+	notes := basePaths[AccessPublic] + apiPrefix + "/notes"
+	admin := basePaths[AccessAdmin] + apiPrefix + "/notes"
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+	}{
+		{"limit out of range", http.MethodGet, notes + "?limit=1000", ""},
+		{"limit not a number", http.MethodGet, notes + "?limit=all", ""},
+		{"missing text", http.MethodPost, notes, `{}`},
+		{"blank text", http.MethodPost, notes, `{"text": "   "}`},
+		{"text too long", http.MethodPost, notes, `{"text": "` + strings.Repeat("a", 501) + `"}`},
+		{"not JSON", http.MethodPost, notes, `text`},
+		{"missing id", http.MethodDelete, admin, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := serve(t, engine, tt.method, tt.url, tt.body, nil); code != http.StatusBadRequest {
+				t.Fatalf("status %d, want %d", code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestEndpointsAreMountedOnTheirRouter(t *testing.T) {
+	_, engine := newTestRouter()
+	// Deleting is for administrators, the public API must not offer it
+	url := basePaths[AccessPublic] + apiPrefix + "/notes?id=1"
+	if code := serve(t, engine, http.MethodDelete, url, "", nil); code != http.StatusNotFound {
+		t.Fatalf("status %d, want %d", code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,357 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"{{testkit_module}}"
+)
+
+var update = flag.Bool("update", false, "rewrite api.ts with the client generated from the endpoints")
+
+const clientFile = "api.ts"
+
+// basePaths are where Answer mounts the router of each access level
+var basePaths = map[Access]string{
+	AccessPublic: testkit.UnAuthPrefix,
+	AccessUser:   testkit.AuthUserPrefix,
+	AccessAdmin:  testkit.AdminPrefix,
+}
+
+// TestClientUpToDate fails when api.ts no longer matches the endpoints, run
+// go test -update to generate it again.
+func TestClientUpToDate(t *testing.T) {
+	generated := generateClient(endpoints)
+	if *update {
+		if err := os.WriteFile(clientFile, []byte(generated), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	current, err := os.ReadFile(clientFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(current) != generated {
+		t.Fatalf("%s is out of date, run go test -update to generate it again", clientFile)
+	}
+}
+
+func TestClientTypes(t *testing.T) {
+	type Inner struct {
+		Value float64 `json:"value"`
+	}
+	type Sample struct {
+		Inner
+		Name     string            `json:"name"`
+		Count    *int              `json:"count"`
+		Tags     []string          `json:"tags,omitempty"`
+		Labels   map[string]*Inner `json:"labels"`
+		Raw      json.RawMessage   `json:"raw"`
+		Hidden   string            `json:"-"`
+		Untagged bool
+		At       time.Time `json:"at"`
+	}
+
+	g := newClientGenerator()
+	if got := g.typeOf(reflect.TypeFor[Sample]()); got != "Sample" {
+		t.Fatalf("typeOf() = %q", got)
+	}
+	got := g.declarations()
+	want := `export interface Sample {
+  value: number;
+  name: string;
+  count: number | null;
+  tags?: string[];
+  labels: Record<string, Inner>;
+  raw: unknown;
+  Untagged: boolean;
+  at: string;
+}
+
+export interface Inner {
+  value: number;
+}
+`
+	if got != want {
+		t.Fatalf("declarations:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// clientGenerator renders Go types as TypeScript interfaces
+type clientGenerator struct {
+	seen  map[reflect.Type]bool
+	queue []reflect.Type
+	// requests marks the request types, true for those bound from the query string
+	requests map[reflect.Type]bool
+}
+
+func newClientGenerator() *clientGenerator {
+	return &clientGenerator{seen: map[reflect.Type]bool{}, requests: map[reflect.Type]bool{}}
+}
+
+// generateClient renders api.ts: the types of the endpoints and a function per endpoint
+func generateClient(endpoints []Endpoint) string {
+	g := newClientGenerator()
+	functions := &strings.Builder{}
+	for _, e := range endpoints {
+		url := basePaths[e.Access] + apiPrefix + e.Path
+		params, args := "", "undefined"
+		if e.Request != reflect.TypeFor[NoRequest]() {
+			g.requests[e.Request] = queryMethod(e.Method)
+			params, args = "params: "+g.typeOf(e.Request), "params"
+			if g.allOptional(e.Request) {
+				params += " = {}"
+			}
+			params += ", "
+		}
+		response := g.typeOf(e.Response)
+
+		fmt.Fprintf(functions, "\n// %s %s\n", e.Method, url)
+		fmt.Fprintf(functions, "export const %s = (%soptions?: CallOptions) =>\n", e.Name, params)
+		fmt.Fprintf(functions, "  call<%s>('%s', '%s', %s, options);\n", response, e.Method, url, args)
+	}
+
+	return clientHeader + "\n" + g.declarations() + clientRuntime + functions.String()
+}
+
+// declarations renders the interfaces of the struct types seen so far, and of the
+// types they refer to
+func (g *clientGenerator) declarations() string {
+	b := &strings.Builder{}
+	for len(g.queue) > 0 {
+		t := g.queue[0]
+		g.queue = g.queue[1:]
+		fmt.Fprintf(b, "export interface %s {\n", t.Name())
+		for _, f := range g.fields(t) {
+			fmt.Fprintf(b, "  %s: %s;\n", f.name, f.tsType)
+		}
+		b.WriteString("}\n\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+type clientField struct {
+	name     string
+	tsType   string
+	optional bool
+}
+
+// fields lists the fields as encoding/json sees them, or gin's query binding. Request
+// fields are optional unless their binding is required.
+func (g *clientGenerator) fields(t reflect.Type) []clientField {
+	query, request := g.requests[t]
+	var fields []clientField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || len(f.Index) > 1 && !embeddedPath(t, f.Index) {
+			continue
+		}
+		name, options, tagged := f.Name, "", false
+		if tag, ok := f.Tag.Lookup(tagKey(query)); ok {
+			name, options, _ = strings.Cut(tag, ",")
+			tagged = true
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+		}
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct {
+			continue // its fields are listed by VisibleFields
+		}
+		optional := strings.Contains(options, "omitempty")
+		if request {
+			optional = !strings.Contains(f.Tag.Get("binding"), "required")
+		}
+		tsType := g.typeOf(f.Type)
+		if f.Type.Kind() == reflect.Pointer && !optional {
+			tsType += " | null"
+		}
+		if optional {
+			name += "?"
+		}
+		fields = append(fields, clientField{name: name, tsType: tsType, optional: optional})
+	}
+	return fields
+}
+
+// tagKey is the tag naming fields in the query string or in JSON
+func tagKey(query bool) string {
+	if query {
+		return "form"
+	}
+	return "json"
+}
+
+// embeddedPath reports whether every field on the way to a promoted field is an
+// untagged embedded struct, as encoding/json requires
+func embeddedPath(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		f := t.Field(i)
+		if !f.Anonymous || f.Tag.Get("json") != "" {
+			return false
+		}
+		t = f.Type
+	}
+	return true
+}
+
+func (g *clientGenerator) allOptional(t reflect.Type) bool {
+	for _, f := range g.fields(t) {
+		if !f.optional {
+			return false
+		}
+	}
+	return true
+}
+
+// typeOf renders t as a TypeScript type and queues the named structs it refers to.
+// Pointers in slices and maps are rendered as their element, they are rarely nil.
+func (g *clientGenerator) typeOf(t reflect.Type) string {
+	switch t {
+	case reflect.TypeFor[time.Time](), reflect.TypeFor[json.Number]():
+		return "string"
+	case reflect.TypeFor[json.RawMessage]():
+		return "unknown"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Pointer:
+		return g.typeOf(t.Elem())
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // base64
+		}
+		return g.typeOf(t.Elem()) + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.typeOf(t.Elem()) + ">"
+	case reflect.Struct:
+		if t.Name() == "" {
+			b := &strings.Builder{}
+			b.WriteString("{ ")
+			for _, f := range g.fields(t) {
+				fmt.Fprintf(b, "%s: %s; ", f.name, f.tsType)
+			}
+			return b.String() + "}"
+		}
+		if !g.seen[t] {
+			g.seen[t] = true
+			g.queue = append(g.queue, t)
+		}
+		return t.Name()
+	}
+	return "unknown"
+}
+
+const clientHeader = `/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Code generated by go test -update from the endpoints in the Go code. DO NOT EDIT.
+`
+
+const clientRuntime = `
+export interface CallOptions {
+  signal?: AbortSignal;
+}
+
+export class APIError extends Error {
+  constructor(
+    readonly status: number,
+    message: string,
+  ) {
+    super(message);
+  }
+}
+
+// Answer keeps the session token JSON encoded in local storage
+const authorization = (): Record<string, string> => {
+  try {
+    const token = JSON.parse(localStorage.getItem('_a_ltk_') || '""');
+    return token ? { Authorization: token } : {};
+  } catch {
+    return {};
+  }
+};
+
+const call = async <T>(
+  method: string,
+  url: string,
+  params: object | undefined,
+  options?: CallOptions,
+): Promise<T> => {
+  const query = method === 'GET' || method === 'DELETE';
+  const search = new URLSearchParams();
+  if (query) {
+    Object.entries(params ?? {}).forEach(([key, value]) => {
+      [value].flat().forEach((item) => {
+        if (item !== undefined && item !== null) {
+          search.append(key, String(item));
+        }
+      });
+    });
+  }
+  const qs = search.toString();
+  const resp = await fetch(qs ? url + '?' + qs : url, {
+    method,
+    signal: options?.signal,
+    headers: {
+      ...authorization(),
+      ...(query ? {} : { 'Content-Type': 'application/json' }),
+    },
+    body: query ? undefined : JSON.stringify(params ?? {}),
+  });
+  const data = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    throw new APIError(resp.status, data.error || resp.statusText);
+  }
+  return data as T;
+};
+`
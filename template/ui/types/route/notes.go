@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"sync"
+	"time"
+)
+
+// maxNotes bounds the example store, the oldest notes are dropped first
+const maxNotes = 100
+
+// Note is an entry on the example page
+type Note struct {
+	ID        int       `json:"id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListNotesRequest is read from the query string
+type ListNotesRequest struct {
+	// Limit is the number of notes to return, newest first, 20 when not set
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type ListNotesResponse struct {
+	Notes []*Note `json:"notes"`
+	Total int     `json:"total"`
+}
+
+type CreateNoteRequest struct {
+	Text string `json:"text" binding:"required,max=500"`
+}
+
+type DeleteNoteRequest struct {
+	ID int `form:"id" binding:"required"`
+}
+
+// NoteStore keeps the notes in memory. Use KV storage to keep them across restarts
+// and Answer instances.
+type NoteStore struct {
+	mu     sync.RWMutex
+	notes  []*Note
+	nextID int
+}
+
+func NewNoteStore() *NoteStore {
+	return &NoteStore{nextID: 1}
+}
+
+// List returns up to limit notes, newest first, and how many there are
+func (s *NoteStore) List(limit int) ([]*Note, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	notes := make([]*Note, 0, min(limit, len(s.notes)))
+	for i := len(s.notes) - 1; i >= 0 && len(notes) < limit; i-- {
+		notes = append(notes, s.notes[i])
+	}
+	return notes, len(s.notes)
+}
+
+func (s *NoteStore) Add(text string, now time.Time) *Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note := &Note{ID: s.nextID, Text: text, CreatedAt: now}
+	s.nextID++
+	s.notes = append(s.notes, note)
+	if len(s.notes) > maxNotes {
+		s.notes = s.notes[len(s.notes)-maxNotes:]
+	}
+	return note
+}
+
+// Delete removes a note, nil if there is none with the ID
+func (s *NoteStore) Delete(id int) *Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, note := range s.notes {
+		if note.ID == id {
+			s.notes = append(s.notes[:i], s.notes[i+1:]...)
+			return note
+		}
+	}
+	return nil
+}
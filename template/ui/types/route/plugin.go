@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//section:fields
+
+	notes *NoteStore
+
+//section:init
+		notes:  NewNoteStore(),
+
+//section:body
+// endpoints is the page's API. After changing an endpoint or its types, run
+// go test -update to generate the TypeScript client in api.ts again.
+var endpoints = []Endpoint{
+	NewEndpoint("listNotes", http.MethodGet, "/notes", AccessPublic, (*{{plugin_display_name}}).listNotes),
+	NewEndpoint("createNote", http.MethodPost, "/notes", AccessUser, (*{{plugin_display_name}}).createNote),
+	NewEndpoint("deleteNote", http.MethodDelete, "/notes", AccessAdmin, (*{{plugin_display_name}}).deleteNote),
+}
+
+func (r *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {
+	registerEndpoints(r, router, AccessPublic)
+}
+
+func (r *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {
+	registerEndpoints(r, router, AccessUser)
+}
+
+func (r *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {
+	registerEndpoints(r, router, AccessAdmin)
+}
+
+func (r *{{plugin_display_name}}) listNotes(_ *gin.Context, req *ListNotesRequest) (*ListNotesResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+	notes, total := r.notes.List(limit)
+	return &ListNotesResponse{Notes: notes, Total: total}, nil
+}
+
+func (r *{{plugin_display_name}}) createNote(_ *gin.Context, req *CreateNoteRequest) (*Note, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, &APIError{Status: http.StatusBadRequest, Message: "the note is empty"}
+	}
+	return r.notes.Add(text, time.Now()), nil
+}
+
+func (r *{{plugin_display_name}}) deleteNote(_ *gin.Context, req *DeleteNoteRequest) (*Note, error) {
+	note := r.notes.Delete(req.ID)
+	if note == nil {
+		return nil, &APIError{Status: http.StatusNotFound, Message: fmt.Sprintf("note %d not found", req.ID)}
+	}
+	return note, nil
+}
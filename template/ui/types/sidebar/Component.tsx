@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import { FC, useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+
+export interface SidebarProps {
+  // Tag slug of the current page, read from the URL when not given
+  tag?: string;
+}
+
+interface SidebarLink {
+  title: string;
+  url: string;
+}
+
+interface SidebarData {
+  title: string;
+  tag: string;
+  links: SidebarLink[];
+}
+
+const LINKS_API = '/answer/api/v1/{{plugin_slug_name}}/links';
+
+const tagFromPath = () =>
+  window.location.pathname.match(/\/tags\/([^/]+)/)?.[1] ?? '';
+
+const Component: FC<SidebarProps> = ({ tag }) => {
+  const { t } = useTranslation('plugin', {
+    keyPrefix: '{{plugin_slug_name}}.frontend',
+  });
+  const [data, setData] = useState<SidebarData | null>(null);
+  const currentTag = tag ?? decodeURIComponent(tagFromPath());
+
+  useEffect(() => {
+    const controller = new AbortController();
+    fetch(`${LINKS_API}?tag=${encodeURIComponent(currentTag)}`, {
+      signal: controller.signal,
+    })
+      .then((resp) => (resp.ok ? resp.json() : Promise.reject(resp.status)))
+      .then((result: SidebarData) => setData(result))
+      .catch(() => {
+        if (!controller.signal.aborted) {
+          setData(null);
+        }
+      });
+
+    return () => controller.abort();
+  }, [currentTag]);
+
+  // Nothing configured for this page, keep the sidebar clean
+  if (!data || data.links.length === 0) {
+    return null;
+  }
+
+  return (
+    <div className="card mb-4">
+      <div className="card-header text-nowrap text-truncate">
+        {data.title || t('title')}
+      </div>
+      <div className="list-group list-group-flush">
+        {data.links.map((link) => (
+          <a
+            key={link.url}
+            href={link.url}
+            target="_blank"
+            rel="noopener noreferrer nofollow"
+            className="list-group-item list-group-item-action text-truncate">
+            {link.title}
+          </a>
+        ))}
+      </div>
+    </div>
+  );
+};
+
+export default Component;
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const maxCachedResponses = 1000
+
+// CachedResponse is an encoded response body and its ETag
+type CachedResponse struct {
+	Body []byte
+	ETag string
+
+	expires time.Time
+}
+
+// ResponseCache keeps encoded responses by key for a TTL, a zero TTL disables it
+type ResponseCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*CachedResponse
+}
+
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{ttl: ttl, entries: map[string]*CachedResponse{}}
+}
+
+// TTL is how long responses are cached, also sent to browsers
+func (c *ResponseCache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Get returns the cached response for key, or encodes and caches what build returns
+func (c *ResponseCache) Get(key string, build func() (any, error)) (*CachedResponse, error) {
+	now := time.Now()
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached, nil
+	}
+
+	value, err := build()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	cached = &CachedResponse{
+		Body:    body,
+		ETag:    `"` + hex.EncodeToString(sum[:8]) + `"`,
+		expires: now.Add(c.ttl),
+	}
+	if c.ttl <= 0 {
+		return cached, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedResponses {
+		c.evictExpired(now)
+	}
+	if len(c.entries) < maxCachedResponses {
+		c.entries[key] = cached
+	}
+	return cached, nil
+}
+
+func (c *ResponseCache) evictExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package i18n
+
+const (
+	InfoName                  = "plugin.{{info_slug_name}}.backend.info.name"
+	InfoDescription           = "plugin.{{info_slug_name}}.backend.info.description"
+	ConfigTitleTitle          = "plugin.{{info_slug_name}}.backend.config.title.title"
+	ConfigTitleDescription    = "plugin.{{info_slug_name}}.backend.config.title.description"
+	ConfigLinksTitle          = "plugin.{{info_slug_name}}.backend.config.links.title"
+	ConfigLinksDescription    = "plugin.{{info_slug_name}}.backend.config.links.description"
+	ConfigLinksPlaceholder    = "plugin.{{info_slug_name}}.backend.config.links.placeholder"
+	ConfigMaxLinksTitle       = "plugin.{{info_slug_name}}.backend.config.max_links.title"
+	ConfigCacheTTLTitle       = "plugin.{{info_slug_name}}.backend.config.cache_ttl_seconds.title"
+	ConfigCacheTTLDescription = "plugin.{{info_slug_name}}.backend.config.cache_ttl_seconds.description"
+)
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// AllTags marks links shown next to every tag and on pages without one
+const AllTags = "*"
+
+// Link is an entry of the widget
+type Link struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// LinkSet holds the configured links by tag
+type LinkSet struct {
+	byTag map[string][]*Link
+}
+
+// ParseLinks reads one "tag | title | URL" per line. Several tags can share a line separated
+// by commas, "*" matches every tag. Empty lines and lines starting with "#" are skipped.
+func ParseLinks(text string) (*LinkSet, error) {
+	set := &LinkSet{byTag: map[string][]*Link{}}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	line := 0
+	for scanner.Scan() {
+		line++
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		parts := strings.Split(entry, "|")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("links line %d: want \"tag | title | URL\"", line)
+		}
+		title, rawURL := strings.TrimSpace(parts[1]), strings.TrimSpace(parts[2])
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("links line %d: %q is not an http(s) URL", line, rawURL)
+		}
+		if title == "" {
+			title = u.Host
+		}
+		link := &Link{Title: title, URL: u.String()}
+		for _, tag := range strings.Split(parts[0], ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" {
+				return nil, fmt.Errorf("links line %d: missing tag", line)
+			}
+			set.byTag[tag] = append(set.byTag[tag], link)
+		}
+	}
+	return set, scanner.Err()
+}
+
+// ForTag returns the links of the tag followed by the ones for every tag, at most limit
+func (s *LinkSet) ForTag(tag string, limit int) []*Link {
+	links := []*Link{}
+	seen := map[string]bool{}
+	for _, key := range []string{tag, AllTags} {
+		for _, link := range s.byTag[key] {
+			if len(links) == limit {
+				return links
+			}
+			if !seen[link.URL] {
+				seen[link.URL] = true
+				links = append(links, link)
+			}
+		}
+	}
+	return links
+}
+
+// Tags returns the tags with links of their own, sorted
+func (s *LinkSet) Tags() []string {
+	tags := []string{}
+	for tag := range s.byTag {
+		if tag != AllTags {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
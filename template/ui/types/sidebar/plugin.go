@@ -0,0 +1,195 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+//section:fields
+
+	mu    sync.RWMutex
+	links *LinkSet
+	cache *ResponseCache
+
+//section:config
+	// Title is the widget heading, the translated default is used when empty
+	Title string `json:"title"`
+	// Links lists the links to show, one "tag | title | URL" per line
+	Links           string      `json:"links"`
+	MaxLinks        json.Number `json:"max_links"`
+	CacheTTLSeconds json.Number `json:"cache_ttl_seconds"`
+
+//section:defaults
+			MaxLinks:        "5",
+			CacheTTLSeconds: "300",
+
+//section:init
+		links: &LinkSet{},
+		cache: NewResponseCache(defaultCacheTTL),
+
+//section:body
+var _ plugin.Sidebar = (*{{plugin_display_name}})(nil)
+
+const (
+	defaultMaxLinks = 5
+	defaultCacheTTL = 5 * time.Minute
+)
+
+// sidebarResponse is what the widget renders
+type sidebarResponse struct {
+	Title string  `json:"title"`
+	Tag   string  `json:"tag"`
+	Links []*Link `json:"links"`
+}
+
+func (s *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:        "title",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigTitleTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigTitleDescription),
+			Value:       s.Config.Title,
+		},
+		{
+			Name:        "links",
+			Type:        plugin.ConfigTypeTextarea,
+			Title:       plugin.MakeTranslator(i18n.ConfigLinksTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigLinksDescription),
+			UIOptions: plugin.ConfigFieldUIOptions{
+				Rows:        "8",
+				Placeholder: plugin.MakeTranslator(i18n.ConfigLinksPlaceholder),
+			},
+			Value: s.Config.Links,
+		},
+		{
+			Name:      "max_links",
+			Type:      plugin.ConfigTypeInput,
+			Title:     plugin.MakeTranslator(i18n.ConfigMaxLinksTitle),
+			UIOptions: plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeNumber},
+			Value:     s.Config.MaxLinks.String(),
+		},
+		{
+			Name:        "cache_ttl_seconds",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigCacheTTLTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigCacheTTLDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeNumber},
+			Value:       s.Config.CacheTTLSeconds.String(),
+		},
+	}
+}
+
+// GetSidebarConfig answers Answer's GET /answer/api/v1/sidebar/config with the stored
+// links and the tags they are listed under
+func (s *{{plugin_display_name}}) GetSidebarConfig() (*plugin.SidebarConfig, error) {
+	s.mu.RLock()
+	links, config := s.links, s.Config
+	s.mu.RUnlock()
+
+	tags := []*plugin.TagSelectorOption{}
+	for _, tag := range links.Tags() {
+		tags = append(tags, &plugin.TagSelectorOption{SlugName: tag, DisplayName: tag})
+	}
+	return &plugin.SidebarConfig{Tags: tags, LinksText: config.Links}, nil
+}
+
+// ConfigReceiver rejects unparsable links so a typo doesn't empty the widget, and starts
+// a new cache so the widget shows the change right away
+func (s *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+	links, err := ParseLinks(c.Links)
+	if err != nil {
+		return err
+	}
+	if _, err := positive(c.MaxLinks, defaultMaxLinks); err != nil {
+		return fmt.Errorf("invalid max links %q", c.MaxLinks)
+	}
+	ttl, err := positive(c.CacheTTLSeconds, int(defaultCacheTTL/time.Second))
+	if err != nil {
+		return fmt.Errorf("invalid cache TTL %q", c.CacheTTLSeconds)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Config = c
+	s.links = links
+	s.cache = NewResponseCache(time.Duration(ttl) * time.Second)
+	return nil
+}
+
+// positive parses a non-negative number setting, empty means the default
+func positive(value json.Number, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := value.Int64()
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return int(n), nil
+}
+
+// RegisterUnAuthRouter serves the widget's data, anonymous visitors see the sidebar too:
+//
+//	GET /{{plugin_slug_name}}/links?tag=...
+func (s *{{plugin_display_name}}) RegisterUnAuthRouter(router *gin.RouterGroup) {
+	router.GET("/{{plugin_slug_name}}/links", s.getLinks)
+}
+
+func (s *{{plugin_display_name}}) RegisterAuthUserRouter(router *gin.RouterGroup) {}
+
+func (s *{{plugin_display_name}}) RegisterAuthAdminRouter(router *gin.RouterGroup) {}
+
+func (s *{{plugin_display_name}}) getLinks(ctx *gin.Context) {
+	tag := strings.ToLower(strings.TrimSpace(ctx.Query("tag")))
+
+	s.mu.RLock()
+	links, cache, config := s.links, s.cache, s.Config
+	s.mu.RUnlock()
+
+	cached, err := cache.Get(tag, func() (any, error) {
+		maxLinks, _ := positive(config.MaxLinks, defaultMaxLinks)
+		return &sidebarResponse{Title: config.Title, Tag: tag, Links: links.ForTag(tag, maxLinks)}, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("ETag", cached.ETag)
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(cache.TTL()/time.Second)))
+	if ctx.GetHeader("If-None-Match") == cached.ETag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", cached.Body)
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+import { FC } from 'react';
+import { useTranslation } from 'react-i18next';
+
+export interface CaptchaProps {
+  // Image of the challenge, the captcha Create returned
+  imgSrc?: string;
+  value?: string;
+  // Called with the answer, which is sent to Answer as the captcha code
+  onChange?: (code: string) => void;
+  // Asks Answer for a new challenge
+  onRefresh?: () => void;
+}
+
+const Component: FC<CaptchaProps> = ({
+  imgSrc,
+  value,
+  onChange,
+  onRefresh,
+}) => {
+  const { t } = useTranslation('plugin', {
+    keyPrefix: '{{plugin_slug_name}}.frontend',
+  });
+
+  return (
+    <div className="d-flex align-items-center gap-2">
+      {imgSrc && (
+        <img
+          src={imgSrc}
+          alt=""
+          height={50}
+          role="button"
+          title={t('refresh')}
+          onClick={onRefresh}
+        />
+      )}
+      <input
+        type="text"
+        inputMode="numeric"
+        autoComplete="off"
+        className="form-control"
+        placeholder={t('placeholder')}
+        value={value}
+        onChange={(e) => onChange?.(e.target.value)}
+      />
+    </div>
+  );
+};
+
+export default Component;
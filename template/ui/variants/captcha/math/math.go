@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultChallengeTTL = 5 * time.Minute
+	nonceSize           = 16
+	secretSize          = 32
+)
+
+var errMalformedToken = errors.New("malformed captcha token")
+
+// Challenge is a math question and its answer
+type Challenge struct {
+	Question string
+	Answer   int
+}
+
+// MathCaptcha asks users to add or subtract two small numbers. The code Answer keeps
+// is a signed token "nonce.expiry.signature", where the signature is an HMAC over the
+// nonce, the expiry and the answer. So the answer is never stored, a token can't be
+// altered to expire later, and each nonce is accepted once.
+type MathCaptcha struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+	used   *nonceSet
+}
+
+// NewMathCaptcha signs challenges with secret, a random one if it is empty. Tokens
+// signed with a random secret don't survive a restart and only work on this instance.
+func NewMathCaptcha(secret string, ttl time.Duration) (*MathCaptcha, error) {
+	m := &MathCaptcha{now: time.Now, used: &nonceSet{seen: map[string]time.Time{}}}
+	return m.With(secret, ttl)
+}
+
+// With returns a copy using another secret and TTL. Nonces the original accepted are
+// still rejected by the copy.
+func (m *MathCaptcha) With(secret string, ttl time.Duration) (*MathCaptcha, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("invalid challenge TTL %s", ttl)
+	}
+	key := []byte(secret)
+	if secret == "" {
+		key = make([]byte, secretSize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+	return &MathCaptcha{secret: key, ttl: ttl, now: m.now, used: m.used}, nil
+}
+
+// Create returns a new challenge as an SVG data URL, and the token to check the answer with
+func (m *MathCaptcha) Create() (image, token string, err error) {
+	_, image, token, err = m.create()
+	return image, token, err
+}
+
+func (m *MathCaptcha) create() (challenge Challenge, image, token string, err error) {
+	challenge, err = newChallenge()
+	if err != nil {
+		return Challenge{}, "", "", err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return Challenge{}, "", "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(nonce) + "." +
+		strconv.FormatInt(m.now().Add(m.ttl).Unix(), 10)
+	token = payload + "." + m.sign(payload, challenge.Answer)
+	return challenge, RenderSVG(challenge.Question), token, nil
+}
+
+// Verify reports whether code is the answer to the challenge the token was created for.
+// Any attempt uses the token up, so answers can't be guessed one after the other.
+func (m *MathCaptcha) Verify(ctx context.Context, token, code string) (bool, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false, errMalformedToken
+	}
+	nonce, signature := parts[0], parts[2]
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return false, errMalformedToken
+	}
+	// Tokens expiring later than a new one would can't be ours, don't remember their nonce
+	expires := time.Unix(expiresAt, 0)
+	if !m.now().Before(expires) || expires.After(m.now().Add(m.ttl)) {
+		return false, nil
+	}
+	if !m.used.add(nonce, expires, m.now()) {
+		return false, nil
+	}
+
+	answer, err := strconv.Atoi(strings.TrimSpace(code))
+	if err != nil {
+		return false, nil
+	}
+	expected := m.sign(parts[0]+"."+parts[1], answer)
+	return hmac.Equal([]byte(signature), []byte(expected)), nil
+}
+
+func (m *MathCaptcha) sign(payload string, answer int) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(payload + "|" + strconv.Itoa(answer)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newChallenge picks a sum or difference of numbers up to 20, differences are never negative
+func newChallenge() (Challenge, error) {
+	n := make([]int, 3)
+	for i, limit := range []int64{20, 20, 2} {
+		v, err := rand.Int(rand.Reader, big.NewInt(limit))
+		if err != nil {
+			return Challenge{}, err
+		}
+		n[i] = int(v.Int64())
+	}
+	a, b := n[0]+1, n[1]+1
+	if n[2] == 0 {
+		return Challenge{Question: fmt.Sprintf("%d + %d", a, b), Answer: a + b}, nil
+	}
+	if a < b {
+		a, b = b, a
+	}
+	return Challenge{Question: fmt.Sprintf("%d - %d", a, b), Answer: a - b}, nil
+}
+
+// nonceSet remembers the nonces that were used until their token expires
+type nonceSet struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastPrune time.Time
+}
+
+// add marks a nonce as used, false if it was used before
+func (s *nonceSet) add(nonce string, expires, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastPrune) > time.Minute {
+		for n, e := range s.seen {
+			if !now.Before(e) {
+				delete(s.seen, n)
+			}
+		}
+		s.lastPrune = now
+	}
+	if _, ok := s.seen[nonce]; ok {
+		return false
+	}
+	s.seen[nonce] = expires
+	return true
+}
@@ -0,0 +1,200 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"{{testkit_module}}"
+)
+
+func newTestCaptcha(t *testing.T, secret string) *MathCaptcha {
+	t.Helper()
+	m, err := NewMathCaptcha(secret, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func createChallenge(t *testing.T, m *MathCaptcha) (Challenge, string) {
+	t.Helper()
+	challenge, _, token, err := m.create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return challenge, token
+}
+
+func TestMathCaptchaVerify(t *testing.T) {
+	ctx := context.Background()
+	m := newTestCaptcha(t, "test secret")
+
+	for name, check := range map[string]func(t *testing.T) bool{
+		"correct answer": func(t *testing.T) bool {
+			challenge, token := createChallenge(t, m)
+			return mustVerify(t, m, token, " "+strconv.Itoa(challenge.Answer)+" ")
+		},
+		"wrong answer": func(t *testing.T) bool {
+			challenge, token := createChallenge(t, m)
+			return mustVerify(t, m, token, strconv.Itoa(challenge.Answer+1))
+		},
+		"not a number": func(t *testing.T) bool {
+			_, token := createChallenge(t, m)
+			return mustVerify(t, m, token, "seven")
+		},
+		"replayed token": func(t *testing.T) bool {
+			challenge, token := createChallenge(t, m)
+			mustVerify(t, m, token, strconv.Itoa(challenge.Answer))
+			return mustVerify(t, m, token, strconv.Itoa(challenge.Answer))
+		},
+		"retry after a wrong answer": func(t *testing.T) bool {
+			challenge, token := createChallenge(t, m)
+			mustVerify(t, m, token, strconv.Itoa(challenge.Answer+1))
+			return mustVerify(t, m, token, strconv.Itoa(challenge.Answer))
+		},
+		"expired token": func(t *testing.T) bool {
+			challenge, token := createChallenge(t, m)
+			later := *m
+			later.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+			return mustVerify(t, &later, token, strconv.Itoa(challenge.Answer))
+		},
+		"extended expiry": func(t *testing.T) bool {
+			challenge, token := createChallenge(t, m)
+			parts := strings.Split(token, ".")
+			expires, _ := strconv.ParseInt(parts[1], 10, 64)
+			parts[1] = strconv.FormatInt(expires+3600, 10)
+			return mustVerify(t, m, strings.Join(parts, "."), strconv.Itoa(challenge.Answer))
+		},
+		"signed with another secret": func(t *testing.T) bool {
+			challenge, token := createChallenge(t, newTestCaptcha(t, "other secret"))
+			return mustVerify(t, m, token, strconv.Itoa(challenge.Answer))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			want := name == "correct answer"
+			if got := check(t); got != want {
+				t.Errorf("Verify() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	if _, err := m.Verify(ctx, "not-a-token", "1"); !errors.Is(err, errMalformedToken) {
+		t.Errorf("malformed token: got error %v", err)
+	}
+}
+
+func mustVerify(t *testing.T, m *MathCaptcha, token, code string) bool {
+	t.Helper()
+	pass, err := m.Verify(context.Background(), token, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pass
+}
+
+func TestMathCaptchaWithKeepsUsedNonces(t *testing.T) {
+	m := newTestCaptcha(t, "test secret")
+	challenge, token := createChallenge(t, m)
+	if !mustVerify(t, m, token, strconv.Itoa(challenge.Answer)) {
+		t.Fatal("first answer rejected")
+	}
+
+	reconfigured, err := m.With("test secret", 2*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mustVerify(t, reconfigured, token, strconv.Itoa(challenge.Answer)) {
+		t.Error("a used token was accepted after reconfiguring")
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		challenge, err := newChallenge()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if challenge.Answer < 0 || challenge.Answer > 40 {
+			t.Errorf("%s = %d, out of range", challenge.Question, challenge.Answer)
+		}
+
+		image := RenderSVG(challenge.Question)
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(image, "data:image/svg+xml;base64,"))
+		if err != nil {
+			t.Fatalf("%s: %v", challenge.Question, err)
+		}
+		if err := wellFormed(string(data)); err != nil {
+			t.Fatalf("%s: invalid SVG: %v", challenge.Question, err)
+		}
+		if strings.Contains(string(data), "<text") {
+			t.Errorf("%s: the SVG contains text a bot could read", challenge.Question)
+		}
+	}
+}
+
+func wellFormed(doc string) error {
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func TestPluginCreateVerify(t *testing.T) {
+	m := newTestCaptcha(t, "")
+	p := &{{plugin_display_name}}{Config: &{{plugin_display_name}}Config{}, captcha: m}
+
+	image, code := p.Create()
+	if !strings.HasPrefix(image, "data:image/svg+xml;base64,") || code == "" {
+		t.Fatalf("Create() = %.40q, %q", image, code)
+	}
+	if p.Verify(code, "-1") {
+		t.Error("a wrong answer passed")
+	}
+	output := testkit.CaptureOutput(t)
+	if p.Verify("", "1") {
+		t.Error("an empty captcha passed")
+	}
+	if !strings.Contains(output.String(), "captcha check failed") {
+		t.Errorf("the failed check wasn't logged, got %q", output)
+	}
+
+	if err := p.ConfigReceiver([]byte(`{"ttl_seconds": "5"}`)); err == nil {
+		t.Error("a TTL below 30 seconds was accepted")
+	}
+	if err := p.ConfigReceiver([]byte(`{"secret": "shared", "ttl_seconds": "120"}`)); err != nil {
+		t.Fatal(err)
+	}
+	challenge, token := createChallenge(t, p.mathCaptcha())
+	if !p.Verify(token, strconv.Itoa(challenge.Answer)) {
+		t.Error("the right answer failed after reconfiguring")
+	}
+}
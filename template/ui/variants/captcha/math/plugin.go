@@ -0,0 +1,126 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+//section:fields
+
+	mu      sync.RWMutex
+	captcha *MathCaptcha
+
+//section:config
+	// Secret signs the challenges, set the same one on every Answer instance.
+	// A random secret is used while it is empty.
+	Secret     string      `json:"secret"`
+	TTLSeconds json.Number `json:"ttl_seconds"`
+
+//section:setup
+	captcha, err := NewMathCaptcha("", defaultChallengeTTL)
+	if err != nil {
+		panic(fmt.Sprintf("create math captcha: %v", err))
+	}
+
+//section:defaults
+			TTLSeconds: "300",
+
+//section:init
+		captcha: captcha,
+
+//section:body
+func (m *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:        "secret",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigSecretTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigSecretDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypePassword},
+			Value:       m.Config.Secret,
+		},
+		{
+			Name:        "ttl_seconds",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigTTLTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigTTLDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeNumber},
+			Value:       m.Config.TTLSeconds.String(),
+		},
+	}
+}
+
+func (m *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+	ttl := defaultChallengeTTL
+	if c.TTLSeconds != "" {
+		seconds, err := c.TTLSeconds.Int64()
+		if err != nil || seconds < 30 {
+			return fmt.Errorf("invalid challenge TTL %q, at least 30 seconds", c.TTLSeconds)
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+	captcha, err := m.mathCaptcha().With(c.Secret, ttl)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Config = c
+	m.captcha = captcha
+	return nil
+}
+
+func (m *{{plugin_display_name}}) mathCaptcha() *MathCaptcha {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.captcha
+}
+
+// GetConfig is passed to the captcha component, which needs no settings
+func (m *{{plugin_display_name}}) GetConfig() (configJSON string) {
+	return "{}"
+}
+
+// Create returns a new challenge: the image users see, and the code Answer keeps and
+// passes to Verify together with their answer
+func (m *{{plugin_display_name}}) Create() (captcha, code string) {
+	image, token, err := m.mathCaptcha().Create()
+	if err != nil {
+		log.Printf("{{plugin_slug_name}}: create captcha: %v", err)
+		return "", ""
+	}
+	return image, token
+}
+
+// Verify checks the answer a user entered, captcha is the code Create returned
+func (m *{{plugin_display_name}}) Verify(captcha, userInput string) (pass bool) {
+	return verify(m.mathCaptcha(), captcha, userInput)
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	glyphWidth  = 10
+	glyphHeight = 16
+	glyphGap    = 9
+	imageHeight = 50
+)
+
+// glyphs are drawn as strokes, so the image has no text for a bot to read. Digits use
+// seven-segment shapes on a 10x16 grid.
+var (
+	segments = map[byte][2][2]float64{
+		'a': {{0, 0}, {10, 0}},
+		'b': {{10, 0}, {10, 8}},
+		'c': {{10, 8}, {10, 16}},
+		'd': {{0, 16}, {10, 16}},
+		'e': {{0, 8}, {0, 16}},
+		'f': {{0, 0}, {0, 8}},
+		'g': {{0, 8}, {10, 8}},
+		'v': {{5, 3}, {5, 13}},
+	}
+	glyphs = map[rune]string{
+		'0': "abcdef", '1': "bc", '2': "abged", '3': "abgcd", '4': "fgbc",
+		'5': "afgcd", '6': "afgedc", '7': "abc", '8': "abcdefg", '9': "abcdfg",
+		'+': "gv", '-': "g",
+	}
+)
+
+// RenderSVG draws text, digits and + or -, with jittered strokes and noise lines,
+// and returns it as a data URL for an img tag
+func RenderSVG(text string) string {
+	text = strings.ReplaceAll(text, " ", "")
+	width := 20 + len(text)*(glyphWidth+glyphGap)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		width, imageHeight, width, imageHeight)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#f3f4f6"/>`)
+
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(&b, `<path d="M%.1f %.1f Q%.1f %.1f %.1f %.1f" stroke="#9ca3af" fill="none"/>`,
+			rand.Float64()*10, rand.Float64()*imageHeight,
+			rand.Float64()*float64(width), rand.Float64()*imageHeight,
+			float64(width)-rand.Float64()*10, rand.Float64()*imageHeight)
+	}
+
+	b.WriteString(`<g stroke="#1f2937" stroke-width="2.5" stroke-linecap="round" fill="none">`)
+	x := 12.0
+	for _, r := range text {
+		y := (imageHeight-glyphHeight)/2 + jitter(4)
+		fmt.Fprintf(&b, `<path transform="translate(%.1f %.1f) rotate(%.1f 5 8)" d="`, x+jitter(2), y, jitter(12))
+		for _, s := range glyphs[r] {
+			seg := segments[byte(s)]
+			fmt.Fprintf(&b, "M%.1f %.1fL%.1f %.1f",
+				seg[0][0]+jitter(0.8), seg[0][1]+jitter(0.8), seg[1][0]+jitter(0.8), seg[1][1]+jitter(0.8))
+		}
+		b.WriteString(`"/>`)
+		x += glyphWidth + glyphGap
+	}
+	b.WriteString(`</g></svg>`)
+
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(b.String()))
+}
+
+// jitter returns a random offset in [-n, n]
+func jitter(n float64) float64 {
+	return (rand.Float64()*2 - 1) * n
+}
@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+import { FC, useEffect, useMemo, useRef, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+
+interface WidgetConfig {
+  provider?: 'turnstile' | 'hcaptcha' | 'recaptcha';
+  site_key?: string;
+  script_url?: string;
+}
+
+interface WidgetAPI {
+  render: (
+    container: HTMLElement,
+    options: Record<string, unknown>,
+  ) => string | number;
+  remove?: (id: string | number) => void;
+  ready?: (callback: () => void) => void;
+}
+
+export interface CaptchaProps {
+  // What the plugin's GetConfig returned
+  config?: string;
+  // Called with the widget token, which is sent to Answer as the captcha code
+  onChange?: (code: string) => void;
+}
+
+// Global each provider's script defines
+const GLOBALS = {
+  turnstile: 'turnstile',
+  hcaptcha: 'hcaptcha',
+  recaptcha: 'grecaptcha',
+} as const;
+
+const scripts = new Map<string, Promise<void>>();
+
+const loadScript = (src: string): Promise<void> => {
+  let loading = scripts.get(src);
+  if (!loading) {
+    loading = new Promise((resolve, reject) => {
+      const script = document.createElement('script');
+      script.src = src;
+      script.async = true;
+      script.onload = () => resolve();
+      script.onerror = () => {
+        scripts.delete(src);
+        reject(new Error(`failed to load ${src}`));
+      };
+      document.head.appendChild(script);
+    });
+    scripts.set(src, loading);
+  }
+  return loading;
+};
+
+// reCAPTCHA defines its global before it is ready to render
+const widgetAPI = async (global: string): Promise<WidgetAPI | undefined> => {
+  const api = (window as unknown as Record<string, WidgetAPI | undefined>)[
+    global
+  ];
+  if (api?.ready) {
+    await new Promise<void>((resolve) => api.ready?.(resolve));
+  }
+  return api;
+};
+
+const Component: FC<CaptchaProps> = ({ config, onChange }) => {
+  const { t } = useTranslation('plugin', {
+    keyPrefix: '{{plugin_slug_name}}.frontend',
+  });
+  const container = useRef<HTMLDivElement>(null);
+  const [status, setStatus] = useState<'loading' | 'ready' | 'failed'>(
+    'loading',
+  );
+  const widget = useMemo<WidgetConfig>(() => {
+    try {
+      return JSON.parse(config || '{}');
+    } catch {
+      return {};
+    }
+  }, [config]);
+
+  useEffect(() => {
+    const { provider, site_key, script_url } = widget;
+    if (!provider || !site_key || !script_url || !container.current) {
+      return undefined;
+    }
+    let cancelled = false;
+    let id: string | number | undefined;
+    let api: WidgetAPI | undefined;
+
+    loadScript(script_url)
+      .then(() => widgetAPI(GLOBALS[provider]))
+      .then((loaded) => {
+        if (cancelled || !loaded || !container.current) {
+          return;
+        }
+        api = loaded;
+        id = api.render(container.current, {
+          sitekey: site_key,
+          callback: (token: string) => onChange?.(token),
+          'expired-callback': () => onChange?.(''),
+          'error-callback': () => onChange?.(''),
+        });
+        setStatus('ready');
+      })
+      .catch(() => !cancelled && setStatus('failed'));
+
+    return () => {
+      cancelled = true;
+      if (api?.remove && id !== undefined) {
+        api.remove(id);
+      }
+    };
+  }, [widget]);
+
+  return (
+    <div>
+      <div ref={container} />
+      {status === 'loading' && (
+        <small className="text-secondary">{t('loading')}</small>
+      )}
+      {status === 'failed' && (
+        <small className="text-danger">{t('load_failed')}</small>
+      )}
+    </div>
+  );
+};
+
+export default Component;
@@ -0,0 +1,36 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package i18n
+
+const (
+	InfoName                   = "plugin.{{info_slug_name}}.backend.info.name"
+	InfoDescription            = "plugin.{{info_slug_name}}.backend.info.description"
+	ConfigProviderTitle        = "plugin.{{info_slug_name}}.backend.config.provider.title"
+	ConfigProviderTurnstile    = "plugin.{{info_slug_name}}.backend.config.provider.options.turnstile"
+	ConfigProviderHCaptcha     = "plugin.{{info_slug_name}}.backend.config.provider.options.hcaptcha"
+	ConfigProviderRecaptcha    = "plugin.{{info_slug_name}}.backend.config.provider.options.recaptcha"
+	ConfigSiteKeyTitle         = "plugin.{{info_slug_name}}.backend.config.site_key.title"
+	ConfigSecretKeyTitle       = "plugin.{{info_slug_name}}.backend.config.secret_key.title"
+	ConfigVerifyURLTitle       = "plugin.{{info_slug_name}}.backend.config.verify_url.title"
+	ConfigVerifyURLDescription = "plugin.{{info_slug_name}}.backend.config.verify_url.description"
+	ConfigHostnameTitle        = "plugin.{{info_slug_name}}.backend.config.hostname.title"
+	ConfigHostnameDescription  = "plugin.{{info_slug_name}}.backend.config.hostname.description"
+	ConfigMinScoreTitle        = "plugin.{{info_slug_name}}.backend.config.min_score.title"
+	ConfigMinScoreDescription  = "plugin.{{info_slug_name}}.backend.config.min_score.description"
+)
@@ -0,0 +1,184 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/apache/answer/plugin"
+)
+
+//section:fields
+
+	mu       sync.RWMutex
+	verifier *SiteVerifier
+
+//section:config
+	Provider  string `json:"provider"`
+	SiteKey   string `json:"site_key"`
+	SecretKey string `json:"secret_key"`
+	// VerifyURL overrides the provider's siteverify endpoint, e.g. for a proxy
+	VerifyURL string `json:"verify_url"`
+	// Hostname, when set, rejects widgets solved on another site
+	Hostname string      `json:"hostname"`
+	MinScore json.Number `json:"min_score"`
+
+//section:defaults
+			Provider: "turnstile",
+
+//section:body
+// widgetConfig is what the captcha component needs to render the widget
+type widgetConfig struct {
+	Provider  string `json:"provider"`
+	SiteKey   string `json:"site_key"`
+	ScriptURL string `json:"script_url"`
+}
+
+func (s *{{plugin_display_name}}) ConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{
+		{
+			Name:     "provider",
+			Type:     plugin.ConfigTypeSelect,
+			Title:    plugin.MakeTranslator(i18n.ConfigProviderTitle),
+			Required: true,
+			Value:    s.Config.Provider,
+			Options: []plugin.ConfigFieldOption{
+				{Value: "turnstile", Label: plugin.MakeTranslator(i18n.ConfigProviderTurnstile)},
+				{Value: "hcaptcha", Label: plugin.MakeTranslator(i18n.ConfigProviderHCaptcha)},
+				{Value: "recaptcha", Label: plugin.MakeTranslator(i18n.ConfigProviderRecaptcha)},
+			},
+		},
+		{
+			Name:     "site_key",
+			Type:     plugin.ConfigTypeInput,
+			Title:    plugin.MakeTranslator(i18n.ConfigSiteKeyTitle),
+			Required: true,
+			Value:    s.Config.SiteKey,
+		},
+		{
+			Name:      "secret_key",
+			Type:      plugin.ConfigTypeInput,
+			Title:     plugin.MakeTranslator(i18n.ConfigSecretKeyTitle),
+			Required:  true,
+			UIOptions: plugin.ConfigFieldUIOptions{InputType: plugin.InputTypePassword},
+			Value:     s.Config.SecretKey,
+		},
+		{
+			Name:        "verify_url",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigVerifyURLTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigVerifyURLDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeUrl},
+			Value:       s.Config.VerifyURL,
+		},
+		{
+			Name:        "hostname",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigHostnameTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigHostnameDescription),
+			Value:       s.Config.Hostname,
+		},
+		{
+			Name:        "min_score",
+			Type:        plugin.ConfigTypeInput,
+			Title:       plugin.MakeTranslator(i18n.ConfigMinScoreTitle),
+			Description: plugin.MakeTranslator(i18n.ConfigMinScoreDescription),
+			UIOptions:   plugin.ConfigFieldUIOptions{InputType: plugin.InputTypeNumber},
+			Value:       s.Config.MinScore.String(),
+		},
+	}
+}
+
+func (s *{{plugin_display_name}}) ConfigReceiver(config []byte) error {
+	c := &{{plugin_display_name}}Config{}
+	if err := json.Unmarshal(config, c); err != nil {
+		return err
+	}
+	provider, ok := providers[c.Provider]
+	if !ok {
+		return fmt.Errorf("unknown captcha provider %q", c.Provider)
+	}
+	if c.SiteKey == "" || c.SecretKey == "" {
+		return fmt.Errorf("the site key and the secret key are required")
+	}
+	endpoint := provider.VerifyURL
+	if c.VerifyURL != "" {
+		u, err := url.Parse(c.VerifyURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid verify URL %q", c.VerifyURL)
+		}
+		endpoint = c.VerifyURL
+	}
+	minScore := 0.0
+	if c.MinScore != "" {
+		score, err := strconv.ParseFloat(c.MinScore.String(), 64)
+		if err != nil || score < 0 || score > 1 {
+			return fmt.Errorf("invalid minimum score %q, between 0 and 1", c.MinScore)
+		}
+		minScore = score
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Config = c
+	s.verifier = NewSiteVerifier(nil, endpoint, c.SecretKey, c.Hostname, minScore)
+	return nil
+}
+
+func (s *{{plugin_display_name}}) siteVerifier() *SiteVerifier {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.verifier
+}
+
+// GetConfig is passed to the captcha component, the secret key stays on the server
+func (s *{{plugin_display_name}}) GetConfig() (configJSON string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	provider := providers[s.Config.Provider]
+	data, err := json.Marshal(widgetConfig{
+		Provider:  provider.Name,
+		SiteKey:   s.Config.SiteKey,
+		ScriptURL: provider.ScriptURL,
+	})
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// Create returns nothing, the widget brings its own challenge
+func (s *{{plugin_display_name}}) Create() (captcha, code string) {
+	return "", ""
+}
+
+// Verify checks the widget token a user sent as their answer
+func (s *{{plugin_display_name}}) Verify(captcha, userInput string) (pass bool) {
+	v := s.siteVerifier()
+	if v == nil {
+		log.Printf("{{plugin_slug_name}}: captcha is not configured, rejecting the answer")
+		return false
+	}
+	return verify(v, captcha, userInput)
+}
@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Provider is a captcha service speaking the siteverify protocol
+type Provider struct {
+	Name      string
+	ScriptURL string
+	VerifyURL string
+}
+
+var providers = map[string]Provider{
+	"recaptcha": {
+		Name:      "recaptcha",
+		ScriptURL: "https://www.google.com/recaptcha/api.js?render=explicit",
+		VerifyURL: "https://www.google.com/recaptcha/api/siteverify",
+	},
+	"hcaptcha": {
+		Name:      "hcaptcha",
+		ScriptURL: "https://js.hcaptcha.com/1/api.js?render=explicit",
+		VerifyURL: "https://api.hcaptcha.com/siteverify",
+	},
+	"turnstile": {
+		Name:      "turnstile",
+		ScriptURL: "https://challenges.cloudflare.com/turnstile/v0/api.js?render=explicit",
+		VerifyURL: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	},
+}
+
+// configErrorCodes mean the plugin is set up wrong rather than the user failing the check
+var configErrorCodes = []string{
+	"missing-input-secret",
+	"invalid-input-secret",
+	"sitekey-secret-mismatch",
+}
+
+// siteverifyResponse covers the fields the three services have in common
+type siteverifyResponse struct {
+	Success    bool     `json:"success"`
+	Hostname   string   `json:"hostname"`
+	Score      *float64 `json:"score"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// SiteVerifier checks widget tokens with a siteverify endpoint. The widget runs in the
+// browser, so the token it hands out proves nothing until the service confirms it.
+type SiteVerifier struct {
+	client   *http.Client
+	endpoint string
+	secret   string
+	// hostname, when set, must match the site the widget was solved on
+	hostname string
+	// minScore rejects reCAPTCHA v3 and hCaptcha Enterprise answers scoring lower
+	minScore float64
+}
+
+// NewSiteVerifier posts to endpoint with secret, client is http.DefaultClient when nil
+func NewSiteVerifier(client *http.Client, endpoint, secret, hostname string, minScore float64) *SiteVerifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &SiteVerifier{
+		client:   client,
+		endpoint: endpoint,
+		secret:   secret,
+		hostname: hostname,
+		minScore: minScore,
+	}
+}
+
+// Verify asks the service whether token is a solved widget. The first argument is the
+// captcha Create returned, the widget needs none.
+func (v *SiteVerifier) Verify(ctx context.Context, _, token string) (bool, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return false, nil
+	}
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("siteverify returned %s", resp.Status)
+	}
+
+	result := &siteverifyResponse{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(result); err != nil {
+		return false, fmt.Errorf("decode siteverify response: %w", err)
+	}
+	for _, code := range result.ErrorCodes {
+		if slices.Contains(configErrorCodes, code) {
+			return false, fmt.Errorf("siteverify rejected the secret key: %s", code)
+		}
+	}
+	if !result.Success {
+		return false, nil
+	}
+	if v.hostname != "" && !strings.EqualFold(result.Hostname, v.hostname) {
+		return false, nil
+	}
+	if v.minScore > 0 && result.Score != nil && *result.Score < v.minScore {
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"{{testkit_module}}"
+)
+
+// newStubEndpoint stands in for a siteverify endpoint. It accepts the secret
+// "test-secret" and answers by token.
+func newStubEndpoint(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.ParseForm() != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		resp := map[string]any{"success": false, "hostname": "forum.example.com"}
+		switch token := r.PostForm.Get("response"); {
+		case r.PostForm.Get("secret") != "test-secret":
+			resp["error-codes"] = []string{"invalid-input-secret"}
+		case token == "solved":
+			resp["success"] = true
+		case token == "low-score":
+			resp["success"], resp["score"] = true, 0.2
+		case token == "other-site":
+			resp["success"], resp["hostname"] = true, "evil.example.com"
+		case token == "broken":
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+			return
+		case token == "slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		default:
+			resp["error-codes"] = []string{"invalid-input-response"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSiteVerifierVerify(t *testing.T) {
+	server := newStubEndpoint(t)
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		pass    bool
+		wantErr bool
+	}{
+		{"solved", "test-secret", "solved", true, false},
+		{"unknown token", "test-secret", "forged", false, false},
+		{"empty token", "test-secret", " ", false, false},
+		{"score too low", "test-secret", "low-score", false, false},
+		{"solved on another site", "test-secret", "other-site", false, false},
+		{"wrong secret", "other-secret", "solved", false, true},
+		{"endpoint error", "test-secret", "broken", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewSiteVerifier(server.Client(), server.URL, tt.secret, "forum.example.com", 0.5)
+			pass, err := v.Verify(context.Background(), "", tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, want error %v", err, tt.wantErr)
+			}
+			if pass != tt.pass {
+				t.Fatalf("Verify() = %v, want %v", pass, tt.pass)
+			}
+		})
+	}
+}
+
+func TestSiteVerifierTimeout(t *testing.T) {
+	server := newStubEndpoint(t)
+	v := NewSiteVerifier(server.Client(), server.URL, "test-secret", "", 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if pass, err := v.Verify(ctx, "", "slow"); pass || err == nil {
+		t.Fatalf("Verify() = %v, %v, want a timeout error", pass, err)
+	}
+}
+
+func TestPluginVerify(t *testing.T) {
+	server := newStubEndpoint(t)
+	s := &{{plugin_display_name}}{Config: &{{plugin_display_name}}Config{}}
+	output := testkit.CaptureOutput(t)
+	if s.Verify("", "solved") {
+		t.Fatal("an unconfigured captcha passed")
+	}
+	if !strings.Contains(output.String(), "not configured") {
+		t.Errorf("the unconfigured captcha wasn't logged, got %q", output)
+	}
+
+	config, _ := json.Marshal(map[string]any{
+		"provider":   "hcaptcha",
+		"site_key":   "site-key",
+		"secret_key": "test-secret",
+		"verify_url": server.URL,
+	})
+	if err := s.ConfigReceiver(config); err != nil {
+		t.Fatalf("ConfigReceiver: %v", err)
+	}
+	if !s.Verify("", "solved") {
+		t.Error("a solved widget failed")
+	}
+	if s.Verify("", "broken") {
+		t.Error("a token that couldn't be checked passed")
+	}
+	if !strings.Contains(output.String(), "captcha check failed") {
+		t.Errorf("the failed check wasn't logged, got %q", output)
+	}
+
+	widget := widgetConfig{}
+	if err := json.Unmarshal([]byte(s.GetConfig()), &widget); err != nil {
+		t.Fatal(err)
+	}
+	if widget != (widgetConfig{"hcaptcha", "site-key", providers["hcaptcha"].ScriptURL}) {
+		t.Errorf("GetConfig() = %+v", widget)
+	}
+	if image, code := s.Create(); image != "" || code != "" {
+		t.Errorf("Create() = %q, %q, want nothing", image, code)
+	}
+}
+
+func TestPluginConfigReceiver(t *testing.T) {
+	for name, config := range map[string]map[string]any{
+		"unknown provider":   {"provider": "other", "site_key": "k", "secret_key": "s"},
+		"missing secret":     {"provider": "turnstile", "site_key": "k"},
+		"invalid URL":        {"provider": "turnstile", "site_key": "k", "secret_key": "s", "verify_url": "ftp://x"},
+		"score out of range": {"provider": "recaptcha", "site_key": "k", "secret_key": "s", "min_score": "1.5"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := &{{plugin_display_name}}{Config: &{{plugin_display_name}}Config{}}
+			data, _ := json.Marshal(config)
+			if err := s.ConfigReceiver(data); err == nil {
+				t.Fatal("ConfigReceiver accepted an invalid config")
+			}
+			if s.siteVerifier() != nil {
+				t.Fatal("an invalid config was applied")
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+import { FC, useMemo } from 'react';
+
+import { renderHTML } from './render';
+
+export interface RenderProps {
+  content: string;
+}
+
+// Renders the chart fences in the post, the server renders them the same way
+// for feeds, emails and search engines
+const Component: FC<RenderProps> = ({ content }) => {
+  const html = useMemo(() => renderHTML(content || ''), [content]);
+
+  return <div dangerouslySetInnerHTML={{ __html: html }} />;
+};
+
+export default Component;
@@ -0,0 +1,143 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FenceLanguage is the code fence this plugin renders:
+//
+//	```chart
+//	# Answers per day
+//	Monday: 12
+//	Tuesday: 7.5
+//	```
+//
+// render.ts renders the same syntax in the browser. Both have to produce the same
+// markup, testdata/golden holds the cases they are tested against.
+const FenceLanguage = "chart"
+
+const (
+	maxBars     = 50
+	labelWidth  = 120
+	barMaxWidth = 300
+	barHeight   = 20
+	rowHeight   = 28
+	chartWidth  = labelWidth + barMaxWidth + 60
+)
+
+var chartValue = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// Chart is a parsed chart fence
+type Chart struct {
+	Title string
+	Bars  []Bar
+}
+
+// Bar is a "label: value" line, Value keeps the text to show it as written
+type Bar struct {
+	Label string
+	Value string
+	value float64
+}
+
+// ParseChart reads the lines of a chart fence: "# title" and "label: value"
+func ParseChart(source string) (*Chart, error) {
+	chart := &Chart{}
+	for i, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if title, ok := strings.CutPrefix(line, "#"); ok {
+			chart.Title = strings.TrimSpace(title)
+			continue
+		}
+		sep := strings.LastIndex(line, ":")
+		if sep < 0 || strings.TrimSpace(line[:sep]) == "" {
+			return nil, fmt.Errorf("line %d: expected \"label: value\"", i+1)
+		}
+		value := strings.TrimSpace(line[sep+1:])
+		if !chartValue.MatchString(value) {
+			return nil, fmt.Errorf("line %d: invalid value \"%s\"", i+1, value)
+		}
+		if len(chart.Bars) == maxBars {
+			return nil, fmt.Errorf("more than %d bars", maxBars)
+		}
+		v, _ := strconv.ParseFloat(value, 64)
+		chart.Bars = append(chart.Bars, Bar{Label: strings.TrimSpace(line[:sep]), Value: value, value: v})
+	}
+	if len(chart.Bars) == 0 {
+		return nil, fmt.Errorf("no bars, add lines like \"label: 3\"")
+	}
+	return chart, nil
+}
+
+// RenderChart renders a chart fence as a static SVG figure, or the source and the
+// error when it can't be parsed
+func RenderChart(source string) string {
+	chart, err := ParseChart(source)
+	if err != nil {
+		return `<figure class="chart chart-error"><pre>` + escape(source) + "</pre><figcaption>" +
+			escape(err.Error()) + "</figcaption></figure>"
+	}
+
+	maxValue := 0.0
+	for _, bar := range chart.Bars {
+		maxValue = max(maxValue, bar.value)
+	}
+	height := len(chart.Bars)*rowHeight - (rowHeight - barHeight)
+
+	b := &strings.Builder{}
+	b.WriteString(`<figure class="chart">`)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="%s">`,
+		chartWidth, height, chartWidth, height, escape(chart.Title))
+	for i, bar := range chart.Bars {
+		width := 0
+		if maxValue > 0 {
+			width = int(roundHalfUp(bar.value / maxValue * barMaxWidth))
+		}
+		fmt.Fprintf(b, "\n"+`<g transform="translate(0,%d)">`, i*rowHeight)
+		fmt.Fprintf(b, `<text x="%d" y="15" text-anchor="end">%s</text>`, labelWidth-8, escape(bar.Label))
+		fmt.Fprintf(b, `<rect x="%d" y="0" width="%d" height="%d" fill="currentColor" opacity="0.6"/>`,
+			labelWidth, width, barHeight)
+		fmt.Fprintf(b, `<text x="%d" y="15">%s</text></g>`, labelWidth+width+6, escape(bar.Value))
+	}
+	b.WriteString("\n</svg>")
+	if chart.Title != "" {
+		b.WriteString("<figcaption>" + escape(chart.Title) + "</figcaption>")
+	}
+	b.WriteString("</figure>")
+	return b.String()
+}
+
+// roundHalfUp rounds like JavaScript's Math.round, so both renderers agree
+func roundHalfUp(x float64) float64 {
+	return float64(int64(x + 0.5))
+}
+
+// escape escapes like render.ts does
+func escape(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"github.com/apache/answer/plugin"
+	"github.com/gin-gonic/gin"
+	"github.com/yuin/goldmark"
+)
+
+//section:body
+var _ plugin.Render = (*{{plugin_display_name}})(nil)
+
+// GetRenderConfig is what Answer calls on a render plugin, for the code highlighting theme
+// of the editor and the posts. The chart has no theme, an empty one keeps Answer's default.
+func (r *{{plugin_display_name}}) GetRenderConfig(ctx *gin.Context) *plugin.RenderConfig {
+	return &plugin.RenderConfig{}
+}
+
+// Extend adds the chart renderer to a goldmark converter like the one Answer renders
+// posts with, so feeds, emails and pages rendered on the server show the chart too.
+// Answer doesn't call it: its converter takes no extensions from plugins, so whoever
+// builds Answer with the plugin has to add it to that converter themselves.
+func (r *{{plugin_display_name}}) Extend(m goldmark.Markdown) {
+	NewExtension().Extend(m)
+}
+
+// RenderHTML renders the chart fences left in HTML that was converted before. Answer
+// doesn't call it either, call it where stored HTML leaves Answer, e.g. a feed or an email.
+func (r *{{plugin_display_name}}) RenderHTML(content string) string {
+	return RenderHTML(content)
+}
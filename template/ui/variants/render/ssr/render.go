@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"html"
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// fenceHTML matches a chart fence in HTML rendered from Markdown
+var fenceHTML = regexp.MustCompile(`(?s)<pre><code class="language-` + FenceLanguage + `">(.*?)</code></pre>`)
+
+// RenderHTML replaces the chart fences in HTML that was rendered before, e.g. stored
+// posts shown in feeds and emails
+func RenderHTML(content string) string {
+	return fenceHTML.ReplaceAllStringFunc(content, func(fence string) string {
+		return RenderChart(html.UnescapeString(fenceHTML.FindStringSubmatch(fence)[1]))
+	})
+}
+
+// fenceRenderer renders chart fences while Markdown is converted to HTML, other fences
+// are rendered the way goldmark does
+type fenceRenderer struct{}
+
+// NewExtension returns a goldmark extension rendering chart fences, Answer converts
+// Markdown to HTML with goldmark
+func NewExtension() goldmark.Extender {
+	return &fenceRenderer{}
+}
+
+func (r *fenceRenderer) Extend(m goldmark.Markdown) {
+	// Goldmark's own renderers have priority 1000, lower runs first
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(r, 100)))
+}
+
+func (r *fenceRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFence)
+}
+
+func (r *fenceRenderer) renderFence(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.FencedCodeBlock)
+	language := n.Language(source)
+
+	lines := n.Lines()
+	code := make([]byte, 0, lines.Len()*16)
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		code = append(code, line.Value(source)...)
+	}
+
+	if string(language) == FenceLanguage {
+		_, _ = w.WriteString(RenderChart(string(code)) + "\n")
+		return ast.WalkSkipChildren, nil
+	}
+	_, _ = w.WriteString("<pre><code")
+	if language != nil {
+		_, _ = w.WriteString(` class="language-`)
+		gmhtml.DefaultWriter.Write(w, language)
+		_ = w.WriteByte('"')
+	}
+	_ = w.WriteByte('>')
+	gmhtml.DefaultWriter.RawWrite(w, code)
+	_, _ = w.WriteString("</code></pre>\n")
+	return ast.WalkSkipChildren, nil
+}
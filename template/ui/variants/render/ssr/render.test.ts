@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+import { readdirSync, readFileSync } from 'node:fs';
+import { join } from 'node:path';
+import { describe, expect, it } from 'vitest';
+
+import { renderChart, renderHTML } from './render';
+
+// The cases render_test.go checks the Go renderer against, run go test -update
+// to write the expected.html of a new case
+const GOLDEN = join(__dirname, 'testdata', 'golden');
+
+describe('renderChart', () => {
+  readdirSync(GOLDEN).forEach((name) => {
+    it(`matches testdata/golden/${name}`, () => {
+      const input = readFileSync(join(GOLDEN, name, 'input.chart'), 'utf-8');
+      const expected = readFileSync(
+        join(GOLDEN, name, 'expected.html'),
+        'utf-8',
+      );
+      expect(renderChart(input)).toBe(expected);
+    });
+  });
+});
+
+describe('renderHTML', () => {
+  it('renders chart fences and keeps other code', () => {
+    const html =
+      '<p>Answers:</p>\n' +
+      '<pre><code class="language-chart">Q&amp;A: 3\n</code></pre>\n' +
+      '<pre><code class="language-go">ok := 1 &lt; 2\n</code></pre>\n';
+    expect(renderHTML(html)).toBe(
+      '<p>Answers:</p>\n' +
+        renderChart('Q&A: 3\n') +
+        '\n<pre><code class="language-go">ok := 1 &lt; 2\n</code></pre>\n',
+    );
+  });
+});
@@ -0,0 +1,156 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+// Renders chart fences like chart.go and render.go do on the server. Both
+// have to produce the same markup, testdata/golden holds the cases they are
+// tested against.
+
+export const FENCE_LANGUAGE = 'chart';
+
+const MAX_BARS = 50;
+const LABEL_WIDTH = 120;
+const BAR_MAX_WIDTH = 300;
+const BAR_HEIGHT = 20;
+const ROW_HEIGHT = 28;
+const CHART_WIDTH = LABEL_WIDTH + BAR_MAX_WIDTH + 60;
+
+const CHART_VALUE = /^\d+(\.\d+)?$/;
+const FENCE_HTML = new RegExp(
+  `<pre><code class="language-${FENCE_LANGUAGE}">([\\s\\S]*?)</code></pre>`,
+  'g',
+);
+
+export interface Bar {
+  label: string;
+  // The value as written
+  value: string;
+}
+
+export interface Chart {
+  title: string;
+  bars: Bar[];
+}
+
+// Escapes like Go's html.EscapeString
+export const escape = (s: string): string =>
+  s.replace(
+    /[&'<>"]/g,
+    (c) =>
+      ({ '&': '&amp;', "'": '&#39;', '<': '&lt;', '>': '&gt;', '"': '&#34;' })[
+        c
+      ] as string,
+  );
+
+const NAMED_ENTITIES: Record<string, string> = {
+  amp: '&',
+  lt: '<',
+  gt: '>',
+  quot: '"',
+  apos: "'",
+};
+
+// Unescapes the entities Markdown renderers write
+const unescape = (s: string): string =>
+  s.replace(/&(#x[0-9a-f]+|#\d+|\w+);/gi, (entity, name: string) => {
+    if (name[0] === '#') {
+      const hex = name[1] === 'x' || name[1] === 'X';
+      return String.fromCodePoint(
+        parseInt(name.slice(hex ? 2 : 1), hex ? 16 : 10),
+      );
+    }
+    return NAMED_ENTITIES[name] ?? entity;
+  });
+
+// Reads the lines of a chart fence: "# title" and "label: value"
+export const parseChart = (source: string): Chart => {
+  const chart: Chart = { title: '', bars: [] };
+  source.split('\n').forEach((raw, i) => {
+    const line = raw.trim();
+    if (line === '') {
+      return;
+    }
+    if (line.startsWith('#')) {
+      chart.title = line.slice(1).trim();
+      return;
+    }
+    const sep = line.lastIndexOf(':');
+    if (sep < 0 || line.slice(0, sep).trim() === '') {
+      throw new Error(`line ${i + 1}: expected "label: value"`);
+    }
+    const value = line.slice(sep + 1).trim();
+    if (!CHART_VALUE.test(value)) {
+      throw new Error(`line ${i + 1}: invalid value "${value}"`);
+    }
+    if (chart.bars.length === MAX_BARS) {
+      throw new Error(`more than ${MAX_BARS} bars`);
+    }
+    chart.bars.push({ label: line.slice(0, sep).trim(), value });
+  });
+  if (chart.bars.length === 0) {
+    throw new Error('no bars, add lines like "label: 3"');
+  }
+  return chart;
+};
+
+// Renders a chart fence as a static SVG figure, or the source and the error
+// when it can't be parsed
+export const renderChart = (source: string): string => {
+  let chart: Chart;
+  try {
+    chart = parseChart(source);
+  } catch (err) {
+    return (
+      `<figure class="chart chart-error"><pre>${escape(source)}</pre>` +
+      `<figcaption>${escape((err as Error).message)}</figcaption></figure>`
+    );
+  }
+
+  const values = chart.bars.map((bar) => parseFloat(bar.value));
+  const maxValue = Math.max(0, ...values);
+  const height = chart.bars.length * ROW_HEIGHT - (ROW_HEIGHT - BAR_HEIGHT);
+  const title = escape(chart.title);
+  let out =
+    '<figure class="chart">' +
+    '<svg xmlns="http://www.w3.org/2000/svg" ' +
+    `viewBox="0 0 ${CHART_WIDTH} ${height}" ` +
+    `width="${CHART_WIDTH}" height="${height}" role="img" ` +
+    `aria-label="${title}">`;
+  chart.bars.forEach((bar, i) => {
+    const width =
+      maxValue > 0 ? Math.round((values[i] / maxValue) * BAR_MAX_WIDTH) : 0;
+    out +=
+      `\n<g transform="translate(0,${i * ROW_HEIGHT})">` +
+      `<text x="${LABEL_WIDTH - 8}" y="15" text-anchor="end">` +
+      `${escape(bar.label)}</text>` +
+      `<rect x="${LABEL_WIDTH}" y="0" width="${width}" ` +
+      `height="${BAR_HEIGHT}" fill="currentColor" opacity="0.6"/>` +
+      `<text x="${LABEL_WIDTH + width + 6}" y="15">` +
+      `${escape(bar.value)}</text></g>`;
+  });
+  out += '\n</svg>';
+  if (chart.title !== '') {
+    out += `<figcaption>${title}</figcaption>`;
+  }
+  return out + '</figure>';
+};
+
+// Replaces the chart fences in HTML rendered from Markdown
+export const renderHTML = (content: string): string =>
+  content.replace(FENCE_HTML, (_, code: string) =>
+    renderChart(unescape(code)),
+  );
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package {{package_name}}
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected.html files with the current output")
+
+// TestGolden renders each testdata/golden/<case>/input.chart and compares the result
+// with expected.html. render.test.ts checks the TypeScript renderer against the same
+// files. Add a case by adding a directory, run go test -update to write its expected.html.
+func TestGolden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "golden", "*"))
+	if err != nil || len(dirs) == 0 {
+		t.Fatalf("no golden cases: %v", err)
+	}
+	for _, dir := range dirs {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join(dir, "input.chart"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := RenderChart(string(input))
+
+			expectedPath := filepath.Join(dir, "expected.html")
+			if *update {
+				if err := os.WriteFile(expectedPath, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(expectedPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(expected) {
+				t.Errorf("got:\n%s\nwant:\n%s", got, expected)
+			}
+		})
+	}
+}
+
+const markdown = "Answers this week:\n\n```chart\nMonday: 3\nTuesday: 5\n```\n\n```go\nok := 1 < 2\n```\n"
+
+func convert(t *testing.T, md goldmark.Markdown, source string) string {
+	t.Helper()
+	out := &bytes.Buffer{}
+	if err := md.Convert([]byte(source), out); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestExtension(t *testing.T) {
+	got := convert(t, goldmark.New(goldmark.WithExtensions(NewExtension())), markdown)
+	want := "<p>Answers this week:</p>\n" + RenderChart("Monday: 3\nTuesday: 5\n") + "\n" +
+		// Other fences are rendered like goldmark does
+		convert(t, goldmark.New(), "```go\nok := 1 < 2\n```\n")
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderHTMLMatchesExtension(t *testing.T) {
+	stored := convert(t, goldmark.New(), markdown)
+	rendered := convert(t, goldmark.New(goldmark.WithExtensions(NewExtension())), markdown)
+	if got := RenderHTML(stored); got != rendered {
+		t.Errorf("got:\n%s\nwant:\n%s", got, rendered)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"go/format"
+	"go/parser"
+	"go/token"
+)
+
+// FormatSource formats Go source the way gofmt does. Source that doesn't
+// parse is an error at its file:line:col.
+func FormatSource(filename string, src []byte, args []string) ([]byte, error) {
+	if len(args) > 0 {
+		return nil, errors.New("source format takes no import paths or modules")
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
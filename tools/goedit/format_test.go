@@ -0,0 +1,11 @@
+package main
+
+import "testing"
+
+func TestFormatSource(t *testing.T) {
+	testEdit(t, FormatSource, "plugin.go",
+		"package x\nimport (\n\"fmt\"\n)\ntype P struct {\nA int\nLonger string\n}\nfunc (p *P) F( ) { fmt.Println(p.A) }\n", nil,
+		"package x\n\nimport (\n\t\"fmt\"\n)\n\ntype P struct {\n\tA      int\n\tLonger string\n}\n\nfunc (p *P) F() { fmt.Println(p.A) }\n", "")
+	testEdit(t, FormatSource, "plugin.go", "package x\n\nfunc (p *P) F( {\n", nil, "", "plugin.go:3:16: expected ')', found '{'")
+	testEdit(t, FormatSource, "plugin.go", "package x\n", []string{"fmt"}, "", "takes no import paths")
+}
@@ -0,0 +1,5 @@
+module github.com/answerdev/create-answer-plugin/tools/goedit
+
+go 1.23.0
+
+require golang.org/x/mod v0.26.0
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// moduleArg is a module required at a version, replaced by a directory
+// unless it's published
+type moduleArg struct {
+	Path    string
+	Version string
+	Dir     string
+}
+
+// parseModuleArgs parses MODULE@VERSION[=DIR] arguments
+func parseModuleArgs(args []string) ([]moduleArg, error) {
+	mods := make([]moduleArg, 0, len(args))
+	for _, arg := range args {
+		mv, dir, local := strings.Cut(arg, "=")
+		i := strings.LastIndex(mv, "@")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid module %q, want MODULE@VERSION[=DIR]", arg)
+		}
+		mod := moduleArg{Path: mv[:i], Version: mv[i+1:], Dir: dir}
+		if err := module.Check(mod.Path, mod.Version); err != nil {
+			return nil, err
+		}
+		if local && !modfile.IsDirectoryPath(dir) {
+			return nil, fmt.Errorf("invalid module %q: %s isn't a local directory", arg, dir)
+		}
+		mods = append(mods, mod)
+	}
+	return mods, nil
+}
+
+// AddModules requires each module of MODULE@VERSION[=DIR] arguments.
+//
+// A module with a directory is replaced by it. If it's required already it
+// keeps its version, its other replacements, for any version or directory,
+// are dropped. A published module, without a directory, is required at the
+// version and its replacements are dropped.
+func AddModules(filename string, src []byte, args []string) ([]byte, error) {
+	mods, err := parseModuleArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	f, err := modfile.Parse(filename, src, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mod := range mods {
+		if mod.Dir == "" {
+			if err := f.AddRequire(mod.Path, mod.Version); err != nil {
+				return nil, err
+			}
+			if err := dropReplaces(f, mod.Path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !requires(f, mod.Path) {
+			if err := f.AddRequire(mod.Path, mod.Version); err != nil {
+				return nil, err
+			}
+		}
+		// The first replacement of the module, for any version, is
+		// rewritten in place and the others are dropped
+		n := len(f.Replace)
+		if err := f.AddReplace(mod.Path, "", mod.Dir, ""); err != nil {
+			return nil, err
+		}
+		if len(f.Replace) > n {
+			joinReplaceBlock(f, f.Replace[n].Syntax)
+		}
+	}
+	f.SortBlocks()
+	f.Cleanup()
+	return modfile.Format(f.Syntax), nil
+}
+
+// RemoveModules drops the requirements and the replacements of the modules
+func RemoveModules(filename string, src []byte, paths []string) ([]byte, error) {
+	for _, path := range paths {
+		if err := module.CheckPath(path); err != nil {
+			return nil, err
+		}
+	}
+	f, err := modfile.Parse(filename, src, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		if err := f.DropRequire(path); err != nil {
+			return nil, err
+		}
+		if err := dropReplaces(f, path); err != nil {
+			return nil, err
+		}
+	}
+	f.SortBlocks()
+	f.Cleanup()
+	return modfile.Format(f.Syntax), nil
+}
+
+// goModJSON is what ModJSON prints, in the shape of go mod edit -json
+type goModJSON struct {
+	Module  module.Version
+	Require []module.Version
+}
+
+// ModJSON prints the module path and the requirements of a go.mod as JSON,
+// for the CLI to read it without parsing go.mod itself. The file is parsed
+// leniently, as directives newer than the helper don't matter to it.
+func ModJSON(filename string, src []byte, args []string) ([]byte, error) {
+	if len(args) > 0 {
+		return nil, errors.New("mod json takes no modules")
+	}
+	f, err := modfile.ParseLax(filename, src, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := goModJSON{Require: []module.Version{}}
+	if f.Module != nil {
+		out.Module = f.Module.Mod
+	}
+	for _, r := range f.Require {
+		out.Require = append(out.Require, r.Mod)
+	}
+	data, err := json.MarshalIndent(out, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// joinReplaceBlock moves a new replace line from the end of the file to the
+// last replace block, AddReplace only adds to a block that replaces the same
+// module
+func joinReplaceBlock(f *modfile.File, line *modfile.Line) {
+	if line.InBlock {
+		return
+	}
+	var block *modfile.LineBlock
+	at := -1
+	for i, stmt := range f.Syntax.Stmt {
+		switch stmt := stmt.(type) {
+		case *modfile.LineBlock:
+			if stmt.Token[0] == "replace" {
+				block = stmt
+			}
+		case *modfile.Line:
+			if stmt == line {
+				at = i
+			}
+		}
+	}
+	if block == nil || at < 0 {
+		return
+	}
+	f.Syntax.Stmt = append(f.Syntax.Stmt[:at], f.Syntax.Stmt[at+1:]...)
+	line.Token = line.Token[1:]
+	line.InBlock = true
+	block.Line = append(block.Line, line)
+}
+
+// dropReplaces drops the replacements of the module for any version
+func dropReplaces(f *modfile.File, path string) error {
+	for _, r := range f.Replace {
+		if r.Old.Path == path {
+			if err := f.DropReplace(r.Old.Path, r.Old.Version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func requires(f *modfile.File, path string) bool {
+	for _, r := range f.Require {
+		if r.Mod.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// AddWorkspaceDirs adds use directives for the directories to the go.work
+// file. A missing file is created with the go version of the go.mod next to
+// it, using its module too.
+func AddWorkspaceDirs(filename string, src []byte, dirs []string) ([]byte, error) {
+	if err := checkWorkspaceDirs(dirs); err != nil {
+		return nil, err
+	}
+	if src == nil {
+		goMod := filepath.Join(filepath.Dir(filename), "go.mod")
+		data, err := os.ReadFile(goMod)
+		if err != nil {
+			return nil, err
+		}
+		mod, err := modfile.ParseLax(goMod, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		if mod.Go == nil {
+			return nil, fmt.Errorf("%s: no go directive", goMod)
+		}
+		src = []byte("go " + mod.Go.Version + "\n")
+		dirs = append([]string{"."}, dirs...)
+	}
+	f, err := modfile.ParseWork(filename, src, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if findUse(f, dir) == nil {
+			if err := f.AddUse(dir, ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+	f.SortBlocks()
+	f.Cleanup()
+	return modfile.Format(f.Syntax), nil
+}
+
+// RemoveWorkspaceDirs drops the use directives of the directories from the
+// go.work file
+func RemoveWorkspaceDirs(filename string, src []byte, dirs []string) ([]byte, error) {
+	if err := checkWorkspaceDirs(dirs); err != nil {
+		return nil, err
+	}
+	f, err := modfile.ParseWork(filename, src, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if use := findUse(f, dir); use != nil {
+			if err := f.DropUse(use.Path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	f.Cleanup()
+	return modfile.Format(f.Syntax), nil
+}
+
+func checkWorkspaceDirs(dirs []string) error {
+	for _, dir := range dirs {
+		if !modfile.IsDirectoryPath(dir) {
+			return fmt.Errorf("invalid directory %q, want a local path such as ./dir", dir)
+		}
+	}
+	return nil
+}
+
+// findUse returns the use directive of the directory, however it's spelled
+func findUse(f *modfile.WorkFile, dir string) *modfile.Use {
+	for _, use := range f.Use {
+		if filepath.Clean(use.Path) == filepath.Clean(dir) {
+			return use
+		}
+	}
+	return nil
+}
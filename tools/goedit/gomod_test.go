@@ -0,0 +1,199 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const goMod = `module github.com/apache/answer
+
+go 1.23.0
+
+require (
+	github.com/apache/answer-plugins/connector-github v1.2.0
+	github.com/gin-gonic/gin v1.10.0
+)
+
+replace github.com/apache/answer-plugins/connector-github => ./ui/src/plugins/connector-github
+`
+
+func TestAddModules(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		args []string
+		want string
+		err  string
+	}{
+		{
+			name: "local module joins the replace block",
+			src:  goMod,
+			args: []string{"github.com/apache/answer-plugins/storage-s3@v0.0.0-00010101000000-000000000000=./ui/src/plugins/storage-s3"},
+			want: `module github.com/apache/answer
+
+go 1.23.0
+
+require (
+	github.com/apache/answer-plugins/connector-github v1.2.0
+	github.com/apache/answer-plugins/storage-s3 v0.0.0-00010101000000-000000000000
+	github.com/gin-gonic/gin v1.10.0
+)
+
+replace github.com/apache/answer-plugins/connector-github => ./ui/src/plugins/connector-github
+
+replace github.com/apache/answer-plugins/storage-s3 => ./ui/src/plugins/storage-s3
+`,
+		},
+		{
+			name: "existing requirement keeps its version, the replacement is rewritten",
+			src:  goMod,
+			args: []string{"github.com/apache/answer-plugins/connector-github@v0.0.0-00010101000000-000000000000=../connector-github"},
+			want: strings.Replace(goMod, "./ui/src/plugins/connector-github", "../connector-github", 1),
+		},
+		{
+			name: "duplicate add",
+			src:  goMod,
+			args: []string{
+				"github.com/apache/answer-plugins/connector-github@v1.2.0=./ui/src/plugins/connector-github",
+				"github.com/apache/answer-plugins/connector-github@v1.2.0=./ui/src/plugins/connector-github",
+			},
+			want: goMod,
+		},
+		{
+			name: "published module drops the replacement",
+			src:  goMod,
+			args: []string{"github.com/apache/answer-plugins/connector-github@v1.3.0"},
+			want: `module github.com/apache/answer
+
+go 1.23.0
+
+require (
+	github.com/apache/answer-plugins/connector-github v1.3.0
+	github.com/gin-gonic/gin v1.10.0
+)
+`,
+		},
+		{
+			name: "missing require block",
+			src:  "module github.com/apache/answer\n\ngo 1.23.0\n",
+			args: []string{"example.com/a@v1.0.0"},
+			want: "module github.com/apache/answer\n\ngo 1.23.0\n\nrequire example.com/a v1.0.0\n",
+		},
+		{
+			name: "no version",
+			src:  goMod,
+			args: []string{"example.com/a"},
+			err:  `invalid module "example.com/a", want MODULE@VERSION[=DIR]`,
+		},
+		{
+			name: "invalid version",
+			src:  goMod,
+			args: []string{"example.com/a@latest"},
+			err:  "example.com/a@latest",
+		},
+		{
+			name: "replacement that isn't a directory",
+			src:  goMod,
+			args: []string{"example.com/a@v1.0.0=example.com/b"},
+			err:  "example.com/b isn't a local directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testEdit(t, AddModules, "go.mod", tt.src, tt.args, tt.want, tt.err)
+		})
+	}
+}
+
+func TestRemoveModules(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		paths []string
+		want  string
+		err   string
+	}{
+		{
+			name:  "requirement and replacement",
+			src:   goMod,
+			paths: []string{"github.com/apache/answer-plugins/connector-github"},
+			want: `module github.com/apache/answer
+
+go 1.23.0
+
+require github.com/gin-gonic/gin v1.10.0
+`,
+		},
+		{
+			name:  "the last requirement",
+			src:   "module github.com/apache/answer\n\ngo 1.23.0\n\nrequire example.com/a v1.0.0\n",
+			paths: []string{"example.com/a"},
+			want:  "module github.com/apache/answer\n\ngo 1.23.0\n",
+		},
+		{
+			name:  "not required",
+			src:   goMod,
+			paths: []string{"example.com/a"},
+			want:  goMod,
+		},
+		{
+			name:  "invalid module path",
+			src:   goMod,
+			paths: []string{"example.com/a b"},
+			err:   "example.com/a b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testEdit(t, RemoveModules, "go.mod", tt.src, tt.paths, tt.want, tt.err)
+		})
+	}
+}
+
+func TestModJSON(t *testing.T) {
+	testEdit(t, ModJSON, "go.mod", goMod, nil, `{
+	"Module": {
+		"Path": "github.com/apache/answer"
+	},
+	"Require": [
+		{
+			"Path": "github.com/apache/answer-plugins/connector-github",
+			"Version": "v1.2.0"
+		},
+		{
+			"Path": "github.com/gin-gonic/gin",
+			"Version": "v1.10.0"
+		}
+	]
+}
+`, "")
+	testEdit(t, ModJSON, "go.mod", "module example.com/plugin\n\ngo 1.23.0\n\nunknown directive\n", nil,
+		"{\n\t\"Module\": {\n\t\t\"Path\": \"example.com/plugin\"\n\t},\n\t\"Require\": []\n}\n", "")
+	testEdit(t, ModJSON, "go.mod", "module example.com/plugin\nrequire (\n", nil, "", "go.mod:2:")
+	testEdit(t, ModJSON, "go.mod", goMod, []string{"example.com/a"}, "", "takes no modules")
+}
+
+func TestWorkspaceDirs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goWork := filepath.Join(dir, "go.work")
+
+	created, err := AddWorkspaceDirs(goWork, nil, []string{"./ui/src/plugins/storage-s3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "go 1.23.0\n\nuse (\n\t.\n\t./ui/src/plugins/storage-s3\n)\n"
+	if string(created) != want {
+		t.Errorf("created:\n%s\nwant:\n%s", created, want)
+	}
+
+	// The same directory spelled differently is used already
+	testEdit(t, AddWorkspaceDirs, goWork, want, []string{"ui/src/plugins/../plugins/storage-s3/"}, "", "invalid directory")
+	testEdit(t, AddWorkspaceDirs, goWork, want, []string{"./ui/src/plugins/storage-s3/"}, want, "")
+	testEdit(t, RemoveWorkspaceDirs, goWork, want, []string{"./ui/src/plugins/storage-s3"}, "go 1.23.0\n\nuse .\n", "")
+	testEdit(t, RemoveWorkspaceDirs, goWork, want, []string{"./ui/src/plugins/connector-github"}, want, "")
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AddBlankImports adds blank imports of the paths to the Go source. They go to
+// the dedicated group, the last group of an import block with nothing but
+// blank imports, which is kept sorted. Without such a group, a new one is
+// started after the other imports. Paths imported already are left as they are.
+func AddBlankImports(filename string, src []byte, paths []string) ([]byte, error) {
+	e, err := newEditor(filename, src, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := map[string]bool{}
+	for _, spec := range e.file.Imports {
+		imported[importPath(spec)] = true
+	}
+	var entries []importEntry
+	for _, path := range paths {
+		if !imported[path] {
+			imported[path] = true
+			entries = append(entries, importEntry{path: path, text: "\t_ " + strconv.Quote(path) + "\n"})
+		}
+	}
+	if len(entries) == 0 {
+		return src, nil
+	}
+
+	if group := e.dedicatedGroup(); group != nil {
+		e.sortGroup(group, entries)
+	} else if decl := e.lastImportBlock(); decl != nil {
+		e.appendGroup(decl, entries)
+	} else if decl := e.lastImportDecl(); decl != nil {
+		e.convertToBlock(decl, entries)
+	} else {
+		at := e.lineEnd(e.offset(e.file.Name.End()))
+		e.replace(at, at, "\nimport (\n"+joinEntries(entries)+")\n")
+	}
+	return e.finish()
+}
+
+// RemoveBlankImports removes the blank imports of the paths from the Go
+// source, with their comments. The comment heading a group stays as long as
+// the group has imports left. Paths that aren't imported are ignored, paths
+// imported by name are an error as the source may use them.
+func RemoveBlankImports(filename string, src []byte, paths []string) ([]byte, error) {
+	e, err := newEditor(filename, src, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	remove := map[string]bool{}
+	for _, path := range paths {
+		remove[path] = true
+	}
+	removed := map[*ast.ImportSpec]bool{}
+	for _, spec := range e.file.Imports {
+		if !remove[importPath(spec)] {
+			continue
+		}
+		if spec.Name == nil || spec.Name.Name != "_" {
+			return nil, fmt.Errorf("%s: %s is not a blank import", e.fset.Position(spec.Pos()), spec.Path.Value)
+		}
+		removed[spec] = true
+	}
+	if len(removed) == 0 {
+		return src, nil
+	}
+
+	for _, decl := range e.importDecls() {
+		left := 0
+		for _, spec := range decl.Specs {
+			if !removed[spec.(*ast.ImportSpec)] {
+				left++
+			}
+		}
+		if left == 0 && len(decl.Specs) > 0 {
+			e.remove(declStart(decl), decl.End())
+			continue
+		}
+		for _, group := range e.groups(decl) {
+			groupLeft := false
+			for _, spec := range group.specs {
+				groupLeft = groupLeft || !removed[spec]
+			}
+			for i, spec := range group.specs {
+				if !removed[spec] {
+					continue
+				}
+				start := specStart(spec)
+				if i == 0 && groupLeft {
+					start = spec.Pos()
+				}
+				e.remove(start, specEnd(spec))
+			}
+		}
+	}
+	return e.finish()
+}
+
+// importEntry is an import of a group, with its comments
+type importEntry struct {
+	path string
+	text string
+}
+
+func joinEntries(entries []importEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(entry.text)
+	}
+	return b.String()
+}
+
+// importGroup is a run of imports of a block not separated by blank lines
+type importGroup struct {
+	decl  *ast.GenDecl
+	specs []*ast.ImportSpec
+}
+
+type replacement struct {
+	start, end int
+	text       string
+}
+
+// editor collects replacements of byte ranges of the parsed source
+type editor struct {
+	filename     string
+	src          []byte
+	fset         *token.FileSet
+	file         *ast.File
+	replacements []replacement
+}
+
+func newEditor(filename string, src []byte, paths []string) (*editor, error) {
+	for _, path := range paths {
+		if !validImportPath(path) {
+			return nil, fmt.Errorf("invalid import path %q", path)
+		}
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	return &editor{filename: filename, src: src, fset: fset, file: file}, nil
+}
+
+func (e *editor) importDecls() []*ast.GenDecl {
+	var decls []*ast.GenDecl
+	for _, decl := range e.file.Decls {
+		if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.IMPORT {
+			decls = append(decls, decl)
+		}
+	}
+	return decls
+}
+
+// lastImportBlock returns the last parenthesized import declaration
+func (e *editor) lastImportBlock() *ast.GenDecl {
+	var block *ast.GenDecl
+	for _, decl := range e.importDecls() {
+		if decl.Lparen.IsValid() {
+			block = decl
+		}
+	}
+	return block
+}
+
+func (e *editor) lastImportDecl() *ast.GenDecl {
+	decls := e.importDecls()
+	if len(decls) == 0 {
+		return nil
+	}
+	return decls[len(decls)-1]
+}
+
+func (e *editor) groups(decl *ast.GenDecl) []*importGroup {
+	var groups []*importGroup
+	prevEnd := 0
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ImportSpec)
+		if len(groups) == 0 || e.line(specStart(spec)) > prevEnd+1 {
+			groups = append(groups, &importGroup{decl: decl})
+		}
+		group := groups[len(groups)-1]
+		group.specs = append(group.specs, spec)
+		prevEnd = e.line(specEnd(spec))
+	}
+	return groups
+}
+
+// dedicatedGroup returns the last group of blank imports, each on lines of
+// its own
+func (e *editor) dedicatedGroup() *importGroup {
+	var dedicated *importGroup
+	for _, decl := range e.importDecls() {
+		if !decl.Lparen.IsValid() {
+			continue
+		}
+		for _, group := range e.groups(decl) {
+			if e.ownLines(group) {
+				dedicated = group
+			}
+		}
+	}
+	return dedicated
+}
+
+func (e *editor) ownLines(group *importGroup) bool {
+	prevEnd := e.line(group.decl.Lparen)
+	for _, spec := range group.specs {
+		if spec.Name == nil || spec.Name.Name != "_" || e.line(specStart(spec)) <= prevEnd {
+			return false
+		}
+		prevEnd = e.line(specEnd(spec))
+	}
+	return prevEnd < e.line(group.decl.Rparen)
+}
+
+// sortGroup adds the entries to the group and sorts it by path. The comment
+// heading the group stays on top.
+func (e *editor) sortGroup(group *importGroup, entries []importEntry) {
+	first := group.specs[0]
+	start := e.lineStart(e.offset(specStart(first)))
+	header := ""
+	at := start
+	if first.Doc != nil {
+		at = e.lineEnd(e.offset(first.Doc.End()))
+		header = string(e.src[start:at])
+	}
+	for _, spec := range group.specs {
+		end := e.lineEnd(e.offset(specEnd(spec)))
+		entries = append(entries, importEntry{path: importPath(spec), text: string(e.src[at:end])})
+		at = end
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+	e.replace(start, at, header+joinEntries(entries))
+}
+
+// appendGroup starts a new group at the end of the import block
+func (e *editor) appendGroup(decl *ast.GenDecl, entries []importEntry) {
+	text := joinEntries(entries)
+	if len(decl.Specs) > 0 {
+		text = "\n" + text
+	}
+	rparen := e.offset(decl.Rparen)
+	at := e.lineStart(rparen)
+	if len(bytes.TrimSpace(e.src[at:rparen])) > 0 {
+		at, text = rparen, "\n"+text
+	}
+	e.replace(at, at, text)
+}
+
+// convertToBlock turns an import declaration without parentheses into a
+// block with the entries in a group of their own
+func (e *editor) convertToBlock(decl *ast.GenDecl, entries []importEntry) {
+	spec := decl.Specs[0].(*ast.ImportSpec)
+	end := e.offset(specEnd(spec))
+	existing := "\t" + string(e.src[e.offset(spec.Pos()):end]) + "\n"
+	if spec.Name != nil && spec.Name.Name == "_" {
+		entries = append(entries, importEntry{path: importPath(spec), text: existing})
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+		existing = ""
+	} else {
+		existing += "\n"
+	}
+	e.replace(e.offset(decl.TokPos), end, "import (\n"+existing+joinEntries(entries)+")")
+}
+
+// remove removes the source from start to end, with the lines holding it when
+// there's nothing else on them
+func (e *editor) remove(start, end token.Pos) {
+	from, to := e.offset(start), e.offset(end)
+	lineStart, lineEnd := e.lineStart(from), e.lineEnd(to)
+	if len(bytes.TrimSpace(e.src[lineStart:from])) == 0 && len(bytes.TrimSpace(e.src[to:lineEnd])) == 0 {
+		from, to = lineStart, lineEnd
+	}
+	e.replace(from, to, "")
+}
+
+func (e *editor) replace(start, end int, text string) {
+	e.replacements = append(e.replacements, replacement{start: start, end: end, text: text})
+}
+
+// finish applies the replacements and formats the result
+func (e *editor) finish() ([]byte, error) {
+	sort.SliceStable(e.replacements, func(i, j int) bool { return e.replacements[i].start < e.replacements[j].start })
+	var b bytes.Buffer
+	at := 0
+	for _, r := range e.replacements {
+		if r.start < at {
+			continue
+		}
+		b.Write(e.src[at:r.start])
+		b.WriteString(r.text)
+		at = r.end
+	}
+	b.Write(e.src[at:])
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: the edited imports don't parse: %v", e.filename, err)
+	}
+	return out, nil
+}
+
+func (e *editor) offset(pos token.Pos) int {
+	return e.fset.Position(pos).Offset
+}
+
+func (e *editor) line(pos token.Pos) int {
+	return e.fset.Position(pos).Line
+}
+
+// lineStart returns the offset of the start of the line holding offset
+func (e *editor) lineStart(offset int) int {
+	return bytes.LastIndexByte(e.src[:offset], '\n') + 1
+}
+
+// lineEnd returns the offset after the newline ending the line holding offset
+func (e *editor) lineEnd(offset int) int {
+	i := bytes.IndexByte(e.src[offset:], '\n')
+	if i < 0 {
+		return len(e.src)
+	}
+	return offset + i + 1
+}
+
+// specStart returns the start of the import with its doc comment
+func specStart(spec *ast.ImportSpec) token.Pos {
+	if spec.Doc != nil {
+		return spec.Doc.Pos()
+	}
+	return spec.Pos()
+}
+
+// specEnd returns the end of the import with its line comment
+func specEnd(spec *ast.ImportSpec) token.Pos {
+	if spec.Comment != nil {
+		return spec.Comment.End()
+	}
+	return spec.End()
+}
+
+func declStart(decl *ast.GenDecl) token.Pos {
+	if decl.Doc != nil {
+		return decl.Doc.Pos()
+	}
+	return decl.Pos()
+}
+
+func importPath(spec *ast.ImportSpec) string {
+	path, _ := strconv.Unquote(spec.Path.Value)
+	return path
+}
+
+// validImportPath reports whether the parser accepts path as an import path
+func validImportPath(path string) bool {
+	const illegalChars = `!"#$%&'()*,:;<=>?[\]^{|}` + "`�"
+	if path == "" {
+		return false
+	}
+	for _, r := range path {
+		if !unicode.IsGraphic(r) || unicode.IsSpace(r) || strings.ContainsRune(illegalChars, r) {
+			return false
+		}
+	}
+	return true
+}
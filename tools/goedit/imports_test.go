@@ -0,0 +1,239 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const mainGo = `package main
+
+import (
+	"fmt"
+
+	answercmd "github.com/apache/answer/cmd"
+
+	// Plugins
+	_ "github.com/apache/answer-plugins/connector-github"
+	_ "github.com/apache/answer-plugins/storage-s3"
+)
+
+func main() {
+	fmt.Println(answercmd.Version)
+}
+`
+
+func TestAddBlankImports(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		paths []string
+		want  string
+		err   string
+	}{
+		{
+			name:  "sorted into the dedicated group",
+			src:   mainGo,
+			paths: []string{"github.com/apache/answer-plugins/search-meili"},
+			want: strings.Replace(mainGo, `	_ "github.com/apache/answer-plugins/storage-s3"`,
+				`	_ "github.com/apache/answer-plugins/search-meili"
+	_ "github.com/apache/answer-plugins/storage-s3"`, 1),
+		},
+		{
+			name:  "existing import",
+			src:   mainGo,
+			paths: []string{"github.com/apache/answer-plugins/storage-s3"},
+			want:  mainGo,
+		},
+		{
+			name:  "imported under an alias",
+			src:   mainGo,
+			paths: []string{"github.com/apache/answer/cmd"},
+			want:  mainGo,
+		},
+		{
+			name:  "duplicate add",
+			src:   mainGo,
+			paths: []string{"example.com/a", "example.com/a"},
+			want: strings.Replace(mainGo, `	// Plugins
+`, `	// Plugins
+	_ "example.com/a"
+`, 1),
+		},
+		{
+			name: "new group after the other imports",
+			src: `package main
+
+import (
+	"fmt"
+)
+
+func main() { fmt.Println() }
+`,
+			paths: []string{"example.com/b", "example.com/a"},
+			want: `package main
+
+import (
+	"fmt"
+
+	_ "example.com/a"
+	_ "example.com/b"
+)
+
+func main() { fmt.Println() }
+`,
+		},
+		{
+			name: "single import turned into a block",
+			src: `package main
+
+import "fmt"
+
+func main() { fmt.Println() }
+`,
+			paths: []string{"example.com/a"},
+			want: `package main
+
+import (
+	"fmt"
+
+	_ "example.com/a"
+)
+
+func main() { fmt.Println() }
+`,
+		},
+		{
+			name: "missing import block",
+			src: `package main
+
+func main() {}
+`,
+			paths: []string{"example.com/a"},
+			want: `package main
+
+import (
+	_ "example.com/a"
+)
+
+func main() {}
+`,
+		},
+		{
+			name:  "invalid import path",
+			src:   mainGo,
+			paths: []string{"example.com/a b"},
+			err:   `invalid import path "example.com/a b"`,
+		},
+		{
+			name:  "source that doesn't parse",
+			src:   "package main\n\nimport (\n",
+			paths: []string{"example.com/a"},
+			err:   "main.go:3:10: expected ')'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testEdit(t, AddBlankImports, "main.go", tt.src, tt.paths, tt.want, tt.err)
+		})
+	}
+}
+
+func TestRemoveBlankImports(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		paths []string
+		want  string
+		err   string
+	}{
+		{
+			name:  "one of the group",
+			src:   mainGo,
+			paths: []string{"github.com/apache/answer-plugins/connector-github"},
+			want:  strings.Replace(mainGo, "\t_ \"github.com/apache/answer-plugins/connector-github\"\n", "", 1),
+		},
+		{
+			name:  "the whole group with its comment",
+			src:   mainGo,
+			paths: []string{"github.com/apache/answer-plugins/connector-github", "github.com/apache/answer-plugins/storage-s3"},
+			want: `package main
+
+import (
+	"fmt"
+
+	answercmd "github.com/apache/answer/cmd"
+)
+
+func main() {
+	fmt.Println(answercmd.Version)
+}
+`,
+		},
+		{
+			name:  "not imported",
+			src:   mainGo,
+			paths: []string{"example.com/a"},
+			want:  mainGo,
+		},
+		{
+			name: "the last import",
+			src: `package main
+
+import (
+	_ "example.com/a"
+)
+
+func main() {}
+`,
+			paths: []string{"example.com/a"},
+			want: `package main
+
+func main() {}
+`,
+		},
+		{
+			name: "the last import without parentheses",
+			src: `package main
+
+// Plugins
+import _ "example.com/a"
+
+func main() {}
+`,
+			paths: []string{"example.com/a"},
+			want: `package main
+
+func main() {}
+`,
+		},
+		{
+			name:  "imported under an alias",
+			src:   mainGo,
+			paths: []string{"github.com/apache/answer/cmd"},
+			err:   `main.go:6:2: "github.com/apache/answer/cmd" is not a blank import`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testEdit(t, RemoveBlankImports, "main.go", tt.src, tt.paths, tt.want, tt.err)
+		})
+	}
+}
+
+// testEdit runs the edit and compares its output, or its error when err is set
+func testEdit(t *testing.T, edit editFunc, filename, src string, args []string, want, err string) {
+	t.Helper()
+	got, gotErr := edit(filename, []byte(src), args)
+	if err != "" {
+		if gotErr == nil || !strings.Contains(gotErr.Error(), err) {
+			t.Fatalf("got error %v, want one containing %q", gotErr, err)
+		}
+		return
+	}
+	if gotErr != nil {
+		t.Fatal(gotErr)
+	}
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
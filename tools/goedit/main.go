@@ -0,0 +1,131 @@
+// Command goedit edits the Go files of an Answer project for the
+// answer-plugin CLI: the imports of a source file, the requirements and
+// replacements of go.mod and the directories of go.work.
+//
+// Usage:
+//
+//	goedit imports add [-w] FILE IMPORT_PATH...
+//	goedit imports remove [-w] FILE IMPORT_PATH...
+//	goedit mod add [-w] GO.MOD MODULE@VERSION[=DIR]...
+//	goedit mod remove [-w] GO.MOD MODULE...
+//	goedit work add [-w] GO.WORK DIR...
+//	goedit work remove [-w] GO.WORK DIR...
+//
+// The edited file is printed to stdout, or written back with -w. A missing
+// go.work is created by work add. Errors are printed to stderr as
+// file:line:col: message and exit with status 1.
+//
+//	goedit source format [-w] [-stdin] FILE
+//
+// formats a Go file like gofmt, and fails if it doesn't parse. With -stdin
+// the source is read from stdin and FILE only names it in errors.
+//
+//	goedit mod json GO.MOD
+//
+// prints the module path and the requirements of go.mod as JSON, like
+// go mod edit -json.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+const usage = `usage: goedit imports add [-w] FILE IMPORT_PATH...
+       goedit imports remove [-w] FILE IMPORT_PATH...
+       goedit mod add [-w] GO.MOD MODULE@VERSION[=DIR]...
+       goedit mod remove [-w] GO.MOD MODULE...
+       goedit work add [-w] GO.WORK DIR...
+       goedit work remove [-w] GO.WORK DIR...
+       goedit source format [-w] [-stdin] FILE
+       goedit mod json GO.MOD
+`
+
+// editFunc edits the source of the file with the arguments of the command
+type editFunc func(filename string, src []byte, args []string) ([]byte, error)
+
+var commands = map[string]editFunc{
+	"imports add":    AddBlankImports,
+	"imports remove": RemoveBlankImports,
+	"mod add":        AddModules,
+	"mod remove":     RemoveModules,
+	"mod json":       ModJSON,
+	"work add":       AddWorkspaceDirs,
+	"work remove":    RemoveWorkspaceDirs,
+	"source format":  FormatSource,
+}
+
+// creates lists the commands that start from a nil source when the file is
+// missing
+var creates = map[string]bool{
+	"work add": true,
+}
+
+// bare lists the commands that take the file alone
+var bare = map[string]bool{
+	"source format": true,
+	"mod json":      true,
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+	name := args[0] + " " + args[1]
+	edit, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q\n%s", name, usage)
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	write := flags.Bool("w", false, "write the result to the file instead of stdout")
+	stdin := flags.Bool("stdin", false, "read the source from stdin, FILE only names it in errors")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	if err := flags.Parse(args[2:]); err != nil {
+		return err
+	}
+	if flags.NArg() < 1 || (flags.NArg() < 2 && !bare[name]) || (*write && *stdin) {
+		return errors.New(usage)
+	}
+
+	filename := flags.Arg(0)
+	perm := fs.FileMode(0o644)
+	var src []byte
+	var err error
+	if *stdin {
+		src, err = io.ReadAll(os.Stdin)
+	} else {
+		src, err = os.ReadFile(filename)
+	}
+	switch {
+	case err == nil:
+		if info, err := os.Stat(filename); err == nil {
+			perm = info.Mode().Perm()
+		}
+	case errors.Is(err, fs.ErrNotExist) && creates[name]:
+		src = nil
+	default:
+		return err
+	}
+
+	out, err := edit(filename, src, flags.Args()[1:])
+	if err != nil {
+		return err
+	}
+	if *write {
+		return os.WriteFile(filename, out, perm)
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
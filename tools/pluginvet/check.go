@@ -0,0 +1,270 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Severity tells whether a finding keeps Answer from using the plugin
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a finding at a file:line:col position
+type Diagnostic struct {
+	Pos      string   `json:"pos"`
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+
+	position token.Position
+}
+
+// templateTODO matches the TODO comments of the generated stubs
+var templateTODO = regexp.MustCompile(`^TODO: (Implement|Register|This is a Hello World example)\b`)
+
+// debugPrints are the functions that write to stdout, where Answer's own
+// output goes
+var debugPrints = map[string]bool{
+	"fmt.Print":   true,
+	"fmt.Printf":  true,
+	"fmt.Println": true,
+	"print":       true,
+	"println":     true,
+}
+
+type analysis struct {
+	pkg         *packages.Package
+	info        *info
+	diagnostics []Diagnostic
+}
+
+// Analyze loads the plugin package of dir and runs the checks on it. Test
+// files aren't loaded.
+func Analyze(dir string) ([]Diagnostic, error) {
+	i, err := readInfo(dir)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%s: want one package, got %d", dir, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		errs := make([]error, len(pkg.Errors))
+		for i, e := range pkg.Errors {
+			errs[i] = e
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	a := &analysis{pkg: pkg, info: i}
+	a.checkRegistration()
+	a.checkTODOs()
+	a.checkDebugOutput()
+	sort.SliceStable(a.diagnostics, func(i, j int) bool {
+		p, q := a.diagnostics[i].position, a.diagnostics[j].position
+		if p.Filename != q.Filename {
+			return p.Filename < q.Filename
+		}
+		return p.Offset < q.Offset
+	})
+	return a.diagnostics, nil
+}
+
+func (a *analysis) report(pos token.Pos, check string, severity Severity, format string, args ...any) {
+	a.reportAt(a.pkg.Fset.Position(pos), check, severity, format, args...)
+}
+
+// reportAt reports at a position of any file, info.yaml has no token.Pos
+func (a *analysis) reportAt(position token.Position, check string, severity Severity, format string, args ...any) {
+	a.diagnostics = append(a.diagnostics, Diagnostic{
+		Pos:      position.String(),
+		Check:    check,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		position: position,
+	})
+}
+
+// qualifier names the types of other packages by package name, and those
+// of the plugin unqualified
+func (a *analysis) qualifier(p *types.Package) string {
+	if p == a.pkg.Types {
+		return ""
+	}
+	return p.Name()
+}
+
+// checkRegistration checks that an init function calls plugin.Register, and
+// that for each type of info.yaml a registered value implements its
+// interface. Answer detects what a plugin does by type assertions on the
+// registered value.
+func (a *analysis) checkRegistration() {
+	var registered []ast.Expr
+	for _, file := range a.pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "init" || fn.Recv != nil || fn.Body == nil {
+				continue
+			}
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok && len(call.Args) == 1 && a.isPluginFunc(call, "Register") {
+					registered = append(registered, call.Args[0])
+				}
+				return true
+			})
+		}
+	}
+	if len(registered) == 0 {
+		a.report(a.pkg.Syntax[0].Name.Pos(), "register", SeverityError,
+			"no init function calls plugin.Register, Answer won't load the plugin")
+		return
+	}
+
+	pluginPkg := a.importedPackage(pluginPkgPath)
+	for _, t := range a.info.types() {
+		name, known := interfaces[t]
+		if !known {
+			a.reportAt(token.Position{Filename: a.info.Path}, "interface", SeverityError, "unknown plugin type %q", t)
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		obj, _ := pluginPkg.Scope().Lookup(name).(*types.TypeName)
+		if obj == nil {
+			a.reportAt(token.Position{Filename: a.info.Path}, "interface", SeverityError,
+				"type %s needs plugin.%s, the Answer version go.mod requires has no such interface", t, name)
+			continue
+		}
+		iface, ok := obj.Type().Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+
+		implemented := false
+		for _, expr := range registered {
+			if types.Implements(a.pkg.TypesInfo.TypeOf(expr), iface) {
+				implemented = true
+				break
+			}
+		}
+		if !implemented {
+			expr := registered[0]
+			a.report(expr.Pos(), "interface", SeverityError,
+				"%s doesn't implement plugin.%s, which type %s of info.yaml needs: %s",
+				types.TypeString(a.pkg.TypesInfo.TypeOf(expr), a.qualifier), name, t,
+				a.missing(a.pkg.TypesInfo.TypeOf(expr), iface))
+		}
+	}
+}
+
+// missing explains why t doesn't implement iface
+func (a *analysis) missing(t types.Type, iface *types.Interface) string {
+	method, wrongType := types.MissingMethod(t, iface, true)
+	if method == nil {
+		return "it's not a plugin"
+	}
+	if _, ok := t.(*types.Pointer); !ok && types.Implements(types.NewPointer(t), iface) {
+		return fmt.Sprintf("method %s has a pointer receiver, register a pointer", method.Name())
+	}
+	want := strings.TrimPrefix(types.TypeString(method.Type(), a.qualifier), "func")
+	if wrongType {
+		if obj, _, _ := types.LookupFieldOrMethod(t, true, a.pkg.Types, method.Name()); obj != nil {
+			got := strings.TrimPrefix(types.TypeString(obj.Type(), a.qualifier), "func")
+			return fmt.Sprintf("method %s%s, want %s%s", method.Name(), got, method.Name(), want)
+		}
+	}
+	return fmt.Sprintf("missing method %s%s", method.Name(), want)
+}
+
+// isPluginFunc reports whether call calls the function of the plugin package
+func (a *analysis) isPluginFunc(call *ast.CallExpr, name string) bool {
+	fn, ok := typeutil.Callee(a.pkg.TypesInfo, call).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == pluginPkgPath && fn.Name() == name
+}
+
+// importedPackage returns the package the plugin imports by path, directly
+// or not
+func (a *analysis) importedPackage(path string) *types.Package {
+	seen := map[*types.Package]bool{}
+	var find func(p *types.Package) *types.Package
+	find = func(p *types.Package) *types.Package {
+		if p.Path() == path {
+			return p
+		}
+		seen[p] = true
+		for _, imp := range p.Imports() {
+			if !seen[imp] {
+				if found := find(imp); found != nil {
+					return found
+				}
+			}
+		}
+		return nil
+	}
+	return find(a.pkg.Types)
+}
+
+// checkTODOs flags the TODO comments of the generated stubs, the code next
+// to them is a placeholder
+func (a *analysis) checkTODOs() {
+	for _, file := range a.pkg.Syntax {
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+				if templateTODO.MatchString(text) {
+					a.report(c.Pos(), "todo", SeverityWarning, "template stub left: %s", text)
+				}
+			}
+		}
+	}
+}
+
+// checkDebugOutput flags calls that print to stdout, plugins log instead
+func (a *analysis) checkDebugOutput() {
+	for _, file := range a.pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			var name string
+			switch callee := typeutil.Callee(a.pkg.TypesInfo, call).(type) {
+			case *types.Func:
+				if callee.Pkg() != nil {
+					name = callee.Pkg().Path() + "." + callee.Name()
+				}
+			case *types.Builtin:
+				name = callee.Name()
+			}
+			if debugPrints[name] {
+				a.report(call.Pos(), "debug", SeverityWarning,
+					"%s writes to Answer's stdout, log it or remove it", name)
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The fixtures in testdata/plugins build against a stand-in for Answer's
+// plugin package, testdata/answer, so the tests don't need the network.
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		check    string
+		severity Severity
+		pos      string
+		message  string
+	}{
+		{"clean", "", "", "", ""},
+		{"noregister", "register", SeverityError, "plugin.go:1:9", "no init function calls plugin.Register"},
+		{"signature", "interface", SeverityError, "plugin.go:8:18",
+			"method FilterText(text string) bool, want FilterText(text string) (err error)"},
+		{"pointer", "interface", SeverityError, "plugin.go:8:18",
+			"method FilterText has a pointer receiver, register a pointer"},
+		{"todo", "todo", SeverityWarning, "plugin.go:16:2", "template stub left: TODO: Implement the text check"},
+		{"printf", "debug", SeverityWarning, "plugin.go:20:2", "fmt.Printf writes to Answer's stdout"},
+		{"sidebar", "interface", SeverityError, "plugin.go:8:18",
+			"*Sidebar doesn't implement plugin.Sidebar, which type sidebar of info.yaml needs: missing method GetSidebarConfig"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			diagnostics, err := Analyze(filepath.Join("testdata", "plugins", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.check == "" {
+				if len(diagnostics) != 0 {
+					t.Fatalf("got %+v, want no findings", diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 1 {
+				t.Fatalf("got %+v, want one finding", diagnostics)
+			}
+			d := diagnostics[0]
+			if d.Check != tt.check || d.Severity != tt.severity {
+				t.Errorf("got a %s %s finding, want a %s %s one", d.Severity, d.Check, tt.severity, tt.check)
+			}
+			if !strings.HasSuffix(d.Pos, filepath.Join(tt.fixture, tt.pos)) {
+				t.Errorf("finding at %s, want %s", d.Pos, tt.pos)
+			}
+			if !strings.Contains(d.Message, tt.message) {
+				t.Errorf("message %q, want it to contain %q", d.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestInterfaces(t *testing.T) {
+	for typ, name := range map[string]string{
+		"filter":  "Filter",
+		"render":  "Render",
+		"sidebar": "Sidebar",
+		"route":   "",
+	} {
+		if got, ok := interfaces[typ]; !ok || got != name {
+			t.Errorf("type %s maps to %q, want %q", typ, got, name)
+		}
+	}
+}
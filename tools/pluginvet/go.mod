@@ -0,0 +1,13 @@
+module github.com/answerdev/create-answer-plugin/tools/pluginvet
+
+go 1.25.0
+
+require (
+	golang.org/x/tools v0.47.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+)
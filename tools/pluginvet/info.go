@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pluginPkgPath is the package of the interfaces plugins implement
+const pluginPkgPath = "github.com/apache/answer/plugin"
+
+// interfaces maps the types of info.yaml to the interface of the plugin
+// package Answer tells them apart by. Types mapped to "" only need
+// plugin.Base, Answer finds their UI through info.yaml.
+var interfaces = map[string]string{
+	"cache":        "Cache",
+	"captcha":      "Captcha",
+	"connector":    "Connector",
+	"editor":       "",
+	"embed":        "Embed",
+	"filter":       "Filter",
+	"importer":     "Importer",
+	"kv-storage":   "KVStorage",
+	"mcp-tool":     "Agent",
+	"notification": "Notification",
+	"render":       "Render",
+	"reviewer":     "Reviewer",
+	"route":        "",
+	"search":       "Search",
+	"sidebar":      "Sidebar",
+	"storage":      "Storage",
+	"user-center":  "UserCenter",
+}
+
+// info is the part of info.yaml the checks use
+type info struct {
+	Path     string `yaml:"-"`
+	SlugName string `yaml:"slug_name"`
+	Type     string `yaml:"type"`
+}
+
+func readInfo(dir string) (*info, error) {
+	path := filepath.Join(dir, "info.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	i := &info{Path: path}
+	if err := yaml.Unmarshal(data, i); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if i.Type == "" {
+		return nil, fmt.Errorf("%s: no type", path)
+	}
+	return i, nil
+}
+
+// types returns the declared types, a composite plugin lists several
+// separated by commas
+func (i *info) types() []string {
+	var types []string
+	for _, t := range strings.Split(i.Type, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
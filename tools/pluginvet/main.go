@@ -0,0 +1,74 @@
+// Command pluginvet checks the Go package of an Answer plugin for what the
+// compiler doesn't: that its init function registers it with
+// plugin.Register, that the registered value implements the plugin
+// interfaces of the types its info.yaml declares, and that no template TODO
+// stub or fmt.Print debug output is left in it.
+//
+// Usage:
+//
+//	pluginvet [-json] DIR
+//
+// DIR holds the plugin's package and its info.yaml. The package is loaded
+// with go/packages, against the Answer version its go.mod requires.
+// Findings are printed as file:line:col: check: message, or as a JSON array
+// with -json. Errors are what keeps Answer from using the plugin, warnings
+// what's left to finish. The exit status is 1 when the package can't be
+// loaded, and 2 when there are errors, unless -json is given.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const usage = `usage: pluginvet [-json] DIR
+`
+
+func main() {
+	code, err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
+
+func run(args []string) (int, error) {
+	flags := flag.NewFlagSet("pluginvet", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the findings as a JSON array")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	if err := flags.Parse(args); err != nil {
+		return 1, err
+	}
+	if flags.NArg() != 1 {
+		return 1, errors.New(usage)
+	}
+
+	diagnostics, err := Analyze(flags.Arg(0))
+	if err != nil {
+		return 1, err
+	}
+
+	if *asJSON {
+		if diagnostics == nil {
+			diagnostics = []Diagnostic{}
+		}
+		out, err := json.MarshalIndent(diagnostics, "", "  ")
+		if err != nil {
+			return 1, err
+		}
+		fmt.Println(string(out))
+		return 0, nil
+	}
+
+	code := 0
+	for _, d := range diagnostics {
+		fmt.Printf("%s: %s: %s: %s\n", d.Pos, d.Severity, d.Check, d.Message)
+		if d.Severity == SeverityError {
+			code = 2
+		}
+	}
+	return code, nil
+}
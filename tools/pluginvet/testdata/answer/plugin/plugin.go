@@ -0,0 +1,39 @@
+// Package plugin is the part of Answer's plugin package the fixtures use
+package plugin
+
+type Translator struct {
+	Key string
+}
+
+func MakeTranslator(key string) Translator {
+	return Translator{Key: key}
+}
+
+type Info struct {
+	Name        Translator
+	SlugName    string
+	Description Translator
+	Author      string
+	Version     string
+	Link        string
+}
+
+type Base interface {
+	Info() Info
+}
+
+func Register(p Base) {}
+
+type Filter interface {
+	Base
+	FilterText(text string) (err error)
+}
+
+type SidebarConfig struct {
+	LinksText string `json:"links_text"`
+}
+
+type Sidebar interface {
+	Base
+	GetSidebarConfig() (sidebarConfig *SidebarConfig, err error)
+}
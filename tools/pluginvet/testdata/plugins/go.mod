@@ -0,0 +1,7 @@
+module example.com/plugins
+
+go 1.23.0
+
+require github.com/apache/answer v1.7.0
+
+replace github.com/apache/answer => ../answer
@@ -0,0 +1,13 @@
+package noregister
+
+import "github.com/apache/answer/plugin"
+
+type Filter struct{}
+
+func (f *Filter) Info() plugin.Info {
+	return plugin.Info{SlugName: "noregister"}
+}
+
+func (f *Filter) FilterText(text string) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+package printf
+
+import (
+	"fmt"
+
+	"github.com/apache/answer/plugin"
+)
+
+type Filter struct{}
+
+func init() {
+	plugin.Register(&Filter{})
+}
+
+func (f *Filter) Info() plugin.Info {
+	return plugin.Info{SlugName: "printf"}
+}
+
+func (f *Filter) FilterText(text string) error {
+	fmt.Printf("checking %q\n", text)
+	return nil
+}
@@ -0,0 +1,13 @@
+package sidebar
+
+import "github.com/apache/answer/plugin"
+
+type Sidebar struct{}
+
+func init() {
+	plugin.Register(&Sidebar{})
+}
+
+func (s *Sidebar) Info() plugin.Info {
+	return plugin.Info{SlugName: "sidebar"}
+}
@@ -0,0 +1,17 @@
+package signature
+
+import "github.com/apache/answer/plugin"
+
+type Filter struct{}
+
+func init() {
+	plugin.Register(&Filter{})
+}
+
+func (f *Filter) Info() plugin.Info {
+	return plugin.Info{SlugName: "signature"}
+}
+
+func (f *Filter) FilterText(text string) bool {
+	return true
+}